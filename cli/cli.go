@@ -7,6 +7,7 @@ import (
 
 	"github.com/latitudesh/lsh/client"
 	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
 	"github.com/latitudesh/lsh/internal/version"
 
 	"github.com/go-openapi/runtime"
@@ -81,6 +82,16 @@ func MakeRootCmd(rootCmd *cobra.Command) (*cobra.Command, error) {
 	// configure config location
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file path")
 
+	// complete and validate the flags before any request, then accept hostnames, names, slugs and
+	// ID prefixes wherever an ID is expected, also when typed at the prompt
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		cmdflag.Prepare(cmd)
+		return resolveIdentifierFlags(cmd, args)
+	}
+	cmdflag.ResolvePrompted = func(cmd *cobra.Command) error {
+		return resolveIdentifierFlags(cmd, nil)
+	}
+
 	// register security flags
 	if err := registerAuthInoWriterFlags(rootCmd); err != nil {
 		return nil, err
@@ -99,6 +110,12 @@ func MakeRootCmd(rootCmd *cobra.Command) (*cobra.Command, error) {
 	}
 	rootCmd.AddCommand(operationUpdateCmd)
 
+	operationUICmd, err := makeOperationUICmd()
+	if err != nil {
+		return nil, err
+	}
+	rootCmd.AddCommand(operationUICmd)
+
 	operationGroupAPIKeysCmd, err := makeOperationGroupAPIKeysCmd()
 	if err != nil {
 		return nil, err
@@ -141,6 +158,30 @@ func MakeRootCmd(rootCmd *cobra.Command) (*cobra.Command, error) {
 	}
 	rootCmd.AddCommand(operationGroupVolumeCmd)
 
+	operationGroupStorageCmd, err := makeOperationGroupStorageCmd()
+	if err != nil {
+		return nil, err
+	}
+	rootCmd.AddCommand(operationGroupStorageCmd)
+
+	operationGroupFilesystemCmd, err := makeOperationGroupFilesystemCmd()
+	if err != nil {
+		return nil, err
+	}
+	rootCmd.AddCommand(operationGroupFilesystemCmd)
+
+	operationGroupInventoryCmd, err := makeOperationGroupInventoryCmd()
+	if err != nil {
+		return nil, err
+	}
+	rootCmd.AddCommand(operationGroupInventoryCmd)
+
+	operationGroupExportCmd, err := makeOperationGroupExportCmd()
+	if err != nil {
+		return nil, err
+	}
+	rootCmd.AddCommand(operationGroupExportCmd)
+
 	// add cobra completion
 	rootCmd.AddCommand(makeGenCompletionCmd())
 
@@ -328,6 +369,12 @@ func makeOperationGroupServersCmd() (*cobra.Command, error) {
 	}
 	operationGroupServersCmd.AddCommand(operationServerReinstallCmd)
 
+	operationServersSSHConfigCmd, err := makeOperationServersSSHConfigCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupServersCmd.AddCommand(operationServersSSHConfigCmd)
+
 	return operationGroupServersCmd, nil
 }
 
@@ -421,7 +468,7 @@ func makeOperationGroupVolumeCmd() (*cobra.Command, error) {
 	operationGroupVolumeCmd := &cobra.Command{
 		Use:   "volume",
 		Short: "Manage volumes",
-		Long:  `Commands to manage volume operations such as listing, mounting, creating, and deleting volumes`,
+		Long:  `Commands to manage volume operations such as listing, showing, creating, mounting, unmounting and deleting volumes, growing the filesystem of a resized volume, managing the NQNs of this server and checking the health of its volumes`,
 	}
 
 	operationVolumeListCmd, err := makeOperationVolumeListCmd()
@@ -442,6 +489,18 @@ func makeOperationGroupVolumeCmd() (*cobra.Command, error) {
 	}
 	operationGroupVolumeCmd.AddCommand(operationVolumeMountCmd)
 
+	operationVolumeUnmountCmd, err := makeOperationVolumeUnmountCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupVolumeCmd.AddCommand(operationVolumeUnmountCmd)
+
+	operationVolumeStatusCmd, err := makeOperationVolumeStatusCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupVolumeCmd.AddCommand(operationVolumeStatusCmd)
+
 	operationVolumeCreateCmd, err := makeOperationVolumeCreateCmd()
 	if err != nil {
 		return nil, err
@@ -454,5 +513,162 @@ func makeOperationGroupVolumeCmd() (*cobra.Command, error) {
 	}
 	operationGroupVolumeCmd.AddCommand(operationVolumeDeleteCmd)
 
+	operationVolumeResizeCmd, err := makeOperationVolumeResizeCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupVolumeCmd.AddCommand(operationVolumeResizeCmd)
+
+	operationGroupVolumeNQNCmd, err := makeOperationGroupVolumeNQNCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupVolumeCmd.AddCommand(operationGroupVolumeNQNCmd)
+
 	return operationGroupVolumeCmd, nil
 }
+
+func makeOperationGroupFilesystemCmd() (*cobra.Command, error) {
+	operationGroupFilesystemCmd := &cobra.Command{
+		Use:   "filesystem",
+		Short: "Manage filesystems",
+		Long:  `Commands to manage shared filesystems and mount them on servers over NFS`,
+	}
+
+	operationFilesystemListCmd, err := makeOperationFilesystemListCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupFilesystemCmd.AddCommand(operationFilesystemListCmd)
+
+	operationFilesystemGetCmd, err := makeOperationFilesystemGetCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupFilesystemCmd.AddCommand(operationFilesystemGetCmd)
+
+	operationFilesystemCreateCmd, err := makeOperationFilesystemCreateCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupFilesystemCmd.AddCommand(operationFilesystemCreateCmd)
+
+	operationFilesystemUpdateCmd, err := makeOperationFilesystemUpdateCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupFilesystemCmd.AddCommand(operationFilesystemUpdateCmd)
+
+	operationFilesystemDeleteCmd, err := makeOperationFilesystemDeleteCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupFilesystemCmd.AddCommand(operationFilesystemDeleteCmd)
+
+	operationFilesystemMountCmd, err := makeOperationFilesystemMountCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupFilesystemCmd.AddCommand(operationFilesystemMountCmd)
+
+	return operationGroupFilesystemCmd, nil
+}
+
+func makeOperationGroupStorageCmd() (*cobra.Command, error) {
+	operationGroupStorageCmd := &cobra.Command{
+		Use:   "storage",
+		Short: "Manage object storage",
+		Long:  `Commands to manage storage products other than NVMe-oF block volumes (see 'lsh volume')`,
+	}
+
+	operationGroupObjectStorageCmd, err := makeOperationGroupObjectStorageCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupStorageCmd.AddCommand(operationGroupObjectStorageCmd)
+
+	return operationGroupStorageCmd, nil
+}
+
+func makeOperationGroupObjectStorageCmd() (*cobra.Command, error) {
+	operationGroupObjectStorageCmd := &cobra.Command{
+		Use:   "object",
+		Short: "Manage S3-compatible object storage",
+		Long:  `Commands to configure S3 clients for object storage`,
+	}
+
+	// Buckets and access keys are managed from the dashboard until the API exposes them
+	operationObjectStorageConfigCmd, err := makeOperationObjectStorageConfigCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupObjectStorageCmd.AddCommand(operationObjectStorageConfigCmd)
+
+	return operationGroupObjectStorageCmd, nil
+}
+
+func makeOperationGroupVolumeNQNCmd() (*cobra.Command, error) {
+	operationGroupVolumeNQNCmd := &cobra.Command{
+		Use:   "nqn",
+		Short: "Manage the NQN of this server",
+		Long:  `Commands to show, generate and set the host NQN (NVMe Qualified Name) of this server, and to authorize NQNs on volume storages`,
+	}
+
+	operationVolumeNQNShowCmd, err := makeOperationVolumeNQNShowCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupVolumeNQNCmd.AddCommand(operationVolumeNQNShowCmd)
+
+	operationVolumeNQNGenerateCmd, err := makeOperationVolumeNQNGenerateCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupVolumeNQNCmd.AddCommand(operationVolumeNQNGenerateCmd)
+
+	operationVolumeNQNSetCmd, err := makeOperationVolumeNQNSetCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupVolumeNQNCmd.AddCommand(operationVolumeNQNSetCmd)
+
+	operationVolumeNQNAuthorizeCmd, err := makeOperationVolumeNQNAuthorizeCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupVolumeNQNCmd.AddCommand(operationVolumeNQNAuthorizeCmd)
+
+	return operationGroupVolumeNQNCmd, nil
+}
+
+func makeOperationGroupInventoryCmd() (*cobra.Command, error) {
+	operationGroupInventoryCmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Export server inventories",
+		Long:  `Commands to export your servers as inventories for configuration management tools`,
+	}
+
+	operationInventoryAnsibleCmd, err := makeOperationInventoryAnsibleCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupInventoryCmd.AddCommand(operationInventoryAnsibleCmd)
+
+	return operationGroupInventoryCmd, nil
+}
+
+func makeOperationGroupExportCmd() (*cobra.Command, error) {
+	operationGroupExportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export resources as infrastructure as code",
+		Long:  `Commands to export existing resources as configuration for infrastructure as code tools`,
+	}
+
+	operationExportTerraformCmd, err := makeOperationExportTerraformCmd()
+	if err != nil {
+		return nil, err
+	}
+	operationGroupExportCmd.AddCommand(operationExportTerraformCmd)
+
+	return operationGroupExportCmd, nil
+}
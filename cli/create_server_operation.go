@@ -52,27 +52,32 @@ func (o *CreateServerOperation) registerFlags(cmd *cobra.Command) {
 			Label:       "Hostname",
 			Description: "The server hostname",
 			Required:    true,
+			Validators:  []cmdflag.Validator{cmdflag.Hostname()},
 		},
 		&cmdflag.String{
-			Name:        "operating_system",
-			Label:       "Operating System",
-			Description: `Enum: ["ipxe","windows_server_2019_std_v1","ubuntu_22_04_x64_lts","debian_11","rockylinux_8","debian_10","rhel8","centos_7_4_x64","centos_8_x64","ubuntu_20_04_x64_lts","debian_12","ubuntu22_ml_in_a_box","windows2022"]. The operating system for the new server`,
-			Required:    true,
-			Options:     server.SupportedOperatingSystems,
+			Name:          "operating_system",
+			Label:         "Operating System",
+			Description:   `Enum: ["ipxe","windows_server_2019_std_v1","ubuntu_24_04_x64_lts","ubuntu_22_04_x64_lts","debian_11","rockylinux_8","debian_10","rhel8","centos_7_4_x64","centos_8_x64","ubuntu_20_04_x64_lts","debian_12","ubuntu22_ml_in_a_box","windows2022"]. The operating system for the new server`,
+			Required:      true,
+			Options:       server.SupportedOperatingSystems,
+			AllowUnlisted: true,
 		},
 		&cmdflag.String{
-			Name:        "plan",
-			Label:       "Plan",
-			Description: `Enum: ["c2-large-x86","c2-medium-x86","c2-small-x86","c3-large-x86","c3-medium-x86","c3-small-x86","c3-xlarge-x86","g3-large-x86","g3-medium-x86","g3-small-x86","g3-xlarge-x86","m3-large-x86","s2-small-x86","s3-large-x86"]. The plan to choose server from`,
-			Required:    true,
-			Options:     server.SupportedPlans,
+			Name:          "plan",
+			Label:         "Plan",
+			Description:   `Enum: ["c2-large-x86","c2-medium-x86","c2-small-x86","c3-large-x86","c3-medium-x86","c3-small-x86","c3-xlarge-x86","g3-large-x86","g3-medium-x86","g3-small-x86","g3-xlarge-x86","m3-large-x86","s2-small-x86","s3-large-x86"]. The plan to choose server from`,
+			Required:      true,
+			Options:       server.SupportedPlans,
+			AllowUnlisted: true,
 		},
 		&cmdflag.String{
-			Name:        "site",
-			Label:       "Site",
-			Description: `Enum: ["ASH","BGT","BUE","CHI","DAL","FRA","LAX","LON","MEX","MEX2","MIA","MIA2","NYC","SAN","SAN2","SAO","SAO2","SYD","TYO","TYO2"]. The site to deploy the server`,
-			Required:    true,
-			Options:     server.SupportedSites,
+			Name:          "site",
+			Label:         "Site",
+			Description:   `Enum: ["ASH","BGT","BUE","CHI","DAL","FRA","LAX","LON","MEX","MEX2","MIA","MIA2","NYC","SAN","SAN2","SAO","SAO2","SYD","TYO","TYO2"]. The site to deploy the server`,
+			Required:      true,
+			Options:       server.SupportedSites,
+			AllowUnlisted: true,
+			Env:           true,
 		},
 		&cmdflag.String{
 			Name:        "billing",
@@ -86,6 +91,7 @@ func (o *CreateServerOperation) registerFlags(cmd *cobra.Command) {
 			Label:       "iPXE URL",
 			Description: "URL where iPXE script is stored on, necessary for custom image deployments. This attribute is required when iPXE is selected as operating system.",
 			Required:    false,
+			Validators:  []cmdflag.Validator{cmdflag.Matches(`^https?://\S+$`, "an http(s) URL")},
 		},
 		&cmdflag.String{
 			Name:        "raid",
@@ -111,6 +117,7 @@ func (o *CreateServerOperation) registerFlags(cmd *cobra.Command) {
 			Label:       "Project",
 			Description: "The project (ID or Slug) to deploy the server",
 			Required:    true,
+			Env:         true,
 		},
 	}
 
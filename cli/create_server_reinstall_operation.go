@@ -3,11 +3,13 @@ package cli
 import (
 	"fmt"
 
+	"github.com/latitudesh/lsh/client"
 	"github.com/latitudesh/lsh/client/server_reinstall"
 	"github.com/latitudesh/lsh/cmd/lsh"
 	"github.com/latitudesh/lsh/internal/api/resource"
 	"github.com/latitudesh/lsh/internal/cmdflag"
 	"github.com/latitudesh/lsh/internal/utils"
+	"github.com/latitudesh/lsh/models"
 
 	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
@@ -27,13 +29,17 @@ func makeOperationServerReinstallCmd() (*cobra.Command, error) {
 type CreateServerReinstallOperation struct {
 	PathParamFlags      cmdflag.Flags
 	BodyAttributesFlags cmdflag.Flags
+	BulkFlags           serverBulkFlags
 }
 
 func (o *CreateServerReinstallOperation) Register() (*cobra.Command, error) {
 	cmd := &cobra.Command{
-		Use:    "reinstall",
-		Short:  "Reintall a server",
-		Long:   "Submit a reinstall request to a server.",
+		Use:   "reinstall",
+		Short: "Reintall a server",
+		Long: `Submit a reinstall request to a server.
+
+Use --selector or --all instead of --id to reinstall every matching server, e.g.:
+  lsh servers reinstall --selector tag=ci-runners --operating_system ubuntu_22_04_x64_lts`,
 		RunE:   o.run,
 		PreRun: o.preRun,
 	}
@@ -59,17 +65,19 @@ func (o *CreateServerReinstallOperation) registerFlags(cmd *cobra.Command) {
 
 	bodyAttributesFlagsSchema := &cmdflag.FlagsSchema{
 		&cmdflag.String{
-			Name:        "operating_system",
-			Label:       "Operating System",
-			Description: `Enum: ["ipxe","windows_server_2019_std_v1","ubuntu_22_04_x64_lts","debian_11","rockylinux_8","debian_10","rhel8","centos_7_4_x64","centos_8_x64","ubuntu_20_04_x64_lts","debian_12","ubuntu22_ml_in_a_box","windows2022"]. The operating system for the new server`,
-			Required:    false,
-			Options:     server.SupportedOperatingSystems,
+			Name:          "operating_system",
+			Label:         "Operating System",
+			Description:   `Enum: ["ipxe","windows_server_2019_std_v1","ubuntu_24_04_x64_lts","ubuntu_22_04_x64_lts","debian_11","rockylinux_8","debian_10","rhel8","centos_7_4_x64","centos_8_x64","ubuntu_20_04_x64_lts","debian_12","ubuntu22_ml_in_a_box","windows2022"]. The operating system for the new server`,
+			Required:      false,
+			Options:       server.SupportedOperatingSystems,
+			AllowUnlisted: true,
 		},
 		&cmdflag.String{
 			Name:        "hostname",
 			Label:       "",
 			Description: "The server hostname",
 			Required:    false,
+			Validators:  []cmdflag.Validator{cmdflag.Hostname()},
 		},
 		&cmdflag.StringSlice{
 			Name:        "ssh_keys",
@@ -95,16 +103,19 @@ func (o *CreateServerReinstallOperation) registerFlags(cmd *cobra.Command) {
 			Label:       "iPXE URL",
 			Description: "URL where iPXE script is stored on, necessary for custom image deployments.This attribute is required when iPXE is selected as operating system.",
 			Required:    false,
+			Validators:  []cmdflag.Validator{cmdflag.Matches(`^https?://\S+$`, "an http(s) URL")},
 		},
 	}
 
 	o.BodyAttributesFlags.Register(bodyAttributesFlagsSchema)
 	o.PathParamFlags.Register(pathParamsFlagsSchema)
-
+	o.BulkFlags.register(cmd)
 }
 
 func (o *CreateServerReinstallOperation) preRun(cmd *cobra.Command, args []string) {
-	o.PathParamFlags.PreRun(cmd, args)
+	if !o.BulkFlags.enabled() {
+		o.PathParamFlags.PreRun(cmd, args)
+	}
 	o.BodyAttributesFlags.PreRun(cmd, args)
 }
 
@@ -114,6 +125,10 @@ func (o *CreateServerReinstallOperation) run(cmd *cobra.Command, args []string)
 		return err
 	}
 
+	if o.BulkFlags.enabled() {
+		return o.runBulk(cmd, appCli)
+	}
+
 	params := server_reinstall.NewCreateServerReinstallParams()
 	o.PathParamFlags.AssignValues(params)
 	o.BodyAttributesFlags.AssignValues(params.Body.Data.Attributes)
@@ -139,3 +154,31 @@ func (o *CreateServerReinstallOperation) run(cmd *cobra.Command, args []string)
 	}
 	return nil
 }
+
+func (o *CreateServerReinstallOperation) runBulk(cmd *cobra.Command, appCli *client.LatitudeShAPI) error {
+	if cmd.Flags().Changed("hostname") {
+		return fmt.Errorf("--hostname cannot be used with --selector or --all")
+	}
+
+	attributes := server_reinstall.CreateServerReinstallParamsBodyDataAttributes{}
+	o.BodyAttributesFlags.AssignFlagValues(&attributes)
+
+	if swag.IsZero(attributes) {
+		fmt.Println("Skipped action: no params provided")
+		return nil
+	}
+
+	return runServerBulkAction(cmd, appCli, &o.BulkFlags, serverBulkAction{
+		Name:        "reinstall",
+		Destructive: true,
+		Run: func(server *models.ServerData) error {
+			params := server_reinstall.NewCreateServerReinstallParams()
+			params.ServerID = server.ID
+			serverAttributes := attributes
+			params.Body.Data.Attributes = &serverAttributes
+
+			_, err := appCli.ServerReinstall.CreateServerReinstall(params, nil)
+			return err
+		},
+	})
+}
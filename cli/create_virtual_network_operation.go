@@ -51,17 +51,20 @@ func (o *CreateVirtualNetworkOperation) registerFlags(cmd *cobra.Command) {
 			Required:    true,
 		},
 		&cmdflag.String{
-			Name:        "site",
-			Label:       "Site ID or slug",
-			Description: "Site ID or slug",
-			Options:     virtualNetwork.SupportedSites,
-			Required:    false,
+			Name:          "site",
+			Label:         "Site ID or slug",
+			Description:   "Site ID or slug",
+			Options:       virtualNetwork.SupportedSites,
+			AllowUnlisted: true,
+			Required:      false,
+			Env:           true,
 		},
 		&cmdflag.String{
 			Name:        "project",
 			Label:       "Project ID or Slug",
 			Description: "Project ID or Slug",
 			Required:    true,
+			Env:         true,
 		},
 	}
 
@@ -53,6 +53,7 @@ func (o *DeleteSSHKeyOperation) registerFlags(cmd *cobra.Command) {
 			Label:       "Project ID or Slug",
 			Description: "Project ID or Slug",
 			Required:    true,
+			Env:         true,
 		},
 	}
 
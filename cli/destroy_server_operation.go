@@ -5,6 +5,7 @@ import (
 	"github.com/latitudesh/lsh/cmd/lsh"
 	"github.com/latitudesh/lsh/internal/cmdflag"
 	"github.com/latitudesh/lsh/internal/utils"
+	"github.com/latitudesh/lsh/models"
 
 	"github.com/spf13/cobra"
 )
@@ -22,12 +23,17 @@ func makeOperationServersDestroyServerCmd() (*cobra.Command, error) {
 
 type DestroyServerOperation struct {
 	PathParamFlags cmdflag.Flags
+	BulkFlags      serverBulkFlags
 }
 
 func (o *DestroyServerOperation) Register() (*cobra.Command, error) {
 	cmd := &cobra.Command{
-		Use:    "destroy",
-		Short:  "Delete a server",
+		Use:   "destroy",
+		Short: "Delete a server",
+		Long: `Delete a server.
+
+Use --selector or --all instead of --id to delete every matching server, e.g.:
+  lsh servers destroy --selector project=staging,status=off`,
 		RunE:   o.run,
 		PreRun: o.preRun,
 	}
@@ -50,9 +56,14 @@ func (o *DestroyServerOperation) registerFlags(cmd *cobra.Command) {
 	}
 
 	o.PathParamFlags.Register(schema)
+	o.BulkFlags.register(cmd)
 }
 
 func (o *DestroyServerOperation) preRun(cmd *cobra.Command, args []string) {
+	if o.BulkFlags.enabled() {
+		return
+	}
+
 	o.PathParamFlags.PreRun(cmd, args)
 }
 
@@ -62,6 +73,18 @@ func (o *DestroyServerOperation) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if o.BulkFlags.enabled() {
+		return runServerBulkAction(cmd, appCli, &o.BulkFlags, serverBulkAction{
+			Name:        "destroy",
+			Destructive: true,
+			Run: func(server *models.ServerData) error {
+				params := servers.NewDestroyServerParams().WithID(server.ID)
+				_, err := appCli.Servers.DestroyServer(params, nil)
+				return err
+			},
+		})
+	}
+
 	params := servers.NewDestroyServerParams()
 	o.PathParamFlags.AssignValues(params)
 
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// ExitError ends lsh with Code, for the commands whose exit code is part of their result, such as
+// 'lsh update --check'. The result is printed by the command, not by cobra
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// exitWithCode returns the error ending cmd with code, or nil for 0
+func exitWithCode(cmd *cobra.Command, code int) error {
+	if code == 0 {
+		return nil
+	}
+
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return &ExitError{Code: code}
+}
@@ -0,0 +1,509 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/latitudesh/lsh/client/projects"
+	"github.com/latitudesh/lsh/client/servers"
+	"github.com/latitudesh/lsh/client/ssh_keys"
+	"github.com/latitudesh/lsh/client/virtual_networks"
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/latitudesh/lsh/internal/utils"
+	"github.com/latitudesh/lsh/models"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+var terraformNameInvalidChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+func makeOperationExportTerraformCmd() (*cobra.Command, error) {
+	operation := ExportTerraformOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type ExportTerraformOperation struct {
+	QueryParamFlags cmdflag.Flags
+	OptionsFlags    cmdflag.Flags
+}
+
+// terraformResource is a resource block followed by its import block
+type terraformResource struct {
+	Type       string
+	Name       string
+	ImportID   string
+	Attributes [][2]string
+}
+
+func (o *ExportTerraformOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "terraform",
+		Short: "Generate Terraform configuration for the resources of a project",
+		Long: `Generate Terraform resource blocks and import {} blocks for an existing project.
+
+The output covers the project itself, its servers, SSH keys, virtual networks and the
+tags they use, so resources created by hand or with lsh can be adopted by the
+Latitude.sh Terraform provider with "terraform plan" followed by "terraform apply".
+
+Example:
+  lsh export terraform --project my-project --write latitudesh.tf`,
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.registerFlags(cmd)
+
+	return cmd, nil
+}
+
+func (o *ExportTerraformOperation) registerFlags(cmd *cobra.Command) {
+	o.QueryParamFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.OptionsFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	queryParamsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "project",
+			Label:       "Project ID or Slug",
+			Description: "The project to export",
+			Required:    true,
+			Env:         true,
+		},
+	}
+
+	optionsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "write",
+			Label:       "Output File",
+			Description: "Write the configuration to this file instead of stdout",
+			Required:    false,
+		},
+	}
+
+	o.QueryParamFlags.Register(queryParamsSchema)
+	o.OptionsFlags.Register(optionsSchema)
+}
+
+func (o *ExportTerraformOperation) preRun(cmd *cobra.Command, args []string) {
+	o.QueryParamFlags.PreRun(cmd, args)
+	o.OptionsFlags.PreRun(cmd, args)
+}
+
+func (o *ExportTerraformOperation) run(cmd *cobra.Command, args []string) error {
+	project, _ := cmd.Flags().GetString("project")
+	writePath, _ := cmd.Flags().GetString("write")
+
+	if project == "" {
+		return fmt.Errorf("--project is required")
+	}
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		return nil
+	}
+
+	appCli, err := makeClient(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	projectResponse, err := appCli.Projects.GetProject(projects.NewGetProjectParams().WithIDOrSlug(project), nil)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	if projectResponse.Payload == nil || projectResponse.Payload.Data == nil {
+		return fmt.Errorf("project %s not found", project)
+	}
+	projectData := projectResponse.Payload.Data
+
+	serversParams := servers.NewGetServersParams()
+	serversParams.SetFilterProject(&projectData.ID)
+	serversResponse, err := appCli.Servers.GetServers(serversParams, nil)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	keysResponse, err := appCli.SSHKeys.GetProjectSSHKeys(ssh_keys.NewGetProjectSSHKeysParams().WithProjectIDOrSlug(projectData.ID), nil)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	vlansParams := virtual_networks.NewGetVirtualNetworksParams()
+	vlansParams.SetFilterProject(&projectData.ID)
+	vlansResponse, err := appCli.VirtualNetworks.GetVirtualNetworks(vlansParams, nil)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	var serverList []*models.ServerData
+	if serversResponse.Payload != nil {
+		serverList = serversResponse.Payload.Data
+	}
+	var keyList []*models.SSHKeyData
+	if keysResponse.Payload != nil {
+		keyList = keysResponse.Payload.Data
+	}
+	var vlanList []*models.VirtualNetwork
+	if vlansResponse.Payload != nil {
+		vlanList = vlansResponse.Payload.Data
+	}
+
+	tagList, err := fetchTerraformTags(projectData, serverList, keyList, vlanList)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	configuration := renderTerraform(buildTerraformResources(projectData, serverList, keyList, vlanList, tagList))
+
+	if writePath == "" {
+		fmt.Fprint(os.Stdout, configuration)
+		return nil
+	}
+
+	path, err := homedir.Expand(writePath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, []byte(configuration), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	fmt.Fprintf(os.Stdout, "✅ Terraform configuration written to %s\n", path)
+	fmt.Fprintf(os.Stdout, "Run 'terraform plan' to review the imports before applying them.\n")
+	return nil
+}
+
+// fetchTerraformTags returns the team tags referenced by the exported resources
+func fetchTerraformTags(project *models.Project, serverList []*models.ServerData, keyList []*models.SSHKeyData, vlanList []*models.VirtualNetwork) ([]terraformTag, error) {
+	used := make(map[string]bool)
+	collect := func(tags []*models.TagsIncude) {
+		for _, tag := range tags {
+			if tag != nil && tag.Id != "" {
+				used[tag.Id] = true
+			}
+		}
+	}
+
+	if project.Attributes != nil {
+		collect(project.Attributes.Tags)
+	}
+	for _, server := range serverList {
+		if server != nil && server.Attributes != nil {
+			collect(server.Attributes.Tags)
+		}
+	}
+	for _, key := range keyList {
+		if key != nil && key.Attributes != nil {
+			collect(key.Attributes.Tags)
+		}
+	}
+	for _, vlan := range vlanList {
+		if vlan != nil && vlan.Attributes != nil {
+			collect(vlan.Attributes.Tags)
+		}
+	}
+
+	if len(used) == 0 {
+		return nil, nil
+	}
+
+	response, err := lsh.NewClient().Tags.List(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	var tagList []terraformTag
+	if response.CustomTags != nil {
+		for _, tag := range response.CustomTags.Data {
+			if tag.ID == nil || !used[*tag.ID] || tag.Attributes == nil {
+				continue
+			}
+
+			tagList = append(tagList, terraformTag{
+				ID:          *tag.ID,
+				Name:        derefString(tag.Attributes.Name),
+				Description: derefString(tag.Attributes.Description),
+				Color:       derefString(tag.Attributes.Color),
+			})
+		}
+	}
+
+	return tagList, nil
+}
+
+// terraformTag holds the tag fields used by the latitudesh_tag resource
+type terraformTag struct {
+	ID          string
+	Name        string
+	Description string
+	Color       string
+}
+
+// buildTerraformResources maps API resources to their latitudesh provider resources
+func buildTerraformResources(project *models.Project, serverList []*models.ServerData, keyList []*models.SSHKeyData, vlanList []*models.VirtualNetwork, tagList []terraformTag) []terraformResource {
+	// Every emitted name is kept, so a suffixed name such as web_2 cannot clash with a
+	// resource named web_2
+	used := make(map[string]bool)
+	uniqueName := func(resourceType, value, fallback string) string {
+		base := terraformName(value)
+		if base == "" {
+			base = terraformName(fallback)
+		}
+
+		name := base
+		for n := 2; used[resourceType+"."+name]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[resourceType+"."+name] = true
+		return name
+	}
+
+	var resources []terraformResource
+
+	projectAttr := project.Attributes
+	if projectAttr == nil {
+		projectAttr = &models.ProjectAttributes{}
+	}
+	projectName := uniqueName("latitudesh_project", projectAttr.Slug, project.ID)
+	projectRef := fmt.Sprintf("latitudesh_project.%s.id", projectName)
+
+	resources = append(resources, terraformResource{
+		Type:     "latitudesh_project",
+		Name:     projectName,
+		ImportID: project.ID,
+		Attributes: [][2]string{
+			{"name", hclString(projectAttr.Name)},
+			{"description", hclString(projectAttr.Description)},
+			{"environment", hclString(projectAttr.Environment)},
+		},
+	})
+
+	tagRefs := make(map[string]string)
+	for _, tag := range tagList {
+		name := uniqueName("latitudesh_tag", tag.Name, tag.ID)
+		tagRefs[tag.ID] = fmt.Sprintf("latitudesh_tag.%s.id", name)
+
+		resources = append(resources, terraformResource{
+			Type:     "latitudesh_tag",
+			Name:     name,
+			ImportID: tag.ID,
+			Attributes: [][2]string{
+				{"name", hclString(tag.Name)},
+				{"description", hclString(tag.Description)},
+				{"color", hclString(tag.Color)},
+			},
+		})
+	}
+
+	tagsAttribute := func(tags []*models.TagsIncude) [][2]string {
+		var refs []string
+		for _, tag := range tags {
+			if tag == nil {
+				continue
+			}
+			if ref, ok := tagRefs[tag.Id]; ok {
+				refs = append(refs, ref)
+			}
+		}
+		if len(refs) == 0 {
+			return nil
+		}
+		return [][2]string{{"tags", "[" + strings.Join(refs, ", ") + "]"}}
+	}
+
+	for _, server := range serverList {
+		if server == nil || server.Attributes == nil {
+			continue
+		}
+		attr := server.Attributes
+
+		site := attr.Site
+		if attr.Region != nil && attr.Region.Site != nil && attr.Region.Site.Slug != "" {
+			site = attr.Region.Site.Slug
+		}
+
+		planSlug := ""
+		if attr.Plan != nil {
+			planSlug = attr.Plan.Name
+		}
+		osSlug := ""
+		if attr.OperatingSystem != nil {
+			osSlug = attr.OperatingSystem.Slug
+		}
+
+		attributes := [][2]string{
+			{"hostname", hclString(attr.Hostname)},
+			{"project", projectRef},
+			{"site", hclString(site)},
+			{"plan", hclString(planSlug)},
+			{"operating_system", hclString(osSlug)},
+		}
+		attributes = append(attributes, tagsAttribute(attr.Tags)...)
+
+		resources = append(resources, terraformResource{
+			Type:       "latitudesh_server",
+			Name:       uniqueName("latitudesh_server", attr.Hostname, server.ID),
+			ImportID:   server.ID,
+			Attributes: attributes,
+		})
+	}
+
+	for _, key := range keyList {
+		if key == nil || key.Attributes == nil {
+			continue
+		}
+		attr := key.Attributes
+
+		attributes := [][2]string{
+			{"name", hclString(attr.Name)},
+			{"public_key", hclString(strings.TrimSpace(attr.PublicKey))},
+		}
+		attributes = append(attributes, tagsAttribute(attr.Tags)...)
+
+		resources = append(resources, terraformResource{
+			Type:       "latitudesh_ssh_key",
+			Name:       uniqueName("latitudesh_ssh_key", attr.Name, key.ID),
+			ImportID:   key.ID,
+			Attributes: attributes,
+		})
+	}
+
+	for _, vlan := range vlanList {
+		if vlan == nil || vlan.Attributes == nil {
+			continue
+		}
+		attr := vlan.Attributes
+
+		site := ""
+		if attr.Region != nil && attr.Region.Site != nil {
+			site = attr.Region.Site.Slug
+		}
+
+		attributes := [][2]string{
+			{"description", hclString(attr.Description)},
+			{"site", hclString(site)},
+			{"project", projectRef},
+		}
+		attributes = append(attributes, tagsAttribute(attr.Tags)...)
+
+		resources = append(resources, terraformResource{
+			Type:       "latitudesh_virtual_network",
+			Name:       uniqueName("latitudesh_virtual_network", attr.Description, vlan.ID),
+			ImportID:   vlan.ID,
+			Attributes: attributes,
+		})
+	}
+
+	return resources
+}
+
+// renderTerraform writes the resource and import blocks as HCL
+func renderTerraform(resources []terraformResource) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by lsh export terraform.\n")
+	b.WriteString("# Review the plan before applying: attributes the API does not expose may need to be filled in.\n\n")
+	b.WriteString("terraform {\n")
+	b.WriteString("  required_providers {\n")
+	b.WriteString("    latitudesh = {\n")
+	b.WriteString("      source = \"latitudesh/latitudesh\"\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+
+	for _, resource := range resources {
+		width := 0
+		for _, attribute := range resource.Attributes {
+			if len(attribute[0]) > width {
+				width = len(attribute[0])
+			}
+		}
+
+		fmt.Fprintf(&b, "\nresource %q %q {\n", resource.Type, resource.Name)
+		for _, attribute := range resource.Attributes {
+			if attribute[1] == `""` {
+				continue
+			}
+			fmt.Fprintf(&b, "  %-*s = %s\n", width, attribute[0], attribute[1])
+		}
+		b.WriteString("}\n")
+
+		fmt.Fprintf(&b, "\nimport {\n")
+		fmt.Fprintf(&b, "  to = %s.%s\n", resource.Type, resource.Name)
+		fmt.Fprintf(&b, "  id = %s\n", hclString(resource.ImportID))
+		b.WriteString("}\n")
+	}
+
+	return b.String()
+}
+
+// terraformName builds a valid Terraform identifier such as "web_01"
+func terraformName(value string) string {
+	name := terraformNameInvalidChars.ReplaceAllString(strings.ToLower(value), "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return ""
+	}
+
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "r_" + name
+	}
+
+	return name
+}
+
+// hclString quotes a value as an HCL string literal, escaping template sequences. Only the escapes
+// of the HCL spec are used: \n, \r, \t, \", \\ and \u or \U for the other unprintable characters
+func hclString(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range value {
+		switch {
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case (r == '$' || r == '%') && strings.HasPrefix(value[i+1:], "{"):
+			// Doubled, ${ and %{ are literal instead of starting a template sequence
+			b.WriteRune(r)
+			b.WriteRune(r)
+		case unicode.IsPrint(r):
+			b.WriteRune(r)
+		case r > 0xFFFF:
+			fmt.Fprintf(&b, `\U%08x`, r)
+		default:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/latitudesh/lsh/models"
+)
+
+func TestRenderTerraform_RecordedServers(t *testing.T) {
+	appCli := newRecordedClient(t, map[string]string{"/servers": "testdata/servers.json"})
+
+	serverList, err := fetchInventoryServers(appCli, "acme-web")
+	if err != nil {
+		t.Fatalf("fetchInventoryServers() error = %v", err)
+	}
+
+	project := &models.Project{
+		ID: "proj_AW6Q2D9lqKLpr",
+		Attributes: &models.ProjectAttributes{
+			Name:        "Acme Web",
+			Slug:        "acme-web",
+			Environment: "Production",
+		},
+	}
+	keyList := []*models.SSHKeyData{
+		{ID: "ssh_1", Attributes: &models.SSHKeyDataAttributes{Name: "deploy", PublicKey: "ssh-ed25519 AAAA deploy\n"}},
+		{ID: "ssh_2", Attributes: &models.SSHKeyDataAttributes{Name: "deploy", PublicKey: "ssh-ed25519 BBBB deploy"}},
+	}
+	tagList := []terraformTag{{ID: "tag_web", Name: "web", Color: "#00FF00"}}
+
+	configuration := renderTerraform(buildTerraformResources(project, serverList, keyList, nil, tagList))
+
+	expected := []string{
+		`resource "latitudesh_project" "acme_web" {`,
+		`resource "latitudesh_server" "web_01" {`,
+		`  project          = latitudesh_project.acme_web.id`,
+		`  site             = "SAO2"`,
+		`  plan             = "c2-small-x86"`,
+		`  operating_system = "ubuntu_22_04_x64_lts"`,
+		`  tags             = [latitudesh_tag.web.id]`,
+		`resource "latitudesh_server" "db_01" {`,
+		`resource "latitudesh_ssh_key" "deploy" {`,
+		`resource "latitudesh_ssh_key" "deploy_2" {`,
+		`  public_key = "ssh-ed25519 AAAA deploy"`,
+		"import {\n  to = latitudesh_server.web_01\n  id = \"sv_2GmAlJ6BXlK1a\"\n}",
+		"import {\n  to = latitudesh_tag.web\n  id = \"tag_web\"\n}",
+	}
+
+	for _, want := range expected {
+		if !strings.Contains(configuration, want) {
+			t.Errorf("configuration is missing %q\n%s", want, configuration)
+		}
+	}
+
+	if got := strings.Count(configuration, "import {"); got != 6 {
+		t.Errorf("configuration has %d import blocks, want 6", got)
+	}
+}
+
+func TestTerraformNameAndHCLString(t *testing.T) {
+	names := map[string]string{
+		"web-01":         "web_01",
+		"Production API": "production_api",
+		"01-db":          "r_01_db",
+		"---":            "",
+	}
+	for input, want := range names {
+		if got := terraformName(input); got != want {
+			t.Errorf("terraformName(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	strs := map[string]string{
+		`echo ${HOME} "%{x}"`:         `"echo $${HOME} \"%%{x}\""`,
+		"C:\\keys\tdeploy\r\n":        `"C:\\keys\tdeploy\r\n"`,
+		"bell\x07 del\x7f nbsp\u00a0": `"bell\u0007 del\u007f nbsp\u00a0"`,
+		"São Paulo $ 100%":            `"São Paulo $ 100%"`,
+	}
+	for input, want := range strs {
+		if got := hclString(input); got != want {
+			t.Errorf("hclString(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestBuildTerraformResources_UniqueNames(t *testing.T) {
+	project := &models.Project{ID: "proj_1", Attributes: &models.ProjectAttributes{Slug: "acme"}}
+	keyList := []*models.SSHKeyData{
+		{ID: "ssh_1", Attributes: &models.SSHKeyDataAttributes{Name: "web"}},
+		{ID: "ssh_2", Attributes: &models.SSHKeyDataAttributes{Name: "web"}},
+		{ID: "ssh_3", Attributes: &models.SSHKeyDataAttributes{Name: "web_2"}},
+	}
+
+	seen := map[string]bool{}
+	for _, resource := range buildTerraformResources(project, nil, keyList, nil, nil) {
+		address := resource.Type + "." + resource.Name
+		if seen[address] {
+			t.Errorf("%s is emitted twice", address)
+		}
+		seen[address] = true
+	}
+	if len(seen) != 4 {
+		t.Errorf("emitted %v, want the project and 3 SSH keys", seen)
+	}
+}
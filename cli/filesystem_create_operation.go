@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/latitudesh/latitudesh-go-sdk/models/operations"
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/latitudesh/lsh/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func makeOperationFilesystemCreateCmd() (*cobra.Command, error) {
+	operation := FilesystemCreateOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type FilesystemCreateOperation struct {
+	BodyAttributesFlags cmdflag.Flags
+}
+
+func (o *FilesystemCreateOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:    "create",
+		Short:  "Create a filesystem",
+		Long:   "Create a shared filesystem in a project. The size defaults to 1500 GB.",
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.registerFlags(cmd)
+
+	return cmd, nil
+}
+
+func (o *FilesystemCreateOperation) registerFlags(cmd *cobra.Command) {
+	o.BodyAttributesFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	bodyAttributesSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "project",
+			Label:       "Project ID or Slug",
+			Description: "The project to create the filesystem in",
+			Required:    true,
+			Env:         true,
+		},
+		&cmdflag.String{
+			Name:        "name",
+			Label:       "Name",
+			Description: "The name of the filesystem",
+			Required:    true,
+		},
+		&cmdflag.Int64{
+			Name:        "size",
+			Label:       "Size (GB)",
+			Description: "The size of the filesystem in GB (default 1500)",
+			Required:    false,
+			Validators:  []cmdflag.Validator{cmdflag.AtLeast(1)},
+		},
+	}
+
+	o.BodyAttributesFlags.Register(bodyAttributesSchema)
+}
+
+func (o *FilesystemCreateOperation) preRun(cmd *cobra.Command, args []string) {
+	o.BodyAttributesFlags.PreRun(cmd, args)
+}
+
+func (o *FilesystemCreateOperation) run(cmd *cobra.Command, args []string) error {
+	project, _ := cmd.Flags().GetString("project")
+	name, _ := cmd.Flags().GetString("name")
+	size, _ := cmd.Flags().GetInt64("size")
+
+	if project == "" || name == "" {
+		return fmt.Errorf("--project and --name are required")
+	}
+
+	attributes := operations.PostStorageFilesystemsStorageAttributes{
+		Project: project,
+		Name:    name,
+	}
+	if cmd.Flags().Changed("size") {
+		if size <= 0 {
+			return fmt.Errorf("--size must be a positive number of GB")
+		}
+		attributes.SizeInGb = &size
+	}
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		return nil
+	}
+
+	client, err := newStorageClient()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "Creating filesystem %s...\n", name)
+	response, err := client.Storage.CreateFilesystem(context.Background(), operations.PostStorageFilesystemsStorageRequestBody{
+		Data: operations.PostStorageFilesystemsStorageData{
+			Type:       operations.PostStorageFilesystemsStorageTypeFilesystems,
+			Attributes: attributes,
+		},
+	})
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	if !lsh.Debug && response != nil && response.Object != nil {
+		utils.Render(newFilesystem(response.Object.Data).GetData())
+	}
+
+	return nil
+}
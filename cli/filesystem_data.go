@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	latitudeshgosdk "github.com/latitudesh/latitudesh-go-sdk"
+	"github.com/latitudesh/latitudesh-go-sdk/models/components"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/latitudesh/lsh/internal/output/table"
+	"github.com/latitudesh/lsh/internal/renderer"
+	"github.com/latitudesh/lsh/internal/utils"
+)
+
+type Filesystems struct {
+	Data []*Filesystem
+}
+
+func (m *Filesystems) GetData() []renderer.ResponseData {
+	var data []renderer.ResponseData
+
+	for _, v := range m.Data {
+		data = append(data, v)
+	}
+
+	return data
+}
+
+// Filesystem is a shared filesystem, decoded from the API response and rendered as returned
+type Filesystem struct {
+	ID         string               `json:"id"`
+	Attributes FilesystemAttributes `json:"attributes"`
+
+	raw json.RawMessage
+}
+
+type FilesystemAttributes struct {
+	Name      string          `json:"name"`
+	SizeInGb  int64           `json:"size_in_gb"`
+	CreatedAt string          `json:"created_at"`
+	Project   volumeReference `json:"project"`
+}
+
+// newFilesystem converts the filesystem returned by the SDK
+func newFilesystem(data *components.FilesystemData) *Filesystem {
+	filesystem := &Filesystem{}
+	if data == nil {
+		return filesystem
+	}
+
+	if data.ID != nil {
+		filesystem.ID = *data.ID
+	}
+
+	if attr := data.Attributes; attr != nil {
+		if attr.Name != nil {
+			filesystem.Attributes.Name = *attr.Name
+		}
+		if attr.SizeInGb != nil {
+			filesystem.Attributes.SizeInGb = *attr.SizeInGb
+		}
+		if attr.CreatedAt != nil {
+			filesystem.Attributes.CreatedAt = attr.CreatedAt.Format(time.RFC3339)
+		}
+		if project := attr.Project; project != nil {
+			switch {
+			case project.Slug != nil:
+				filesystem.Attributes.Project.Value = *project.Slug
+			case project.ID != nil:
+				filesystem.Attributes.Project.Value = *project.ID
+			}
+		}
+	}
+
+	return filesystem
+}
+
+func (m *Filesystem) UnmarshalJSON(data []byte) error {
+	type filesystem Filesystem
+	if err := json.Unmarshal(data, (*filesystem)(m)); err != nil {
+		return err
+	}
+
+	m.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON renders the filesystem as returned by the API
+func (m *Filesystem) MarshalJSON() ([]byte, error) {
+	if m.raw != nil {
+		return m.raw, nil
+	}
+
+	type filesystem Filesystem
+	return json.Marshal((*filesystem)(m))
+}
+
+func (m *Filesystem) GetData() []renderer.ResponseData {
+	return []renderer.ResponseData{m}
+}
+
+func (m *Filesystem) TableRow() table.Row {
+	attr := m.Attributes
+
+	return table.Row{
+		"id": table.Cell{
+			Label: "ID",
+			Value: table.String(m.ID),
+		},
+		"name": table.Cell{
+			Label: "Name",
+			Value: table.String(attr.Name),
+		},
+		"size": table.Cell{
+			Label: "Size (GB)",
+			Value: table.Int(attr.SizeInGb),
+		},
+		"project": table.Cell{
+			Label: "Project",
+			Value: table.String(attr.Project.Value),
+		},
+		"created_at": table.Cell{
+			Label: "Created At",
+			Value: table.String(attr.CreatedAt),
+		},
+	}
+}
+
+// fetchFilesystemData lists the filesystems, decoding the response body the SDK leaves unparsed
+func fetchFilesystemData(ctx context.Context, client *latitudeshgosdk.Latitudesh, filterProject *string) ([]*Filesystem, error) {
+	response, err := client.Storage.ListFilesystems(ctx, filterProject)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to fetch filesystems: %v", err))
+		utils.PrintError(err)
+		return nil, err
+	}
+
+	if response == nil || response.HTTPMeta.Response == nil {
+		printError("No response from API")
+		return nil, fmt.Errorf("failed to get response from API")
+	}
+
+	bodyBytes, err := io.ReadAll(response.HTTPMeta.Response.Body)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to read response body: %v", err))
+		return nil, err
+	}
+
+	var responseData struct {
+		Data []*Filesystem `json:"data"`
+	}
+
+	if err := json.Unmarshal(bodyBytes, &responseData); err != nil {
+		printError(fmt.Sprintf("Failed to parse response: %v", err))
+		return nil, err
+	}
+
+	return responseData.Data, nil
+}
+
+// findFilesystem returns the filesystem with the given ID, as the API has no endpoint to get one
+func findFilesystem(ctx context.Context, client *latitudeshgosdk.Latitudesh, filesystemID string) (*Filesystem, error) {
+	filesystems, err := fetchFilesystemData(ctx, client, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, filesystem := range filesystems {
+		if filesystem.ID == filesystemID {
+			return filesystem, nil
+		}
+	}
+
+	return nil, fmt.Errorf("filesystem with ID '%s' not found", filesystemID)
+}
+
+// filesystemIDFlag is the filesystem a command acts upon
+func filesystemIDFlag(description string) *cmdflag.String {
+	return &cmdflag.String{
+		Name:        "id",
+		Label:       "Filesystem ID",
+		Description: description,
+		Required:    true,
+	}
+}
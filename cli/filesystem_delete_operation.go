@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/latitudesh/lsh/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func makeOperationFilesystemDeleteCmd() (*cobra.Command, error) {
+	operation := FilesystemDeleteOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type FilesystemDeleteOperation struct {
+	PathParamFlags cmdflag.Flags
+}
+
+func (o *FilesystemDeleteOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:    "delete",
+		Short:  "Delete a filesystem",
+		Long:   "Delete a shared filesystem by ID. Warning: This action cannot be undone!",
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.PathParamFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.PathParamFlags.Register(&cmdflag.FlagsSchema{filesystemIDFlag("The ID of the filesystem to delete")})
+
+	return cmd, nil
+}
+
+func (o *FilesystemDeleteOperation) preRun(cmd *cobra.Command, args []string) {
+	o.PathParamFlags.PreRun(cmd, args)
+}
+
+func (o *FilesystemDeleteOperation) run(cmd *cobra.Command, args []string) error {
+	filesystemID, _ := cmd.Flags().GetString("id")
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		return nil
+	}
+
+	client, err := newStorageClient()
+	if err != nil {
+		return err
+	}
+
+	// Confirm deletion
+	fmt.Fprintf(os.Stdout, "⚠️  Warning: You are about to delete filesystem: %s\n", filesystemID)
+	fmt.Fprintf(os.Stdout, "This action cannot be undone. All data will be lost.\n\n")
+	fmt.Fprintf(os.Stdout, "Type 'yes' to confirm: ")
+
+	var confirmation string
+	fmt.Scanln(&confirmation)
+
+	if confirmation != "yes" {
+		fmt.Fprintf(os.Stdout, "Deletion cancelled.\n")
+		return nil
+	}
+
+	response, err := client.Storage.DeleteFilesystem(context.Background(), filesystemID)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	if !lsh.Debug && response != nil && response.HTTPMeta.Response != nil {
+		fmt.Fprintf(os.Stdout, "✅ Filesystem deleted successfully (Status: %s)\n", response.HTTPMeta.Response.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/latitudesh/lsh/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func makeOperationFilesystemGetCmd() (*cobra.Command, error) {
+	operation := FilesystemGetOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type FilesystemGetOperation struct {
+	PathParamFlags cmdflag.Flags
+}
+
+func (o *FilesystemGetOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:    "get",
+		Short:  "Get filesystem details",
+		Long:   "Get detailed information about a shared filesystem",
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.PathParamFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.PathParamFlags.Register(&cmdflag.FlagsSchema{filesystemIDFlag("The ID of the filesystem to retrieve")})
+
+	return cmd, nil
+}
+
+func (o *FilesystemGetOperation) preRun(cmd *cobra.Command, args []string) {
+	o.PathParamFlags.PreRun(cmd, args)
+}
+
+func (o *FilesystemGetOperation) run(cmd *cobra.Command, args []string) error {
+	filesystemID, _ := cmd.Flags().GetString("id")
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		return nil
+	}
+
+	client, err := newStorageClient()
+	if err != nil {
+		return err
+	}
+
+	// The API has no endpoint to get a single filesystem, so the list is filtered by ID
+	filesystem, err := findFilesystem(context.Background(), client, filesystemID)
+	if err != nil {
+		return err
+	}
+
+	if !lsh.Debug {
+		utils.Render(filesystem.GetData())
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/latitudesh/lsh/internal/renderer"
+	"github.com/latitudesh/lsh/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func makeOperationFilesystemListCmd() (*cobra.Command, error) {
+	operation := FilesystemListOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type FilesystemListOperation struct {
+	QueryParamFlags cmdflag.Flags
+}
+
+func (o *FilesystemListOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:    "list",
+		Short:  "List filesystems",
+		Long:   "List the shared filesystems of your team, optionally filtered by project",
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.registerFlags(cmd)
+
+	return cmd, nil
+}
+
+func (o *FilesystemListOperation) registerFlags(cmd *cobra.Command) {
+	o.QueryParamFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	queryParamsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "project",
+			Label:       "Project ID or Slug",
+			Description: "Filter filesystems by project",
+			Required:    false,
+			Env:         true,
+		},
+	}
+
+	o.QueryParamFlags.Register(queryParamsSchema)
+
+	utils.RegisterWatchFlag(cmd)
+}
+
+func (o *FilesystemListOperation) preRun(cmd *cobra.Command, args []string) {
+	o.QueryParamFlags.PreRun(cmd, args)
+}
+
+func (o *FilesystemListOperation) run(cmd *cobra.Command, args []string) error {
+	project, _ := cmd.Flags().GetString("project")
+
+	interval, err := utils.WatchInterval(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		return nil
+	}
+
+	client, err := newStorageClient()
+	if err != nil {
+		return err
+	}
+
+	var filterProject *string
+	if project != "" {
+		filterProject = &project
+	}
+
+	if interval > 0 {
+		return renderer.Watch("Filesystems", interval, func() ([]renderer.ResponseData, error) {
+			data, err := fetchFilesystemData(context.Background(), client, filterProject)
+			if err != nil {
+				return nil, err
+			}
+			filesystems := &Filesystems{Data: data}
+			return filesystems.GetData(), nil
+		})
+	}
+
+	data, err := fetchFilesystemData(context.Background(), client, filterProject)
+	if err != nil {
+		return err
+	}
+
+	if !lsh.Debug {
+		filesystems := &Filesystems{Data: data}
+		utils.Render(filesystems.GetData())
+	}
+
+	return nil
+}
@@ -0,0 +1,363 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/spf13/cobra"
+)
+
+// nfsVersions are the NFS protocol versions filesystem mount can use
+var nfsVersions = []string{"4.2", "4.1", "4", "3"}
+
+const defaultNFSVersion = "4.1"
+
+func makeOperationFilesystemMountCmd() (*cobra.Command, error) {
+	operation := FilesystemMountOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type FilesystemMountOperation struct {
+	PathParamFlags cmdflag.Flags
+	OptionsFlags   cmdflag.Flags
+}
+
+func (o *FilesystemMountOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "mount",
+		Short: "Mount a filesystem on a server over NFS",
+		Long: `Mount a shared filesystem on a server over NFS. This command will:
+  1. Check that the filesystem exists
+  2. Install the NFS client (nfs-common or nfs-utils) if it is missing
+  3. Test connectivity to the NFS server
+  4. Mount the export on --mountpoint
+
+The API does not expose the NFS endpoint of a filesystem yet: pass the server address
+and export path shown in the dashboard with --server and --export.
+
+--persist mounts the filesystem again on boot through an /etc/fstab entry;
+--persist=false removes that entry.
+
+This command must be run with sudo/root privileges on the target server.
+
+Example:
+  sudo lsh filesystem mount --id fs_abc123 --server 10.8.0.10 --export /fs_abc123 --mountpoint /shared --persist`,
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.registerFlags(cmd)
+
+	return cmd, nil
+}
+
+func (o *FilesystemMountOperation) registerFlags(cmd *cobra.Command) {
+	o.PathParamFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.OptionsFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	pathParamsSchema := &cmdflag.FlagsSchema{
+		filesystemIDFlag("The ID of the filesystem to mount"),
+	}
+
+	optionsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "mountpoint",
+			Label:       "Mountpoint",
+			Description: "Directory to mount the filesystem on (created if missing)",
+			Required:    true,
+		},
+		&cmdflag.String{
+			Name:        "server",
+			Label:       "NFS Server",
+			Description: "The address of the NFS server of the filesystem",
+			Required:    true,
+		},
+		&cmdflag.String{
+			Name:        "export",
+			Label:       "Export Path",
+			Description: "The export path of the filesystem on the NFS server",
+			Required:    true,
+		},
+		&cmdflag.String{
+			Name:        "nfs-version",
+			Label:       "NFS Version",
+			Description: "The NFS protocol version (default 4.1)",
+			Required:    false,
+			Options:     nfsVersions,
+		},
+		&cmdflag.String{
+			Name:        "mount-options",
+			Label:       "Mount Options",
+			Description: "Extra comma-separated NFS mount options (e.g. noatime,nconnect=4)",
+			Required:    false,
+		},
+		&cmdflag.Bool{
+			Name:        "persist",
+			Label:       "Persist",
+			Description: "Mount the filesystem again on boot through /etc/fstab (--persist=false removes the entry)",
+			Required:    false,
+		},
+	}
+
+	o.PathParamFlags.Register(pathParamsSchema)
+	o.OptionsFlags.Register(optionsSchema)
+}
+
+func (o *FilesystemMountOperation) preRun(cmd *cobra.Command, args []string) {
+	o.PathParamFlags.PreRun(cmd, args)
+	o.OptionsFlags.PreRun(cmd, args)
+}
+
+// filesystemPersistenceMarker is the comment written above the fstab entry lsh manages for a filesystem
+func filesystemPersistenceMarker(filesystemID string) string {
+	return "# lsh filesystem " + filesystemID
+}
+
+// nfsMount describes an NFS export mounted on the server
+type nfsMount struct {
+	Server     string
+	Export     string
+	Mountpoint string
+	Version    string
+	Options    string
+}
+
+// Source is the export as mount and /proc/mounts name it
+func (m nfsMount) Source() string {
+	return m.Server + ":" + m.Export
+}
+
+func (m nfsMount) mountOptions() string {
+	options := "vers=" + m.Version
+	if m.Options != "" {
+		options += "," + m.Options
+	}
+	return options
+}
+
+// fstabEntry mounts the export once the network is up, without blocking boot when it is unreachable
+func (m nfsMount) fstabEntry() string {
+	return fmt.Sprintf("%s %s nfs %s,_netdev,nofail 0 0", m.Source(), m.Mountpoint, m.mountOptions())
+}
+
+// installNFSClient installs the NFS client package of the distribution
+func (h *volumeHost) installNFSClient() error {
+	printWarning("The NFS client is not installed. Attempting to install...")
+
+	managers := []struct {
+		command string
+		pkg     string
+	}{
+		{command: "apt", pkg: "nfs-common"},
+		{command: "dnf", pkg: "nfs-utils"},
+		{command: "yum", pkg: "nfs-utils"},
+	}
+
+	for _, manager := range managers {
+		if _, err := h.exec.LookPath(manager.command); err != nil {
+			continue
+		}
+
+		printStatus(fmt.Sprintf("Running: %s install -y %s", manager.command, manager.pkg))
+		if manager.command == "apt" {
+			if _, err := h.exec.Run("apt", "update"); err != nil {
+				return fmt.Errorf("failed to update apt: %w", err)
+			}
+		}
+		if output, err := h.exec.Run(manager.command, "install", "-y", manager.pkg); err != nil {
+			return fmt.Errorf("failed to install %s via %s: %s", manager.pkg, manager.command, output)
+		}
+
+		printStatus(fmt.Sprintf("✓ %s installed successfully via %s", manager.pkg, manager.command))
+		return nil
+	}
+
+	return fmt.Errorf("could not detect package manager (apt/yum/dnf). Please install the NFS client manually")
+}
+
+// checkNFSPrerequisites makes sure mount can handle NFS exports
+func (h *volumeHost) checkNFSPrerequisites() error {
+	printStatus("Checking prerequisites...")
+
+	if _, err := h.exec.LookPath("mount.nfs"); err == nil {
+		printStatus("✓ NFS client is installed")
+		return nil
+	}
+
+	if err := h.installNFSClient(); err != nil {
+		return fmt.Errorf(`NFS client installation failed: %w
+Please install manually:
+  Ubuntu/Debian: sudo apt install nfs-common
+  CentOS/RHEL: sudo yum install nfs-utils`, err)
+	}
+
+	return nil
+}
+
+// testNFSConnectivity checks the NFS server answers before mounting, as a hanging NFS mount is hard to interrupt
+func (h *volumeHost) testNFSConnectivity(server string) error {
+	printStatus(fmt.Sprintf("Testing connectivity to %s...", server))
+
+	if _, err := h.exec.Run("ping", "-c", "2", "-W", "2", server); err != nil {
+		return fmt.Errorf("cannot reach NFS server at %s", server)
+	}
+
+	printStatus(fmt.Sprintf("✓ NFS server %s is reachable", server))
+	return nil
+}
+
+// mountNFS mounts the export, doing nothing when it is already mounted on the mountpoint
+func (h *volumeHost) mountNFS(m nfsMount) error {
+	mounts, err := h.readMounts()
+	if err != nil {
+		return err
+	}
+
+	for _, mount := range mounts {
+		if mount.Mountpoint != m.Mountpoint {
+			continue
+		}
+		if mount.Source == m.Source() {
+			printStatus(fmt.Sprintf("✓ %s is already mounted on %s", m.Source(), m.Mountpoint))
+			return nil
+		}
+		return fmt.Errorf("%s is already in use by %s", m.Mountpoint, mount.Source)
+	}
+
+	if err := h.fs.MkdirAll(m.Mountpoint, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", m.Mountpoint, err)
+	}
+
+	if output, err := h.exec.Run("mount", "-t", "nfs", "-o", m.mountOptions(), m.Source(), m.Mountpoint); err != nil {
+		return fmt.Errorf("mount failed: %s", output)
+	}
+	printStatus(fmt.Sprintf("✓ Mounted %s on %s", m.Source(), m.Mountpoint))
+
+	return nil
+}
+
+func (o *FilesystemMountOperation) run(cmd *cobra.Command, args []string) error {
+	// Check if running as root, a dry-run only prints the command plan
+	if !lsh.DryRun && os.Geteuid() != 0 {
+		err := fmt.Errorf(`this command must be run as root (use sudo)
+
+This command requires root privileges to install the NFS client and mount the filesystem.
+
+Usage:
+  sudo lsh filesystem mount --id <FILESYSTEM_ID> --server <NFS_SERVER> --export <EXPORT_PATH> --mountpoint <MOUNTPOINT>`)
+		printError(err.Error())
+		return err
+	}
+
+	filesystemID, _ := cmd.Flags().GetString("id")
+	mountpoint, _ := cmd.Flags().GetString("mountpoint")
+	server, _ := cmd.Flags().GetString("server")
+	export, _ := cmd.Flags().GetString("export")
+	version, _ := cmd.Flags().GetString("nfs-version")
+	mountOptions, _ := cmd.Flags().GetString("mount-options")
+	persist, _ := cmd.Flags().GetBool("persist")
+
+	if server == "" || export == "" {
+		return fmt.Errorf("--server and --export are required: use the NFS endpoint shown in the dashboard")
+	}
+	if !strings.HasPrefix(export, "/") {
+		return fmt.Errorf("--export must be an absolute path")
+	}
+	if mountpoint == "" || !filepath.IsAbs(mountpoint) {
+		return fmt.Errorf("--mountpoint must be an absolute path")
+	}
+	if version == "" {
+		version = defaultNFSVersion
+	}
+	if !slices.Contains(nfsVersions, version) {
+		return fmt.Errorf("unsupported --nfs-version %q: use one of %s", version, strings.Join(nfsVersions, ", "))
+	}
+
+	mount := nfsMount{
+		Server:     server,
+		Export:     export,
+		Mountpoint: mountpoint,
+		Version:    version,
+		Options:    mountOptions,
+	}
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+	} else {
+		client, err := newStorageClient()
+		if err != nil {
+			return err
+		}
+
+		filesystem, err := findFilesystem(context.Background(), client, filesystemID)
+		if err != nil {
+			printError(err.Error())
+			return err
+		}
+		printStatus(fmt.Sprintf("Filesystem: %s (%s)", filesystem.ID, filesystem.Attributes.Name))
+	}
+
+	host := newVolumeHost(lsh.DryRun)
+
+	fmt.Fprintf(os.Stdout, "\n🔧 Preparing server for filesystem mount...\n\n")
+	if err := host.checkNFSPrerequisites(); err != nil {
+		printError(err.Error())
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "\n📡 Connecting to NFS server...\n\n")
+	if err := host.testNFSConnectivity(server); err != nil {
+		printError(fmt.Sprintf("Connectivity test failed: %v", err))
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "\n💾 Mounting filesystem...\n\n")
+	if err := host.mountNFS(mount); err != nil {
+		printError(err.Error())
+		return err
+	}
+
+	if persist {
+		fmt.Fprintf(os.Stdout, "\n🔁 Persisting mount across reboots...\n\n")
+		if err := host.upsertManagedEntry(fstabPath, filesystemPersistenceMarker(filesystemID), mount.fstabEntry()); err != nil {
+			printError(fmt.Sprintf("failed to update %s: %v", fstabPath, err))
+			return err
+		}
+		printStatus(fmt.Sprintf("✓ Added %s entry: %s", fstabPath, mount.fstabEntry()))
+	} else if cmd.Flags().Changed("persist") {
+		fmt.Fprintf(os.Stdout, "\n🧹 Removing boot entries...\n\n")
+		removed, err := host.removeManagedEntries(fstabPath, filesystemPersistenceMarker(filesystemID))
+		if err != nil {
+			printError(fmt.Sprintf("failed to update %s: %v", fstabPath, err))
+			return err
+		}
+		if removed {
+			printStatus(fmt.Sprintf("✓ Removed %s entry for %s", fstabPath, filesystemID))
+		}
+	}
+
+	if lsh.DryRun {
+		fmt.Fprintf(os.Stdout, "\n[DRY-RUN] Filesystem mount plan complete. Nothing was changed.\n")
+		return nil
+	}
+
+	fmt.Fprintf(os.Stdout, "\n✅ Filesystem mount complete!\n")
+	fmt.Fprintf(os.Stdout, "\nConnection Summary:\n")
+	fmt.Fprintf(os.Stdout, "  Export: %s\n", mount.Source())
+	fmt.Fprintf(os.Stdout, "  Mountpoint: %s\n", mountpoint)
+	fmt.Fprintf(os.Stdout, "  Persistent: %t\n", persist)
+
+	return nil
+}
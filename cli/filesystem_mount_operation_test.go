@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMountNFS(t *testing.T) {
+	mount := nfsMount{
+		Server:     "10.8.0.10",
+		Export:     "/fs_abc123",
+		Mountpoint: "/shared",
+		Version:    "4.1",
+		Options:    "noatime",
+	}
+
+	tests := []struct {
+		name     string
+		mounts   string
+		wantCmds []string
+		wantErr  bool
+	}{
+		{
+			name:     "mounts the export",
+			wantCmds: []string{"mount -t nfs -o vers=4.1,noatime 10.8.0.10:/fs_abc123 /shared"},
+		},
+		{
+			name:   "already mounted",
+			mounts: "10.8.0.10:/fs_abc123 /shared nfs4 rw,relatime,vers=4.1 0 0\n",
+		},
+		{
+			name:    "mountpoint used by another export",
+			mounts:  "10.8.0.11:/other /shared nfs4 rw,relatime,vers=4.1 0 0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, executor, fs := newTestVolumeHost(t, nil)
+			writeTestFile(t, fs, procMountsPath, tt.mounts)
+
+			err := host.mountNFS(mount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("mountNFS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(executor.calls, tt.wantCmds) {
+				t.Errorf("commands = %q, want %q", executor.calls, tt.wantCmds)
+			}
+		})
+	}
+}
+
+func TestFilesystemPersistence(t *testing.T) {
+	host, _, fs := newTestVolumeHost(t, nil)
+	writeTestFile(t, fs, fstabPath, "UUID=root / ext4 defaults 0 1\n")
+
+	mount := nfsMount{Server: "10.8.0.10", Export: "/fs_abc123", Mountpoint: "/shared", Version: "4.1"}
+	marker := filesystemPersistenceMarker("fs_abc123")
+
+	for i := 0; i < 2; i++ {
+		if err := host.upsertManagedEntry(fstabPath, marker, mount.fstabEntry()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := "UUID=root / ext4 defaults 0 1\n" +
+		"# lsh filesystem fs_abc123\n" +
+		"10.8.0.10:/fs_abc123 /shared nfs vers=4.1,_netdev,nofail 0 0\n"
+	if content, _ := fs.ReadFile(fstabPath); string(content) != want {
+		t.Errorf("fstab =\n%s\nwant\n%s", content, want)
+	}
+
+	if removed, err := host.removeManagedEntries(fstabPath, marker); err != nil || !removed {
+		t.Fatalf("removeManagedEntries() = %t, %v", removed, err)
+	}
+	if content, _ := fs.ReadFile(fstabPath); string(content) != "UUID=root / ext4 defaults 0 1\n" {
+		t.Errorf("fstab after removal =\n%s", content)
+	}
+}
+
+func TestInstallNFSClient(t *testing.T) {
+	host, executor, _ := newTestVolumeHost(t, nil)
+	executor.installed["dnf"] = true
+
+	if err := host.checkNFSPrerequisites(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"dnf install -y nfs-utils"}
+	if !reflect.DeepEqual(executor.calls, want) {
+		t.Errorf("commands = %q, want %q", executor.calls, want)
+	}
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/latitudesh/latitudesh-go-sdk/models/operations"
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/latitudesh/lsh/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func makeOperationFilesystemUpdateCmd() (*cobra.Command, error) {
+	operation := FilesystemUpdateOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type FilesystemUpdateOperation struct {
+	PathParamFlags      cmdflag.Flags
+	BodyAttributesFlags cmdflag.Flags
+}
+
+func (o *FilesystemUpdateOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:    "update",
+		Short:  "Update a filesystem",
+		Long:   "Update the size of a shared filesystem",
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.PathParamFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.BodyAttributesFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	o.PathParamFlags.Register(&cmdflag.FlagsSchema{filesystemIDFlag("The ID of the filesystem to update")})
+	o.BodyAttributesFlags.Register(&cmdflag.FlagsSchema{
+		&cmdflag.Int64{
+			Name:        "size",
+			Label:       "Size (GB)",
+			Description: "The new size of the filesystem in GB",
+			Required:    true,
+			Validators:  []cmdflag.Validator{cmdflag.AtLeast(1)},
+		},
+	})
+
+	return cmd, nil
+}
+
+func (o *FilesystemUpdateOperation) preRun(cmd *cobra.Command, args []string) {
+	o.PathParamFlags.PreRun(cmd, args)
+	o.BodyAttributesFlags.PreRun(cmd, args)
+}
+
+func (o *FilesystemUpdateOperation) run(cmd *cobra.Command, args []string) error {
+	filesystemID, _ := cmd.Flags().GetString("id")
+	size, _ := cmd.Flags().GetInt64("size")
+
+	if size <= 0 {
+		return fmt.Errorf("--size must be a positive number of GB")
+	}
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		return nil
+	}
+
+	client, err := newStorageClient()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "Updating filesystem %s...\n", filesystemID)
+	response, err := client.Storage.UpdateFilesystem(context.Background(), filesystemID, operations.PatchStorageFilesystemsStorageRequestBody{
+		Data: operations.PatchStorageFilesystemsStorageData{
+			ID:   filesystemID,
+			Type: operations.PatchStorageFilesystemsStorageTypeFilesystems,
+			Attributes: operations.PatchStorageFilesystemsStorageAttributes{
+				SizeInGb: &size,
+			},
+		},
+	})
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	if !lsh.Debug && response != nil && response.Object != nil {
+		utils.Render(newFilesystem(response.Object.Data).GetData())
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ package cli
 import (
 	"github.com/latitudesh/lsh/client/api_keys"
 	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/renderer"
 	"github.com/latitudesh/lsh/internal/utils"
 
 	"github.com/spf13/cobra"
@@ -20,6 +21,8 @@ func makeOperationAPIKeysGetAPIKeysCmd() (*cobra.Command, error) {
 		RunE:  runOperationAPIKeysGetAPIKeys,
 	}
 
+	utils.RegisterWatchFlag(cmd)
+
 	return cmd, nil
 }
 
@@ -31,12 +34,26 @@ func runOperationAPIKeysGetAPIKeys(cmd *cobra.Command, args []string) error {
 	}
 	// retrieve flag values from cmd and fill params
 	params := api_keys.NewGetAPIKeysParams()
+	interval, err := utils.WatchInterval(cmd, args)
+	if err != nil {
+		return err
+	}
 	if lsh.DryRun {
 
 		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
 		return nil
 	}
 
+	if interval > 0 {
+		return renderer.Watch("API Keys", interval, func() ([]renderer.ResponseData, error) {
+			response, err := appCli.APIKeys.GetAPIKeys(params, nil)
+			if err != nil {
+				return nil, err
+			}
+			return response.GetData(), nil
+		})
+	}
+
 	response, err := appCli.APIKeys.GetAPIKeys(params, nil)
 	if err != nil {
 		utils.PrintError(err)
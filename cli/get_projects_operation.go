@@ -8,6 +8,7 @@ import (
 
 	"github.com/latitudesh/lsh/client/projects"
 	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/renderer"
 	"github.com/latitudesh/lsh/internal/utils"
 
 	"github.com/spf13/cobra"
@@ -26,6 +27,8 @@ func makeOperationProjectsGetProjectsCmd() (*cobra.Command, error) {
 		return nil, err
 	}
 
+	utils.RegisterWatchFlag(cmd)
+
 	return cmd, nil
 }
 
@@ -58,12 +61,26 @@ func runOperationProjectsGetProjects(cmd *cobra.Command, args []string) error {
 	if err, _ := retrieveOperationProjectsGetProjectsFilterTagsFlag(params, "", cmd); err != nil {
 		return err
 	}
+	interval, err := utils.WatchInterval(cmd, args)
+	if err != nil {
+		return err
+	}
 	if lsh.DryRun {
 
 		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
 		return nil
 	}
 
+	if interval > 0 {
+		return renderer.Watch("Projects", interval, func() ([]renderer.ResponseData, error) {
+			response, err := appCli.Projects.GetProjects(params, nil)
+			if err != nil {
+				return nil, err
+			}
+			return response.GetData(), nil
+		})
+	}
+
 	response, err := appCli.Projects.GetProjects(params, nil)
 	if err != nil {
 		utils.PrintError(err)
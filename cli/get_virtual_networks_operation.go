@@ -8,6 +8,7 @@ import (
 
 	"github.com/latitudesh/lsh/client/virtual_networks"
 	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/renderer"
 	"github.com/latitudesh/lsh/internal/utils"
 
 	"github.com/spf13/cobra"
@@ -25,6 +26,8 @@ func makeOperationVirtualNetworksGetVirtualNetworksCmd() (*cobra.Command, error)
 		return nil, err
 	}
 
+	utils.RegisterWatchFlag(cmd)
+
 	return cmd, nil
 }
 
@@ -45,12 +48,26 @@ func runOperationVirtualNetworksGetVirtualNetworks(cmd *cobra.Command, args []st
 	if err, _ := retrieveOperationVirtualNetworksGetVirtualNetworksFilterTagsFlag(params, "", cmd); err != nil {
 		return err
 	}
+	interval, err := utils.WatchInterval(cmd, args)
+	if err != nil {
+		return err
+	}
 	if lsh.DryRun {
 
 		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
 		return nil
 	}
 
+	if interval > 0 {
+		return renderer.Watch("Virtual Networks", interval, func() ([]renderer.ResponseData, error) {
+			response, err := appCli.VirtualNetworks.GetVirtualNetworks(params, nil)
+			if err != nil {
+				return nil, err
+			}
+			return response.GetData(), nil
+		})
+	}
+
 	response, err := appCli.VirtualNetworks.GetVirtualNetworks(params, nil)
 	if err != nil {
 		utils.PrintError(err)
@@ -0,0 +1,340 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/latitudesh/lsh/client"
+	"github.com/latitudesh/lsh/client/servers"
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/latitudesh/lsh/models"
+	"github.com/spf13/cobra"
+)
+
+var ansibleGroupNameInvalidChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+func makeOperationInventoryAnsibleCmd() (*cobra.Command, error) {
+	operation := InventoryAnsibleOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type InventoryAnsibleOperation struct {
+	QueryParamFlags cmdflag.Flags
+	OptionsFlags    cmdflag.Flags
+}
+
+// ansibleGroup is a group entry of the Ansible dynamic inventory protocol
+type ansibleGroup struct {
+	Hosts    []string `json:"hosts,omitempty"`
+	Children []string `json:"children,omitempty"`
+}
+
+// ansibleInventory is the document returned by --list
+type ansibleInventory struct {
+	Groups   map[string]*ansibleGroup
+	HostVars map[string]map[string]interface{}
+}
+
+func (i ansibleInventory) MarshalJSON() ([]byte, error) {
+	document := make(map[string]interface{}, len(i.Groups)+1)
+	for name, group := range i.Groups {
+		document[name] = group
+	}
+	document["_meta"] = map[string]interface{}{
+		"hostvars": i.HostVars,
+	}
+
+	return json.Marshal(document)
+}
+
+func (o *InventoryAnsibleOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "ansible",
+		Short: "Ansible dynamic inventory built from your servers",
+		Long: `Implements the Ansible dynamic inventory protocol on top of the servers API.
+
+Hosts are grouped by project, site, country, plan, operating system and tag.
+Every attribute returned by the API is exposed as a host variable prefixed with "lsh_",
+and ansible_host is set to the server primary IPv4. Servers sharing a hostname are
+named after their hostname and ID, such as web-01-sv_2GmAlJ6BXlK1a.
+
+Ansible calls inventory scripts with --list or --host, so wrap lsh in an executable file:
+
+  #!/bin/sh
+  exec lsh inventory ansible --project my-project "$@"
+
+Example:
+  ansible-inventory -i ./latitude.sh --graph`,
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.registerFlags(cmd)
+
+	return cmd, nil
+}
+
+func (o *InventoryAnsibleOperation) registerFlags(cmd *cobra.Command) {
+	o.QueryParamFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.OptionsFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	queryParamsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "project",
+			Label:       "Project ID or Slug",
+			Description: "Only include servers from this project",
+			Required:    false,
+			Env:         true,
+		},
+	}
+
+	optionsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.Bool{
+			Name:        "list",
+			Label:       "List",
+			Description: "Print the whole inventory (default)",
+			Required:    false,
+		},
+		&cmdflag.String{
+			Name:        "host",
+			Label:       "Host",
+			Description: "Print the variables of a single host",
+			Required:    false,
+		},
+	}
+
+	o.QueryParamFlags.Register(queryParamsSchema)
+	o.OptionsFlags.Register(optionsSchema)
+}
+
+func (o *InventoryAnsibleOperation) preRun(cmd *cobra.Command, args []string) {
+	o.QueryParamFlags.PreRun(cmd, args)
+	o.OptionsFlags.PreRun(cmd, args)
+}
+
+func (o *InventoryAnsibleOperation) run(cmd *cobra.Command, args []string) error {
+	project, _ := cmd.Flags().GetString("project")
+	list, _ := cmd.Flags().GetBool("list")
+	host, _ := cmd.Flags().GetString("host")
+
+	if list && host != "" {
+		return fmt.Errorf("--list and --host cannot be used together")
+	}
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		return nil
+	}
+
+	appCli, err := makeClient(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	serverList, err := fetchInventoryServers(appCli, project)
+	if err != nil {
+		return err
+	}
+
+	inventory := buildAnsibleInventory(serverList)
+
+	// --list is the default, so only --host changes the document
+	var document interface{} = inventory
+	if host != "" {
+		hostVars, ok := inventory.HostVars[host]
+		if !ok {
+			hostVars = map[string]interface{}{}
+		}
+		document = hostVars
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+// fetchInventoryServers lists the servers of the team, optionally filtered by project
+func fetchInventoryServers(appCli *client.LatitudeShAPI, project string) ([]*models.ServerData, error) {
+	params := servers.NewGetServersParams()
+	if project != "" {
+		params.SetFilterProject(&project)
+	}
+
+	response, err := appCli.Servers.GetServers(params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	if response.Payload == nil {
+		return nil, nil
+	}
+
+	return response.Payload.Data, nil
+}
+
+// buildAnsibleInventory groups servers and collects their host variables
+func buildAnsibleInventory(serverList []*models.ServerData) ansibleInventory {
+	inventory := ansibleInventory{
+		Groups:   map[string]*ansibleGroup{},
+		HostVars: map[string]map[string]interface{}{},
+	}
+
+	addToGroup := func(prefix, value, host string) {
+		name := ansibleGroupName(prefix, value)
+		if name == "" {
+			return
+		}
+
+		group, ok := inventory.Groups[name]
+		if !ok {
+			group = &ansibleGroup{}
+			inventory.Groups[name] = group
+		}
+		group.Hosts = append(group.Hosts, host)
+	}
+
+	// Ansible keys hosts by name, so servers sharing a hostname are told apart by their ID
+	hostnames := map[string]int{}
+	for _, server := range serverList {
+		if server != nil && server.Attributes != nil {
+			hostnames[server.Attributes.Hostname]++
+		}
+	}
+
+	for _, server := range serverList {
+		if server == nil || server.Attributes == nil || server.Attributes.Hostname == "" {
+			continue
+		}
+
+		attr := server.Attributes
+		host := attr.Hostname
+		if hostnames[host] > 1 {
+			host = fmt.Sprintf("%s-%s", host, server.ID)
+		}
+
+		inventory.HostVars[host] = ansibleHostVars(server)
+
+		if attr.Project != nil {
+			addToGroup("project", attr.Project.Slug, host)
+		}
+		if attr.Region != nil {
+			if attr.Region.Site != nil {
+				addToGroup("site", attr.Region.Site.Slug, host)
+			}
+			addToGroup("country", attr.Region.Country, host)
+		}
+		if attr.Plan != nil {
+			addToGroup("plan", attr.Plan.Name, host)
+		}
+		if attr.OperatingSystem != nil {
+			addToGroup("os", attr.OperatingSystem.Slug, host)
+		}
+		for _, tag := range attr.Tags {
+			if tag != nil {
+				addToGroup("tag", tag.Name, host)
+			}
+		}
+		addToGroup("status", attr.Status, host)
+	}
+
+	var children []string
+	for name, group := range inventory.Groups {
+		sort.Strings(group.Hosts)
+		children = append(children, name)
+	}
+	sort.Strings(children)
+
+	inventory.Groups["all"] = &ansibleGroup{Children: children}
+
+	return inventory
+}
+
+// ansibleHostVars exposes the server attributes as Ansible host variables
+func ansibleHostVars(server *models.ServerData) map[string]interface{} {
+	attr := server.Attributes
+
+	vars := map[string]interface{}{
+		"lsh_id":          server.ID,
+		"lsh_hostname":    attr.Hostname,
+		"lsh_status":      attr.Status,
+		"lsh_ipmi_status": attr.IpmiStatus,
+		"lsh_role":        attr.Role,
+		"ansible_user":    sshUserForOS(attr.OperatingSystem),
+	}
+
+	if attr.PrimaryIPV4 != nil && *attr.PrimaryIPV4 != "" {
+		vars["ansible_host"] = *attr.PrimaryIPV4
+		vars["lsh_primary_ipv4"] = *attr.PrimaryIPV4
+	}
+	if attr.PrimaryIPV6 != nil && *attr.PrimaryIPV6 != "" {
+		vars["lsh_primary_ipv6"] = *attr.PrimaryIPV6
+	}
+	if attr.CreatedAt != nil {
+		vars["lsh_created_at"] = *attr.CreatedAt
+	}
+	if attr.ScheduledDeletionAt != nil {
+		vars["lsh_scheduled_deletion_at"] = *attr.ScheduledDeletionAt
+	}
+	if attr.Project != nil {
+		vars["lsh_project"] = attr.Project.Slug
+		vars["lsh_project_id"] = attr.Project.ID
+	}
+	if attr.Region != nil {
+		vars["lsh_region_city"] = attr.Region.City
+		vars["lsh_region_country"] = attr.Region.Country
+		if attr.Region.Site != nil {
+			vars["lsh_site"] = attr.Region.Site.Slug
+			vars["lsh_facility"] = attr.Region.Site.Facility
+		}
+	}
+	if attr.Plan != nil {
+		vars["lsh_plan"] = attr.Plan.Name
+		vars["lsh_plan_id"] = attr.Plan.ID
+	}
+	if attr.OperatingSystem != nil {
+		vars["lsh_operating_system"] = attr.OperatingSystem.Slug
+		vars["lsh_operating_system_version"] = attr.OperatingSystem.Version
+	}
+	if attr.Specs != nil {
+		vars["lsh_specs"] = map[string]string{
+			"cpu":  attr.Specs.CPU,
+			"disk": attr.Specs.Disk,
+			"ram":  attr.Specs.RAM,
+		}
+	}
+	if attr.Team != nil {
+		vars["lsh_team"] = attr.Team.Slug
+	}
+
+	tagNames := []string{}
+	for _, tag := range attr.Tags {
+		if tag != nil {
+			tagNames = append(tagNames, tag.Name)
+		}
+	}
+	vars["lsh_tags"] = tagNames
+
+	return vars
+}
+
+// ansibleGroupName builds a valid Ansible group name such as "site_sao2"
+func ansibleGroupName(prefix, value string) string {
+	value = ansibleGroupNameInvalidChars.ReplaceAllString(strings.ToLower(value), "_")
+	value = strings.Trim(value, "_")
+	if value == "" {
+		return ""
+	}
+
+	return prefix + "_" + value
+}
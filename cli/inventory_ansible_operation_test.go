@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/latitudesh/lsh/client"
+	"github.com/latitudesh/lsh/models"
+)
+
+// newRecordedClient serves the recorded responses of the given paths through a local API
+func newRecordedClient(t *testing.T, fixtures map[string]string) *client.LatitudeShAPI {
+	t.Helper()
+
+	return newTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture, ok := fixtures[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		body, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Errorf("reading fixture %s: %v", fixture, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write(body)
+	}))
+}
+
+// newTestAPIClient sends the requests of the API client to handler
+func newTestAPIClient(t *testing.T, handler http.Handler) *client.LatitudeShAPI {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptransport.New(serverURL.Host, client.DefaultBasePath, []string{serverURL.Scheme})
+	r.Consumers["application/vnd.api+json"] = runtime.JSONConsumer()
+
+	return client.New(r, strfmt.Default)
+}
+
+func TestBuildAnsibleInventory_RecordedServers(t *testing.T) {
+	appCli := newRecordedClient(t, map[string]string{"/servers": "testdata/servers.json"})
+
+	serverList, err := fetchInventoryServers(appCli, "acme-web")
+	if err != nil {
+		t.Fatalf("fetchInventoryServers() error = %v", err)
+	}
+
+	inventory := buildAnsibleInventory(serverList)
+
+	expectedGroups := map[string][]string{
+		"project_acme_web":        {"db-01", "web-01"},
+		"site_sao2":               {"web-01"},
+		"site_dal":                {"db-01"},
+		"country_brazil":          {"web-01"},
+		"country_united_states":   {"db-01"},
+		"plan_c2_small_x86":       {"web-01"},
+		"plan_m3_large_x86":       {"db-01"},
+		"os_ubuntu_22_04_x64_lts": {"web-01"},
+		"os_rockylinux_8":         {"db-01"},
+		"tag_web":                 {"web-01"},
+		"tag_production_api":      {"web-01"},
+		"status_on":               {"web-01"},
+		"status_off":              {"db-01"},
+	}
+
+	for name, hosts := range expectedGroups {
+		group, ok := inventory.Groups[name]
+		if !ok {
+			t.Errorf("missing group %s", name)
+			continue
+		}
+		if !reflect.DeepEqual(group.Hosts, hosts) {
+			t.Errorf("group %s hosts = %v, want %v", name, group.Hosts, hosts)
+		}
+	}
+
+	if got, want := len(inventory.Groups["all"].Children), len(expectedGroups); got != want {
+		t.Errorf("all has %d children, want %d", got, want)
+	}
+
+	web := inventory.HostVars["web-01"]
+	if web["ansible_host"] != "203.0.113.10" {
+		t.Errorf("web-01 ansible_host = %v, want 203.0.113.10", web["ansible_host"])
+	}
+	if web["ansible_user"] != "ubuntu" {
+		t.Errorf("web-01 ansible_user = %v, want ubuntu", web["ansible_user"])
+	}
+	if web["lsh_site"] != "SAO2" {
+		t.Errorf("web-01 lsh_site = %v, want SAO2", web["lsh_site"])
+	}
+	if db := inventory.HostVars["db-01"]; db["ansible_user"] != "rocky" {
+		t.Errorf("db-01 ansible_user = %v, want rocky", db["ansible_user"])
+	}
+}
+
+func TestBuildAnsibleInventory_DuplicateHostnames(t *testing.T) {
+	server := func(id, hostname, ip string) *models.ServerData {
+		return &models.ServerData{ID: id, Attributes: &models.ServerDataAttributes{Hostname: hostname, PrimaryIPV4: &ip}}
+	}
+
+	inventory := buildAnsibleInventory([]*models.ServerData{
+		server("sv_1", "web", "203.0.113.1"),
+		server("sv_2", "web", "203.0.113.2"),
+		server("sv_3", "db", "203.0.113.3"),
+	})
+
+	want := map[string]string{"web-sv_1": "203.0.113.1", "web-sv_2": "203.0.113.2", "db": "203.0.113.3"}
+	if len(inventory.HostVars) != len(want) {
+		t.Errorf("hostvars = %v, want %d hosts", inventory.HostVars, len(want))
+	}
+	for host, ip := range want {
+		if got := inventory.HostVars[host]["ansible_host"]; got != ip {
+			t.Errorf("%s ansible_host = %v, want %s", host, got, ip)
+		}
+	}
+}
+
+func TestAnsibleInventory_MarshalJSON(t *testing.T) {
+	appCli := newRecordedClient(t, map[string]string{"/servers": "testdata/servers.json"})
+
+	serverList, err := fetchInventoryServers(appCli, "")
+	if err != nil {
+		t.Fatalf("fetchInventoryServers() error = %v", err)
+	}
+
+	encoded, err := json.Marshal(buildAnsibleInventory(serverList))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		t.Fatalf("inventory is not a JSON object: %v", err)
+	}
+
+	var meta struct {
+		HostVars map[string]map[string]interface{} `json:"hostvars"`
+	}
+	if err := json.Unmarshal(document["_meta"], &meta); err != nil {
+		t.Fatalf("_meta is invalid: %v", err)
+	}
+	if len(meta.HostVars) != 2 {
+		t.Errorf("_meta.hostvars has %d hosts, want 2", len(meta.HostVars))
+	}
+
+	if _, ok := document["all"]; !ok {
+		t.Error("inventory is missing the all group")
+	}
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// updateManagedFile rewrites the part of path lsh manages, such as a block of ~/.ssh/config or the
+// fstab entries of a volume. update returns the new content from the current one, empty when the
+// file does not exist. The file is replaced atomically, and left untouched when nothing changed;
+// dirPerm and perm apply to a new directory and a new file
+func updateManagedFile(fs hostFilesystem, path string, dirPerm, perm os.FileMode, update func(content string) (string, error)) error {
+	content, err := fs.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	updated, err := update(string(content))
+	if err != nil {
+		return err
+	}
+	if updated == string(content) {
+		return nil
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
+		return err
+	}
+
+	return fs.WriteFile(path, []byte(updated), perm)
+}
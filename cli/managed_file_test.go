@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdateManagedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ssh", "config")
+	appendLine := func(content string) (string, error) { return content + "Host web-01\n", nil }
+
+	// A missing file is created, with its directory
+	if err := updateManagedFile(osFilesystem{}, path, 0700, 0600, appendLine); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("Stat() = %v, %v, want a 0600 file", info, err)
+	}
+
+	// An existing file keeps its mode
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := updateManagedFile(osFilesystem{}, path, 0700, 0600, appendLine); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0644 {
+		t.Errorf("mode = %v, want the existing 0644", info.Mode().Perm())
+	}
+	if content, _ := os.ReadFile(path); string(content) != "Host web-01\nHost web-01\n" {
+		t.Errorf("content = %q", content)
+	}
+
+	// A failed update leaves the file untouched
+	err := updateManagedFile(osFilesystem{}, path, 0700, 0600, func(string) (string, error) {
+		return "", errors.New("unterminated block")
+	})
+	if err == nil {
+		t.Fatal("updateManagedFile() ignored the update error")
+	}
+	if content, _ := os.ReadFile(path); string(content) != "Host web-01\nHost web-01\n" {
+		t.Errorf("the file was changed: %q", content)
+	}
+	if _, err := os.Stat(path + ".lsh.tmp"); !os.IsNotExist(err) {
+		t.Errorf("the temporary file was left behind: %v", err)
+	}
+}
@@ -374,7 +374,7 @@ func renderGroupedPlans(plans []groupedPlan) {
 	}
 
 	var rows []table.Row
-	var originalPlans []map[string]string
+	var resources []interface{}
 
 	for _, p := range plans {
 		availStr := wrapLocationsSmartLimit(p.AvailableIn, 4)
@@ -392,20 +392,10 @@ func renderGroupedPlans(plans []groupedPlan) {
 			stockStr,
 		})
 
-		originalPlans = append(originalPlans, map[string]string{
-			"SLUG":         p.Slug,
-			"CPU":          p.CPU,
-			"DRIVES":       p.Drives,
-			"NIC":          p.NIC,
-			"ID":           p.ID,
-			"FEATURES":     strings.Join(p.Features, ", "),
-			"MEMORY":       p.Memory,
-			"AVAILABLE IN": strings.Join(p.AvailableIn, ", "),
-			"IN STOCK":     strings.Join(p.InStock, ", "),
-		})
+		resources = append(resources, p)
 	}
 
-	tui.RunPlansTable("Available Plans", columns, rows, originalPlans)
+	tui.RunResourceTable("Available Plans", columns, rows, resources)
 }
 
 func wrapLocations(locs []string, maxPerLine int) string {
@@ -523,7 +513,7 @@ func renderStockTable(rows []flatRow) {
 	}
 
 	var tableRows []table.Row
-	var originalPlans []map[string]string
+	var resources []interface{}
 
 	for _, r := range rows {
 		// Format CPU
@@ -564,21 +554,10 @@ func renderStockTable(rows []flatRow) {
 			r.Location,
 		})
 
-		originalPlans = append(originalPlans, map[string]string{
-			"PLAN":     r.PlanSlug,
-			"ID":       r.PlanID,
-			"CPU":      cpuInfo,
-			"RAM":      ramInfo,
-			"DRIVES":   drivesInfo,
-			"FEATURES": featuresInfo,
-			"STOCK":    stockDisplay,
-			"PRICE/MO": priceDisplay,
-			"REGION":   r.Region,
-			"LOCATION": r.Location,
-		})
+		resources = append(resources, r)
 	}
 
-	tui.RunPlansTable("Plans Availability", columns, tableRows, originalPlans)
+	tui.RunResourceTable("Plans Availability", columns, tableRows, resources)
 }
 
 func renderStockTableClassic(rows []flatRow) {
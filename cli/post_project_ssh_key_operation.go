@@ -49,6 +49,7 @@ func (o *CreateSSHKeyOperation) registerFlags(cmd *cobra.Command) {
 			Label:       "Project ID or Slug",
 			Description: "Project ID or Slug",
 			Required:    true,
+			Env:         true,
 		}}
 
 	bodyFlagsSchema := &cmdflag.FlagsSchema{
@@ -63,6 +64,8 @@ func (o *CreateSSHKeyOperation) registerFlags(cmd *cobra.Command) {
 			Label:       "SSH Public Key",
 			Description: "SSH Public Key",
 			Required:    true,
+			Validators:  []cmdflag.Validator{cmdflag.SSHPublicKey()},
+			Env:         true,
 		},
 	}
 
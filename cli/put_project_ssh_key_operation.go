@@ -55,6 +55,7 @@ func (o *UpdateSSHKeyOperation) registerFlags(cmd *cobra.Command) {
 			Label:       "Project ID or Slug",
 			Description: "Project ID or Slug",
 			Required:    true,
+			Env:         true,
 		},
 	}
 
@@ -0,0 +1,384 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/latitudesh/lsh/client"
+	"github.com/latitudesh/lsh/client/servers"
+	"github.com/latitudesh/lsh/client/ssh_keys"
+	"github.com/latitudesh/lsh/client/virtual_networks"
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/spf13/cobra"
+)
+
+// resourceKind describes a resource whose ID flags accept names
+type resourceKind struct {
+	// Name is used in messages, e.g. "server"
+	Name string
+	// IDPrefix is the prefix of the resource IDs, e.g. "sv_"
+	IDPrefix string
+	// Scope returns the key under which the candidates are cached, e.g. the project of SSH keys.
+	// Resolution is skipped when it returns false.
+	Scope func(cmd *cobra.Command) (string, bool)
+	List  func(cmd *cobra.Command, appCli *client.LatitudeShAPI, scope string) ([]resourceCandidate, error)
+}
+
+// resourceCandidate is a resource that an identifier can resolve to
+type resourceCandidate struct {
+	ID string
+	// Names are the hostname, name or slug of the resource
+	Names []string
+	// Description is shown when an identifier is ambiguous
+	Description string
+}
+
+var (
+	serverKind = &resourceKind{
+		Name:     "server",
+		IDPrefix: "sv_",
+		List:     listServerCandidates,
+	}
+	sshKeyKind = &resourceKind{
+		Name:     "SSH key",
+		IDPrefix: "ssh_",
+		Scope:    projectScope,
+		List:     listSSHKeyCandidates,
+	}
+	virtualNetworkKind = &resourceKind{
+		Name:     "virtual network",
+		IDPrefix: "vlan_",
+		List:     listVirtualNetworkCandidates,
+	}
+	tagKind = &resourceKind{
+		Name:     "tag",
+		IDPrefix: "tag_",
+		List:     listTagCandidates,
+	}
+	volumeKind = &resourceKind{
+		Name:     "volume",
+		IDPrefix: "vol_",
+		List:     listVolumeCandidates,
+	}
+)
+
+// identifierFlags maps the path of a command group to the flags holding IDs of each resource kind.
+// A command uses the entry of its closest group, e.g. "volume" for "volume nqn authorize"
+var identifierFlags = map[string]map[string]*resourceKind{
+	"servers":                      {"id": serverKind},
+	"ssh_keys":                     {"id": sshKeyKind},
+	"virtual_networks":             {"id": virtualNetworkKind},
+	"virtual_networks assignments": {"server_id": serverKind, "virtual_network_id": virtualNetworkKind},
+	"tags":                         {"id": tagKind},
+	"volume":                       {"id": volumeKind},
+}
+
+// resourceIDLength is the number of characters after the prefix of a complete ID, e.g. sv_2GmAlJ6BXlK1a
+const resourceIDLength = 13
+
+// resolverCache keeps the candidates listed during this invocation
+var resolverCache = struct {
+	sync.Mutex
+	candidates map[string][]resourceCandidate
+}{candidates: map[string][]resourceCandidate{}}
+
+// commandIdentifierFlags returns the ID flags of cmd, from the entry of its closest group
+func commandIdentifierFlags(cmd *cobra.Command) map[string]*resourceKind {
+	var groups []string
+	for parent := cmd.Parent(); parent != nil && parent.HasParent(); parent = parent.Parent() {
+		groups = append([]string{parent.Name()}, groups...)
+	}
+
+	for i := len(groups); i > 0; i-- {
+		if flags, ok := identifierFlags[strings.Join(groups[:i], " ")]; ok {
+			return flags
+		}
+	}
+	return nil
+}
+
+// isResourceID reports whether value is a complete ID of kind, which needs no lookup
+func isResourceID(kind *resourceKind, value string) bool {
+	id, ok := strings.CutPrefix(value, kind.IDPrefix)
+	if !ok || len(id) != resourceIDLength {
+		return false
+	}
+
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveIdentifierFlags replaces hostnames, names, slugs and ID prefixes given to ID flags by the
+// matching ID. It runs before PreRun for the values given on the command line, and again after the
+// prompt for those typed in it
+func resolveIdentifierFlags(cmd *cobra.Command, args []string) error {
+	if lsh.DryRun || !cmd.HasParent() {
+		return nil
+	}
+
+	flags := commandIdentifierFlags(cmd)
+
+	var appCli *client.LatitudeShAPI
+	for flagName, kind := range flags {
+		flag := cmd.Flags().Lookup(flagName)
+		if flag == nil || !flag.Changed || flag.Value.String() == "" || isResourceID(kind, flag.Value.String()) {
+			continue
+		}
+
+		scope := ""
+		if kind.Scope != nil {
+			var ok bool
+			if scope, ok = kind.Scope(cmd); !ok {
+				continue
+			}
+		}
+
+		if appCli == nil {
+			var err error
+			if appCli, err = makeClient(cmd, args); err != nil {
+				return err
+			}
+		}
+
+		candidates, err := cachedCandidates(cmd, appCli, kind, scope)
+		if err != nil {
+			return fmt.Errorf("failed to resolve --%s: %w", flagName, err)
+		}
+
+		id, err := resolveIdentifier(kind, flag.Value.String(), candidates)
+		if err != nil {
+			return err
+		}
+
+		if id != flag.Value.String() {
+			lsh.LogDebugf("resolved --%s %q to %s", flagName, flag.Value.String(), id)
+			if err := cmd.Flags().Set(flagName, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func cachedCandidates(cmd *cobra.Command, appCli *client.LatitudeShAPI, kind *resourceKind, scope string) ([]resourceCandidate, error) {
+	key := kind.Name + "/" + scope
+
+	resolverCache.Lock()
+	defer resolverCache.Unlock()
+
+	if candidates, ok := resolverCache.candidates[key]; ok {
+		return candidates, nil
+	}
+
+	candidates, err := kind.List(cmd, appCli, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	resolverCache.candidates[key] = candidates
+	return candidates, nil
+}
+
+// resolveIdentifier finds the ID matching an exact ID, a hostname, name or slug, or a unique ID prefix.
+// Values carrying the ID prefix that match nothing are returned unchanged for the API to decide.
+func resolveIdentifier(kind *resourceKind, value string, candidates []resourceCandidate) (string, error) {
+	for _, candidate := range candidates {
+		if candidate.ID == value {
+			return value, nil
+		}
+	}
+
+	var byName []resourceCandidate
+	for _, candidate := range candidates {
+		for _, name := range candidate.Names {
+			if name != "" && strings.EqualFold(name, value) {
+				byName = append(byName, candidate)
+				break
+			}
+		}
+	}
+	if len(byName) == 1 {
+		return byName[0].ID, nil
+	}
+	if len(byName) > 1 {
+		return "", ambiguousIdentifierError(kind, value, byName)
+	}
+
+	var byPrefix []resourceCandidate
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate.ID, value) || strings.HasPrefix(candidate.ID, kind.IDPrefix+value) {
+			byPrefix = append(byPrefix, candidate)
+		}
+	}
+	if len(byPrefix) == 1 {
+		return byPrefix[0].ID, nil
+	}
+	if len(byPrefix) > 1 {
+		return "", ambiguousIdentifierError(kind, value, byPrefix)
+	}
+
+	if strings.HasPrefix(value, kind.IDPrefix) {
+		return value, nil
+	}
+
+	return "", fmt.Errorf("no %s matches %q", kind.Name, value)
+}
+
+func ambiguousIdentifierError(kind *resourceKind, value string, matches []resourceCandidate) error {
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].ID < matches[j].ID
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d %ss, use one of these IDs instead:", value, len(matches), kind.Name)
+	for _, match := range matches {
+		fmt.Fprintf(&b, "\n  %s  %s", match.ID, match.Description)
+	}
+
+	return fmt.Errorf("%s", b.String())
+}
+
+func projectScope(cmd *cobra.Command) (string, bool) {
+	project, _ := cmd.Flags().GetString("project")
+	return project, project != ""
+}
+
+func listServerCandidates(cmd *cobra.Command, appCli *client.LatitudeShAPI, _ string) ([]resourceCandidate, error) {
+	response, err := appCli.Servers.GetServers(servers.NewGetServersParams(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []resourceCandidate
+	if response.Payload != nil {
+		for _, server := range response.Payload.Data {
+			if server == nil || server.Attributes == nil {
+				continue
+			}
+
+			description := server.Attributes.Hostname
+			if server.Attributes.Project != nil {
+				description = fmt.Sprintf("%s (project %s)", description, server.Attributes.Project.Slug)
+			}
+
+			candidates = append(candidates, resourceCandidate{
+				ID:          server.ID,
+				Names:       []string{server.Attributes.Hostname},
+				Description: description,
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+func listSSHKeyCandidates(cmd *cobra.Command, appCli *client.LatitudeShAPI, project string) ([]resourceCandidate, error) {
+	response, err := appCli.SSHKeys.GetProjectSSHKeys(ssh_keys.NewGetProjectSSHKeysParams().WithProjectIDOrSlug(project), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []resourceCandidate
+	if response.Payload != nil {
+		for _, key := range response.Payload.Data {
+			if key == nil || key.Attributes == nil {
+				continue
+			}
+
+			candidates = append(candidates, resourceCandidate{
+				ID:          key.ID,
+				Names:       []string{key.Attributes.Name},
+				Description: key.Attributes.Name,
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+func listVirtualNetworkCandidates(cmd *cobra.Command, appCli *client.LatitudeShAPI, _ string) ([]resourceCandidate, error) {
+	response, err := appCli.VirtualNetworks.GetVirtualNetworks(virtual_networks.NewGetVirtualNetworksParams(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []resourceCandidate
+	if response.Payload != nil {
+		for _, vlan := range response.Payload.Data {
+			if vlan == nil || vlan.Attributes == nil {
+				continue
+			}
+
+			candidates = append(candidates, resourceCandidate{
+				ID:          vlan.ID,
+				Names:       []string{vlan.Attributes.Name, vlan.Attributes.Description},
+				Description: fmt.Sprintf("%s (VID %d)", vlan.Attributes.Description, vlan.Attributes.Vid),
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+func listTagCandidates(cmd *cobra.Command, _ *client.LatitudeShAPI, _ string) ([]resourceCandidate, error) {
+	response, err := lsh.NewClient().Tags.List(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []resourceCandidate
+	if response.CustomTags != nil {
+		for _, tag := range response.CustomTags.Data {
+			if tag.ID == nil || tag.Attributes == nil {
+				continue
+			}
+
+			name := derefString(tag.Attributes.Name)
+			candidates = append(candidates, resourceCandidate{
+				ID:          *tag.ID,
+				Names:       []string{name, derefString(tag.Attributes.Slug)},
+				Description: name,
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+func listVolumeCandidates(cmd *cobra.Command, _ *client.LatitudeShAPI, _ string) ([]resourceCandidate, error) {
+	response, err := lsh.NewClient().Storage.GetStorageVolumes(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []resourceCandidate
+	if response.Object != nil {
+		for _, volume := range response.Object.Data {
+			if volume.ID == nil || volume.Attributes == nil {
+				continue
+			}
+
+			name := derefString(volume.Attributes.Name)
+			description := name
+			if volume.Attributes.Project != nil && volume.Attributes.Project.Slug != nil {
+				description = fmt.Sprintf("%s (project %s)", name, *volume.Attributes.Project.Slug)
+			}
+
+			candidates = append(candidates, resourceCandidate{
+				ID:          *volume.ID,
+				Names:       []string{name},
+				Description: description,
+			})
+		}
+	}
+
+	return candidates, nil
+}
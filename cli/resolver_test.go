@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/latitudesh/lsh/client"
+	"github.com/spf13/cobra"
+)
+
+func TestResolveIdentifier(t *testing.T) {
+	candidates := []resourceCandidate{
+		{ID: "sv_2GmAlJ6BXlK1a", Names: []string{"web-01"}, Description: "web-01 (project acme-web)"},
+		{ID: "sv_2GmXz81kQpL0b", Names: []string{"web-02"}, Description: "web-02 (project acme-web)"},
+		{ID: "sv_8WbKpO3V5lmq4", Names: []string{"db"}, Description: "db (project acme-web)"},
+		{ID: "sv_9QrT4mN2cX7vE", Names: []string{"db"}, Description: "db (project staging)"},
+	}
+
+	tests := []struct {
+		value     string
+		want      string
+		ambiguous bool
+		wantErr   bool
+	}{
+		{value: "sv_8WbKpO3V5lmq4", want: "sv_8WbKpO3V5lmq4"},
+		{value: "web-01", want: "sv_2GmAlJ6BXlK1a"},
+		{value: "WEB-02", want: "sv_2GmXz81kQpL0b"},
+		{value: "sv_8Wb", want: "sv_8WbKpO3V5lmq4"},
+		{value: "9QrT", want: "sv_9QrT4mN2cX7vE"},
+		{value: "sv_unknownServer", want: "sv_unknownServer"},
+		{value: "db", ambiguous: true, wantErr: true},
+		{value: "sv_2Gm", ambiguous: true, wantErr: true},
+		{value: "cache-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveIdentifier(serverKind, tt.value, candidates)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveIdentifier(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("resolveIdentifier(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+		if tt.ambiguous && !strings.Contains(err.Error(), "use one of these IDs") {
+			t.Errorf("resolveIdentifier(%q) error = %q, want a disambiguation list", tt.value, err)
+		}
+	}
+}
+
+func TestCachedCandidates_ListsOncePerInvocation(t *testing.T) {
+	appCli := newRecordedClient(t, map[string]string{"/servers": "testdata/servers.json"})
+
+	calls := 0
+	kind := &resourceKind{
+		Name:     "test server",
+		IDPrefix: "sv_",
+		List: func(cmd *cobra.Command, _ *client.LatitudeShAPI, scope string) ([]resourceCandidate, error) {
+			calls++
+			return listServerCandidates(cmd, appCli, scope)
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		candidates, err := cachedCandidates(nil, appCli, kind, "")
+		if err != nil {
+			t.Fatalf("cachedCandidates() error = %v", err)
+		}
+
+		id, err := resolveIdentifier(kind, "db-01", candidates)
+		if err != nil || id != "sv_8WbKpO3V5lmq4" {
+			t.Errorf("resolveIdentifier(db-01) = %q, %v", id, err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("servers were listed %d times, want 1", calls)
+	}
+}
+
+func TestCommandIdentifierFlags(t *testing.T) {
+	root := &cobra.Command{Use: "lsh"}
+	group := func(parent *cobra.Command, name string) *cobra.Command {
+		cmd := &cobra.Command{Use: name}
+		parent.AddCommand(cmd)
+		return cmd
+	}
+
+	volume := group(root, "volume")
+	vlans := group(root, "virtual_networks")
+	assignments := group(vlans, "assignments")
+
+	tests := []struct {
+		cmd  *cobra.Command
+		flag string
+		want *resourceKind
+	}{
+		{group(volume, "get"), "id", volumeKind},
+		{group(group(volume, "nqn"), "authorize"), "id", volumeKind},
+		{group(vlans, "destroy"), "id", virtualNetworkKind},
+		{group(assignments, "create"), "server_id", serverKind},
+		{group(assignments, "destroy"), "id", nil},
+		{group(group(root, "plans"), "list"), "id", nil},
+	}
+
+	for _, tt := range tests {
+		if got := commandIdentifierFlags(tt.cmd)[tt.flag]; got != tt.want {
+			t.Errorf("%s --%s resolves %v, want %v", tt.cmd.CommandPath(), tt.flag, got, tt.want)
+		}
+	}
+}
+
+func TestIsResourceID(t *testing.T) {
+	tests := map[string]bool{
+		"sv_2GmAlJ6BXlK1a":  true,
+		"sv_2GmAlJ6BX":      false,
+		"sv_2GmAlJ6BXlK1a1": false,
+		"vol_2GmAlJ6BXlK1a": false,
+		"sv_2GmAlJ6BX-K1a":  false,
+		"web-01":            false,
+	}
+
+	for value, want := range tests {
+		if got := isResourceID(serverKind, value); got != want {
+			t.Errorf("isResourceID(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/latitudesh/lsh/cmd/lsh"
 	"github.com/latitudesh/lsh/internal/cmdflag"
 	"github.com/latitudesh/lsh/internal/utils"
+	"github.com/latitudesh/lsh/models"
 
 	"github.com/spf13/cobra"
 )
@@ -22,13 +23,17 @@ func makeOperationServersServerScheduleDeletionCmd() (*cobra.Command, error) {
 
 type ScheduleServerDeletionOperation struct {
 	PathParamFlags cmdflag.Flags
+	BulkFlags      serverBulkFlags
 }
 
 func (o *ScheduleServerDeletionOperation) Register() (*cobra.Command, error) {
 	cmd := &cobra.Command{
-		Use:    "schedule-deletion",
-		Short:  "Schedule deletion",
-		Long:   "Schedules the server to be removed at the end of the billing cycle.",
+		Use:   "schedule-deletion",
+		Short: "Schedule deletion",
+		Long: `Schedules the server to be removed at the end of the billing cycle.
+
+Use --selector or --all instead of --id to schedule the deletion of every matching server, e.g.:
+  lsh servers schedule-deletion --selector tag=temporary`,
 		RunE:   o.run,
 		PreRun: o.preRun,
 	}
@@ -54,9 +59,14 @@ func (o *ScheduleServerDeletionOperation) registerFlags(cmd *cobra.Command) {
 	}
 
 	o.PathParamFlags.Register(schema)
+	o.BulkFlags.register(cmd)
 }
 
 func (o *ScheduleServerDeletionOperation) preRun(cmd *cobra.Command, args []string) {
+	if o.BulkFlags.enabled() {
+		return
+	}
+
 	o.PathParamFlags.PreRun(cmd, args)
 }
 
@@ -66,6 +76,18 @@ func (o *ScheduleServerDeletionOperation) run(cmd *cobra.Command, args []string)
 		return err
 	}
 
+	if o.BulkFlags.enabled() {
+		return runServerBulkAction(cmd, appCli, &o.BulkFlags, serverBulkAction{
+			Name:        "schedule-deletion",
+			Destructive: true,
+			Run: func(server *models.ServerData) error {
+				params := servers.NewServerScheduleDeletionParams().WithServerID(server.ID)
+				_, err := appCli.Servers.ServerScheduleDeletion(params, nil)
+				return err
+			},
+		})
+	}
+
 	params := servers.NewServerScheduleDeletionParams()
 	o.PathParamFlags.AssignValues(params)
 
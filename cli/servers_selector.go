@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/latitudesh/lsh/client"
+	"github.com/latitudesh/lsh/client/servers"
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/tui"
+	"github.com/latitudesh/lsh/models"
+	"github.com/spf13/cobra"
+)
+
+// serverBulkConcurrency limits how many servers are acted upon at the same time
+const serverBulkConcurrency = 5
+
+// serverPageSize is the number of servers listed per request when paging through every server
+const serverPageSize = 100
+
+// serverSelectorKeys are the keys accepted by --selector
+var serverSelectorKeys = []string{"tag", "site", "project", "status", "plan", "hostname"}
+
+// serverBulkFlags are the flags shared by the commands that can act on many servers
+type serverBulkFlags struct {
+	selector *string
+	all      *bool
+	confirm  *string
+}
+
+// serverBulkAction is an action applied to every server matched by a selector
+type serverBulkAction struct {
+	// Name is the command name, also used as the confirmation phrase
+	Name        string
+	Destructive bool
+	Run         func(server *models.ServerData) error
+}
+
+// serverBulkResult is the outcome of an action on a single server
+type serverBulkResult struct {
+	Server *models.ServerData
+	Err    error
+}
+
+func (f *serverBulkFlags) register(cmd *cobra.Command) {
+	f.selector = cmd.Flags().String("selector", "", fmt.Sprintf("Act on every server matching the selector, e.g. tag=web,site=SAO,project=foo,status=on. Keys: %s", strings.Join(serverSelectorKeys, ", ")))
+	f.all = cmd.Flags().Bool("all", false, "Act on every server of the team")
+	f.confirm = cmd.Flags().String("confirm", "", fmt.Sprintf("Skip the confirmation prompt of --selector/--all by passing the command name, e.g. --confirm %s", cmd.Name()))
+}
+
+// enabled reports whether the command should run against a set of servers instead of --id
+func (f *serverBulkFlags) enabled() bool {
+	return *f.selector != "" || *f.all
+}
+
+// filters validates the flags and returns the parsed selector
+func (f *serverBulkFlags) filters(cmd *cobra.Command) (map[string]string, error) {
+	if cmd.Flags().Changed("id") {
+		return nil, fmt.Errorf("--id cannot be combined with --selector or --all")
+	}
+
+	if *f.selector != "" && *f.all {
+		return nil, fmt.Errorf("--selector and --all cannot be combined")
+	}
+
+	if *f.all {
+		return map[string]string{}, nil
+	}
+
+	return parseServerSelector(*f.selector)
+}
+
+// parseServerSelector parses "key=value,key=value" into a map of filters
+func parseServerSelector(selector string) (map[string]string, error) {
+	filters := make(map[string]string)
+
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(part, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if !found || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid selector %q: expected key=value", part)
+		}
+
+		if !isServerSelectorKey(key) {
+			return nil, fmt.Errorf("unknown selector key %q: valid keys are %s", key, strings.Join(serverSelectorKeys, ", "))
+		}
+
+		if _, ok := filters[key]; ok {
+			return nil, fmt.Errorf("selector key %q is set more than once", key)
+		}
+
+		filters[key] = value
+	}
+
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("selector is empty: use --all to act on every server")
+	}
+
+	return filters, nil
+}
+
+func isServerSelectorKey(key string) bool {
+	for _, k := range serverSelectorKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveServerSelector lists the servers matched by the filters.
+// Tag IDs are filtered by the API, tag names are matched on the returned servers.
+func resolveServerSelector(appCli *client.LatitudeShAPI, filters map[string]string) ([]*models.ServerData, error) {
+	params := servers.NewGetServersParams()
+
+	if value, ok := filters["project"]; ok {
+		params.SetFilterProject(&value)
+	}
+	if value, ok := filters["site"]; ok {
+		params.SetFilterRegion(&value)
+	}
+	if value, ok := filters["status"]; ok {
+		params.SetFilterStatus(&value)
+	}
+	if value, ok := filters["plan"]; ok {
+		params.SetFilterPlan(&value)
+	}
+	if value, ok := filters["hostname"]; ok {
+		params.SetFilterHostname(&value)
+	}
+	tag, hasTag := filters["tag"]
+	if hasTag && strings.HasPrefix(tag, "tag_") {
+		params.SetFilterTags(&tag)
+	}
+
+	serverList, err := listAllServers(appCli, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*models.ServerData
+	for _, server := range serverList {
+		if server == nil || server.Attributes == nil {
+			continue
+		}
+		if hasTag && !serverHasTag(server, tag) {
+			continue
+		}
+		matched = append(matched, server)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].Attributes.Hostname < matched[j].Attributes.Hostname
+	})
+
+	return matched, nil
+}
+
+// listAllServers pages through the servers matching params, as a single request only returns the
+// first page
+func listAllServers(appCli *client.LatitudeShAPI, params *servers.GetServersParams) ([]*models.ServerData, error) {
+	var serverList []*models.ServerData
+	for page := 1; ; page++ {
+		response, err := appCli.Servers.GetServers(params, nil, withServersPage(page, serverPageSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list servers: %w", err)
+		}
+		if response.Payload == nil {
+			return serverList, nil
+		}
+
+		serverList = append(serverList, response.Payload.Data...)
+		if len(response.Payload.Data) < serverPageSize {
+			return serverList, nil
+		}
+	}
+}
+
+// withServersPage requests a page of servers, which the parameters of the generated client lack
+func withServersPage(number, size int) servers.ClientOption {
+	return func(op *runtime.ClientOperation) {
+		params := op.Params
+		op.Params = runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, reg strfmt.Registry) error {
+			if err := params.WriteToRequest(r, reg); err != nil {
+				return err
+			}
+			if err := r.SetQueryParam("page[number]", strconv.Itoa(number)); err != nil {
+				return err
+			}
+			return r.SetQueryParam("page[size]", strconv.Itoa(size))
+		})
+	}
+}
+
+func serverHasTag(server *models.ServerData, tag string) bool {
+	for _, t := range server.Attributes.Tags {
+		if t != nil && (t.Id == tag || strings.EqualFold(t.Name, tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// runServerBulkAction previews the matched servers, asks for confirmation and runs the action on each of them
+func runServerBulkAction(cmd *cobra.Command, appCli *client.LatitudeShAPI, flags *serverBulkFlags, action serverBulkAction) error {
+	filters, err := flags.filters(cmd)
+	if err != nil {
+		return err
+	}
+
+	matched, err := resolveServerSelector(appCli, filters)
+	if err != nil {
+		return err
+	}
+
+	if len(matched) == 0 {
+		fmt.Println("No servers match the selector.")
+		return nil
+	}
+
+	fmt.Printf("The %s action will run on %d server(s):\n\n", action.Name, len(matched))
+	printServerBulkPreview(matched)
+	fmt.Println()
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		return nil
+	}
+
+	confirmed, err := confirmServerBulkAction(cmd, *flags.confirm, action, len(matched))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("Aborted: no servers were changed.")
+		return nil
+	}
+
+	results := executeServerBulkAction(matched, action.Run)
+
+	return printServerBulkSummary(action.Name, results)
+}
+
+func printServerBulkPreview(serverList []*models.ServerData) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tHOSTNAME\tPROJECT\tSITE\tSTATUS")
+
+	for _, server := range serverList {
+		attr := server.Attributes
+
+		project := ""
+		if attr.Project != nil {
+			project = attr.Project.Slug
+		}
+
+		site := attr.Site
+		if attr.Region != nil && attr.Region.Site != nil {
+			site = attr.Region.Site.Slug
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", server.ID, attr.Hostname, project, site, attr.Status)
+	}
+
+	w.Flush()
+}
+
+// confirmServerBulkAction asks for a yes/no answer, or for the action name to be typed when it is destructive
+func confirmServerBulkAction(cmd *cobra.Command, confirm string, action serverBulkAction, count int) (bool, error) {
+	if confirm != "" {
+		if confirm != action.Name {
+			return false, fmt.Errorf("--confirm must be %q to run %s", action.Name, action.Name)
+		}
+		return true, nil
+	}
+
+	if noInput, _ := cmd.Flags().GetBool("no-input"); noInput {
+		return false, fmt.Errorf("confirmation required: pass --confirm %s to run without a prompt", action.Name)
+	}
+
+	if !action.Destructive {
+		return tui.RunConfirm(fmt.Sprintf("Run %s on %d server(s)?", action.Name, count))
+	}
+
+	fmt.Printf("This cannot be undone. Type %q to confirm: ", action.Name)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, nil
+	}
+
+	return strings.TrimSpace(answer) == action.Name, nil
+}
+
+// executeServerBulkAction runs the action on every server, serverBulkConcurrency at a time.
+// Results keep the order of serverList.
+func executeServerBulkAction(serverList []*models.ServerData, run func(server *models.ServerData) error) []serverBulkResult {
+	results := make([]serverBulkResult, len(serverList))
+	semaphore := make(chan struct{}, serverBulkConcurrency)
+
+	var wg sync.WaitGroup
+	for i, server := range serverList {
+		wg.Add(1)
+		go func(i int, server *models.ServerData) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = serverBulkResult{Server: server, Err: run(server)}
+		}(i, server)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func printServerBulkSummary(name string, results []serverBulkResult) error {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Printf("❌ %s (%s): %v\n", result.Server.Attributes.Hostname, result.Server.ID, result.Err)
+			continue
+		}
+		fmt.Printf("✅ %s (%s)\n", result.Server.Attributes.Hostname, result.Server.ID)
+	}
+
+	fmt.Printf("\n%s: %d succeeded, %d failed\n", name, len(results)-failed, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%s failed on %d of %d server(s)", name, failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/latitudesh/lsh/models"
+)
+
+func TestParseServerSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			selector: "tag=web,site=SAO,project=foo,status=on",
+			want:     map[string]string{"tag": "web", "site": "SAO", "project": "foo", "status": "on"},
+		},
+		{
+			selector: " Project = acme-web , ",
+			want:     map[string]string{"project": "acme-web"},
+		},
+		{selector: "", wantErr: true},
+		{selector: "tag", wantErr: true},
+		{selector: "tag=", wantErr: true},
+		{selector: "color=red", wantErr: true},
+		{selector: "tag=web,tag=db", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseServerSelector(tt.selector)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseServerSelector(%q) error = %v, wantErr %v", tt.selector, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseServerSelector(%q) = %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestResolveServerSelector_RecordedServers(t *testing.T) {
+	appCli := newRecordedClient(t, map[string]string{"/servers": "testdata/servers.json"})
+
+	tests := []struct {
+		filters map[string]string
+		want    []string
+	}{
+		{filters: map[string]string{}, want: []string{"db-01", "web-01"}},
+		{filters: map[string]string{"tag": "production api"}, want: []string{"web-01"}},
+		{filters: map[string]string{"tag": "tag_web"}, want: []string{"web-01"}},
+		{filters: map[string]string{"tag": "missing"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		matched, err := resolveServerSelector(appCli, tt.filters)
+		if err != nil {
+			t.Fatalf("resolveServerSelector(%v) error = %v", tt.filters, err)
+		}
+
+		var hostnames []string
+		for _, server := range matched {
+			hostnames = append(hostnames, server.Attributes.Hostname)
+		}
+		if !reflect.DeepEqual(hostnames, tt.want) {
+			t.Errorf("resolveServerSelector(%v) = %v, want %v", tt.filters, hostnames, tt.want)
+		}
+	}
+}
+
+func TestResolveServerSelector_PagesThroughServers(t *testing.T) {
+	const total = serverPageSize + 1
+
+	var requests []string
+	appCli := newTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("page[size]"))
+
+		data := []map[string]interface{}{}
+		for i := (page - 1) * size; i < min(page*size, total); i++ {
+			data = append(data, map[string]interface{}{
+				"id":         fmt.Sprintf("sv_%d", i),
+				"attributes": map[string]interface{}{"hostname": fmt.Sprintf("web-%03d", i)},
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+
+	matched, err := resolveServerSelector(appCli, map[string]string{"project": "acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matched) != total {
+		t.Errorf("matched %d servers, want %d", len(matched), total)
+	}
+	if len(requests) != 2 {
+		t.Errorf("requests = %q, want 2 pages", requests)
+	}
+	for _, query := range requests {
+		if q, _ := url.ParseQuery(query); q.Get("filter[project]") != "acme" {
+			t.Errorf("request %q lost the project filter", query)
+		}
+	}
+}
+
+func TestExecuteServerBulkAction(t *testing.T) {
+	var serverList []*models.ServerData
+	for _, id := range []string{"sv_1", "sv_2", "sv_3", "sv_4", "sv_5", "sv_6", "sv_7"} {
+		serverList = append(serverList, &models.ServerData{ID: id, Attributes: &models.ServerDataAttributes{Hostname: id}})
+	}
+
+	results := executeServerBulkAction(serverList, func(server *models.ServerData) error {
+		if server.ID == "sv_3" {
+			return errors.New("server is locked")
+		}
+		return nil
+	})
+
+	for i, result := range results {
+		if result.Server != serverList[i] {
+			t.Errorf("result %d is for %s, want %s", i, result.Server.ID, serverList[i].ID)
+		}
+		if (result.Err != nil) != (result.Server.ID == "sv_3") {
+			t.Errorf("result for %s has error %v", result.Server.ID, result.Err)
+		}
+	}
+
+	if err := printServerBulkSummary("destroy", results); err == nil {
+		t.Error("printServerBulkSummary() should fail when a server failed")
+	}
+}
@@ -0,0 +1,374 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/latitudesh/lsh/client/servers"
+	"github.com/latitudesh/lsh/client/ssh_keys"
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/latitudesh/lsh/models"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+const (
+	sshConfigBlockBegin = "# BEGIN lsh managed block"
+	sshConfigBlockEnd   = "# END lsh managed block"
+)
+
+func makeOperationServersSSHConfigCmd() (*cobra.Command, error) {
+	operation := ServersSSHConfigOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type ServersSSHConfigOperation struct {
+	QueryParamFlags cmdflag.Flags
+	OptionsFlags    cmdflag.Flags
+}
+
+func (o *ServersSSHConfigOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "ssh-config",
+		Short: "Generate an SSH config block for the servers of a project",
+		Long: `Generate OpenSSH "Host" entries for every server in a project.
+
+Each entry uses the server hostname as alias and its primary IPv4 as HostName.
+The login user is inferred from the operating system, and IdentityFile lines are
+added for every local key in ~/.ssh whose public key is registered in the project.
+
+With --write, only the block managed by lsh for that project is replaced, so the
+command can be re-run safely to keep the file in sync with the fleet.
+
+Example:
+  lsh servers ssh-config --project my-project --write ~/.ssh/config.d/lsh`,
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.registerFlags(cmd)
+
+	return cmd, nil
+}
+
+func (o *ServersSSHConfigOperation) registerFlags(cmd *cobra.Command) {
+	o.QueryParamFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.OptionsFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	queryParamsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "project",
+			Label:       "Project ID or Slug",
+			Description: "The project whose servers will be exported",
+			Required:    true,
+			Env:         true,
+		},
+		&cmdflag.String{
+			Name:        "tag",
+			Label:       "Tag",
+			Description: "Only include servers with this tag ID",
+			Required:    false,
+		},
+	}
+
+	optionsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "user",
+			Label:       "SSH User",
+			Description: "Override the login user inferred from the operating system",
+			Required:    false,
+		},
+		&cmdflag.String{
+			Name:        "write",
+			Label:       "Output File",
+			Description: "Write the managed block to this file (e.g. ~/.ssh/config.d/lsh) instead of stdout",
+			Required:    false,
+		},
+	}
+
+	o.QueryParamFlags.Register(queryParamsSchema)
+	o.OptionsFlags.Register(optionsSchema)
+}
+
+func (o *ServersSSHConfigOperation) preRun(cmd *cobra.Command, args []string) {
+	o.QueryParamFlags.PreRun(cmd, args)
+	o.OptionsFlags.PreRun(cmd, args)
+}
+
+func (o *ServersSSHConfigOperation) run(cmd *cobra.Command, args []string) error {
+	project, _ := cmd.Flags().GetString("project")
+	tag, _ := cmd.Flags().GetString("tag")
+	user, _ := cmd.Flags().GetString("user")
+	writePath, _ := cmd.Flags().GetString("write")
+
+	if project == "" {
+		return fmt.Errorf("--project is required")
+	}
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		return nil
+	}
+
+	appCli, err := makeClient(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	serversParams := servers.NewGetServersParams()
+	serversParams.SetFilterProject(&project)
+	if tag != "" {
+		serversParams.SetFilterTags(&tag)
+	}
+
+	serversResponse, err := appCli.Servers.GetServers(serversParams, nil)
+	if err != nil {
+		// Returned rather than printed, so no error lands in a config redirected from stdout
+		return fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	keysParams := ssh_keys.NewGetProjectSSHKeysParams()
+	keysParams.SetProjectIDOrSlug(project)
+
+	keysResponse, err := appCli.SSHKeys.GetProjectSSHKeys(keysParams, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list the SSH keys of the project: %w", err)
+	}
+
+	sshDir, err := homedir.Expand("~/.ssh")
+	if err != nil {
+		return err
+	}
+
+	var projectKeys []*models.SSHKeyData
+	if keysResponse.Payload != nil {
+		projectKeys = keysResponse.Payload.Data
+	}
+	identityFiles := matchIdentityFiles(projectKeys, sshDir)
+
+	var serverList []*models.ServerData
+	if serversResponse.Payload != nil {
+		serverList = serversResponse.Payload.Data
+	}
+
+	block := buildSSHConfigBlock(project, serverList, identityFiles, user)
+
+	if writePath == "" {
+		fmt.Fprint(os.Stdout, block)
+		return nil
+	}
+
+	path, err := homedir.Expand(writePath)
+	if err != nil {
+		return err
+	}
+
+	if err := writeSSHConfigBlock(path, project, block); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "✅ Wrote %d host entries to %s\n", countSSHHosts(block), path)
+	return nil
+}
+
+// buildSSHConfigBlock renders the managed block with one Host entry per server
+func buildSSHConfigBlock(project string, serverList []*models.ServerData, identityFiles []string, user string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s (project %s)\n", sshConfigBlockBegin, project)
+
+	sorted := make([]*models.ServerData, 0, len(serverList))
+	for _, server := range serverList {
+		if server != nil && server.Attributes != nil {
+			sorted = append(sorted, server)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Attributes.Hostname < sorted[j].Attributes.Hostname
+	})
+
+	for _, server := range sorted {
+		attr := server.Attributes
+		ip := ""
+		if attr.PrimaryIPV4 != nil {
+			ip = *attr.PrimaryIPV4
+		}
+
+		if ip == "" {
+			fmt.Fprintf(&b, "# %s (%s) skipped: no primary IPv4\n", attr.Hostname, server.ID)
+			continue
+		}
+
+		login := user
+		if login == "" {
+			login = sshUserForOS(attr.OperatingSystem)
+		}
+
+		fmt.Fprintf(&b, "Host %s\n", attr.Hostname)
+		fmt.Fprintf(&b, "    HostName %s\n", ip)
+		fmt.Fprintf(&b, "    User %s\n", login)
+		for _, identityFile := range identityFiles {
+			fmt.Fprintf(&b, "    IdentityFile %s\n", identityFile)
+		}
+		if len(identityFiles) > 0 {
+			fmt.Fprintf(&b, "    IdentitiesOnly yes\n")
+		}
+	}
+
+	fmt.Fprintf(&b, "%s (project %s)\n", sshConfigBlockEnd, project)
+
+	return b.String()
+}
+
+// sshUserForOS returns the default login user of an operating system image
+func sshUserForOS(operatingSystem *models.ServerDataAttributesOperatingSystem) string {
+	if operatingSystem == nil {
+		return "root"
+	}
+
+	distro := operatingSystem.Slug
+	if operatingSystem.Distro != nil && operatingSystem.Distro.Slug != "" {
+		distro = operatingSystem.Distro.Slug
+	}
+	distro = strings.ToLower(distro)
+
+	switch {
+	case strings.HasPrefix(distro, "ubuntu"):
+		return "ubuntu"
+	case strings.HasPrefix(distro, "debian"):
+		return "debian"
+	case strings.HasPrefix(distro, "centos"):
+		return "centos"
+	case strings.HasPrefix(distro, "rocky"):
+		return "rocky"
+	case strings.HasPrefix(distro, "alma"):
+		return "almalinux"
+	case strings.HasPrefix(distro, "rhel"):
+		return "cloud-user"
+	case strings.HasPrefix(distro, "flatcar"):
+		return "core"
+	case strings.HasPrefix(distro, "windows"):
+		return "Administrator"
+	}
+
+	return "root"
+}
+
+// matchIdentityFiles returns the private keys in sshDir whose public half is registered in the project
+func matchIdentityFiles(keys []*models.SSHKeyData, sshDir string) []string {
+	registered := make(map[string]bool)
+	for _, key := range keys {
+		if key == nil || key.Attributes == nil {
+			continue
+		}
+		if material := publicKeyMaterial(key.Attributes.PublicKey); material != "" {
+			registered[material] = true
+		}
+	}
+
+	publicKeyFiles, _ := filepath.Glob(filepath.Join(sshDir, "*.pub"))
+	sort.Strings(publicKeyFiles)
+
+	var identityFiles []string
+	for _, publicKeyFile := range publicKeyFiles {
+		content, err := os.ReadFile(publicKeyFile)
+		if err != nil {
+			continue
+		}
+
+		if !registered[publicKeyMaterial(string(content))] {
+			continue
+		}
+
+		privateKeyFile := strings.TrimSuffix(publicKeyFile, ".pub")
+		if _, err := os.Stat(privateKeyFile); err == nil {
+			identityFiles = append(identityFiles, privateKeyFile)
+		}
+	}
+
+	return identityFiles
+}
+
+// publicKeyMaterial strips the comment from an OpenSSH public key, keeping "<type> <base64>"
+func publicKeyMaterial(publicKey string) string {
+	fields := strings.Fields(publicKey)
+	if len(fields) < 2 {
+		return ""
+	}
+
+	return fields[0] + " " + fields[1]
+}
+
+// replaceSSHConfigBlock swaps the managed block of a project inside content, appending it when
+// absent. A begin marker without its end marker is an error, rather than dropping the rest of the file
+func replaceSSHConfigBlock(content, project, block string) (string, error) {
+	begin := fmt.Sprintf("%s (project %s)", sshConfigBlockBegin, project)
+	end := fmt.Sprintf("%s (project %s)", sshConfigBlockEnd, project)
+
+	var out []string
+	inBlock := false
+	replaced := false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.TrimSpace(line) == begin:
+			inBlock = true
+		case inBlock && strings.TrimSpace(line) == end:
+			inBlock = false
+			if !replaced {
+				out = append(out, strings.TrimSuffix(block, "\n"))
+				replaced = true
+			}
+		case !inBlock:
+			out = append(out, line)
+		}
+	}
+
+	if inBlock {
+		return "", fmt.Errorf("%q has no matching %q line: fix the file by hand before writing the block again", begin, end)
+	}
+
+	if !replaced {
+		if len(out) > 0 && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+		out = append(out, strings.TrimSuffix(block, "\n"))
+	}
+
+	return strings.Join(out, "\n") + "\n", nil
+}
+
+// writeSSHConfigBlock rewrites only the managed block of path, leaving the rest of the file untouched
+func writeSSHConfigBlock(path, project, block string) error {
+	err := updateManagedFile(osFilesystem{}, path, 0700, 0600, func(content string) (string, error) {
+		return replaceSSHConfigBlock(content, project, block)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", path, err)
+	}
+	return nil
+}
+
+func countSSHHosts(block string) int {
+	count := 0
+	for _, line := range strings.Split(block, "\n") {
+		if strings.HasPrefix(line, "Host ") {
+			count++
+		}
+	}
+	return count
+}
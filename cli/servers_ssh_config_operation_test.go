@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/latitudesh/lsh/models"
+)
+
+func makeSSHConfigServers() []*models.ServerData {
+	ipv4 := "203.0.113.10"
+
+	return []*models.ServerData{
+		{
+			ID: "sv_web",
+			Attributes: &models.ServerDataAttributes{
+				Hostname:    "web-01",
+				PrimaryIPV4: &ipv4,
+				OperatingSystem: &models.ServerDataAttributesOperatingSystem{
+					Slug:   "ubuntu_22_04_x64_lts",
+					Distro: &models.ServerDataAttributesOperatingSystemDistro{Slug: "ubuntu"},
+				},
+			},
+		},
+		{
+			ID: "sv_pending",
+			Attributes: &models.ServerDataAttributes{
+				Hostname: "pending-01",
+			},
+		},
+	}
+}
+
+func TestBuildSSHConfigBlock(t *testing.T) {
+	block := buildSSHConfigBlock("web", makeSSHConfigServers(), []string{"/home/me/.ssh/id_ed25519"}, "")
+
+	for _, want := range []string{
+		"# BEGIN lsh managed block (project web)",
+		"Host web-01\n    HostName 203.0.113.10\n    User ubuntu\n    IdentityFile /home/me/.ssh/id_ed25519\n    IdentitiesOnly yes\n",
+		"# pending-01 (sv_pending) skipped: no primary IPv4",
+		"# END lsh managed block (project web)",
+	} {
+		if !strings.Contains(block, want) {
+			t.Errorf("block is missing %q:\n%s", want, block)
+		}
+	}
+
+	if got := countSSHHosts(block); got != 1 {
+		t.Errorf("countSSHHosts() = %d, want 1", got)
+	}
+}
+
+func TestReplaceSSHConfigBlock_Idempotent(t *testing.T) {
+	userConfig := "Host personal\n    HostName 198.51.100.1\n"
+	block := buildSSHConfigBlock("web", makeSSHConfigServers(), nil, "root")
+
+	first, err := replaceSSHConfigBlock(userConfig, "web", block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := replaceSSHConfigBlock(first, "web", block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second {
+		t.Errorf("rewriting the same block changed the file:\n--- first\n%s\n--- second\n%s", first, second)
+	}
+	if !strings.HasPrefix(first, userConfig) {
+		t.Errorf("content outside the managed block was not preserved:\n%s", first)
+	}
+
+	updated, err := replaceSSHConfigBlock(second, "web", buildSSHConfigBlock("web", nil, nil, "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(updated, "Host web-01") {
+		t.Errorf("stale host entry survived a rewrite:\n%s", updated)
+	}
+	if strings.Count(updated, sshConfigBlockBegin) != 1 {
+		t.Errorf("expected exactly one managed block:\n%s", updated)
+	}
+}
+
+func TestReplaceSSHConfigBlock_Unterminated(t *testing.T) {
+	userConfig := "# BEGIN lsh managed block (project web)\nHost web-01\n    HostName 203.0.113.10\n\nHost personal\n    HostName 198.51.100.1\n"
+
+	if _, err := replaceSSHConfigBlock(userConfig, "web", buildSSHConfigBlock("web", nil, nil, "root")); err == nil {
+		t.Fatal("replaceSSHConfigBlock() dropped the lines after an unterminated block instead of failing")
+	}
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(userConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeSSHConfigBlock(path, "web", buildSSHConfigBlock("web", nil, nil, "root")); err == nil {
+		t.Fatal("writeSSHConfigBlock() accepted an unterminated block")
+	}
+	if content, _ := os.ReadFile(path); string(content) != userConfig {
+		t.Errorf("the file was changed:\n%s", content)
+	}
+}
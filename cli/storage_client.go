@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"fmt"
+
+	latitudeshgosdk "github.com/latitudesh/latitudesh-go-sdk"
+	"github.com/spf13/viper"
+)
+
+// newStorageClient returns the SDK client used by the storage commands
+func newStorageClient() (*latitudeshgosdk.Latitudesh, error) {
+	// Try both "authorization" and "Authorization" for compatibility with sudo
+	apiKey := viper.GetString("authorization")
+	if apiKey == "" {
+		apiKey = viper.GetString("Authorization")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key not found. Please run 'lsh login <API_KEY>' first")
+	}
+
+	return latitudeshgosdk.New(latitudeshgosdk.WithSecurity(apiKey)), nil
+}
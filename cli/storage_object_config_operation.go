@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+// objectStorageConfigFormats are the S3 clients 'lsh storage object config' writes configuration for
+var objectStorageConfigFormats = []string{"aws", "rclone", "s3cmd"}
+
+const defaultObjectStorageProfile = "latitude"
+
+func makeOperationObjectStorageConfigCmd() (*cobra.Command, error) {
+	operation := ObjectStorageConfigOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type ObjectStorageConfigOperation struct {
+	BodyAttributesFlags cmdflag.Flags
+	OptionsFlags        cmdflag.Flags
+}
+
+func (o *ObjectStorageConfigOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Write the configuration of an S3 client for object storage",
+		Long: `Write the configuration of an S3 client (AWS CLI, rclone or s3cmd) for an object storage
+access key.
+
+Only the section of the profile is replaced, so the rest of the file is left untouched and
+the command can be re-run safely. The default files are:
+  aws     ~/.aws/config               (use with: aws --profile latitude s3 ls)
+  rclone  ~/.config/rclone/rclone.conf (use with: rclone ls latitude:)
+  s3cmd   ~/.s3cfg-latitude            (use with: s3cmd -c ~/.s3cfg-latitude ls)
+
+Example:
+  lsh storage object config --format rclone --endpoint https://s3.example.com \
+    --access-key-id <ACCESS_KEY_ID> --secret-access-key <SECRET_ACCESS_KEY>`,
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.registerFlags(cmd)
+
+	return cmd, nil
+}
+
+func (o *ObjectStorageConfigOperation) registerFlags(cmd *cobra.Command) {
+	o.BodyAttributesFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.OptionsFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	bodyAttributesSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "format",
+			Label:       "Format",
+			Description: "The S3 client to configure",
+			Required:    true,
+			Options:     objectStorageConfigFormats,
+		},
+		&cmdflag.String{
+			Name:        "endpoint",
+			Label:       "Endpoint",
+			Description: "The S3 endpoint of the object storage (e.g. https://s3.example.com)",
+			Required:    true,
+		},
+		&cmdflag.String{
+			Name:        "access-key-id",
+			Label:       "Access Key ID",
+			Description: "The ID of the access key",
+			Required:    true,
+		},
+		&cmdflag.String{
+			Name:        "secret-access-key",
+			Label:       "Secret Access Key",
+			Description: "The secret of the access key",
+			Required:    true,
+		},
+		&cmdflag.String{
+			Name:        "region",
+			Label:       "Region",
+			Description: "The region to sign requests for (optional)",
+			Required:    false,
+		},
+	}
+
+	optionsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "profile",
+			Label:       "Profile",
+			Description: "The name of the profile or remote (default \"latitude\")",
+			Required:    false,
+		},
+		&cmdflag.String{
+			Name:        "file",
+			Label:       "Output File",
+			Description: "Write the configuration to this file instead of the client's default",
+			Required:    false,
+		},
+		&cmdflag.Bool{
+			Name:        "print",
+			Label:       "Print",
+			Description: "Print the configuration to stdout instead of writing it",
+			Required:    false,
+		},
+	}
+
+	o.BodyAttributesFlags.Register(bodyAttributesSchema)
+	o.OptionsFlags.Register(optionsSchema)
+}
+
+func (o *ObjectStorageConfigOperation) preRun(cmd *cobra.Command, args []string) {
+	o.BodyAttributesFlags.PreRun(cmd, args)
+	o.OptionsFlags.PreRun(cmd, args)
+}
+
+// objectStorageCredentials are the settings every S3 client needs
+type objectStorageCredentials struct {
+	Profile         string
+	Endpoint        *url.URL
+	AccessKeyID     string
+	SecretAccessKey string
+	Region          string
+}
+
+// parseObjectStorageEndpoint accepts an endpoint with or without scheme, defaulting to https
+func parseObjectStorageEndpoint(endpoint string) (*url.URL, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid --endpoint %q: expected a URL such as https://s3.example.com", endpoint)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("invalid --endpoint %q: the scheme must be http or https", endpoint)
+	}
+
+	return &url.URL{Scheme: u.Scheme, Host: u.Host}, nil
+}
+
+// objectStorageSection renders the INI section configuring a client, returning its header and body
+func objectStorageSection(format string, creds objectStorageCredentials) (string, string, error) {
+	var b strings.Builder
+	var header string
+
+	switch format {
+	case "aws":
+		header = fmt.Sprintf("[profile %s]", creds.Profile)
+		if creds.Profile == "default" {
+			header = "[default]"
+		}
+		fmt.Fprintf(&b, "aws_access_key_id = %s\n", creds.AccessKeyID)
+		fmt.Fprintf(&b, "aws_secret_access_key = %s\n", creds.SecretAccessKey)
+		fmt.Fprintf(&b, "endpoint_url = %s\n", creds.Endpoint)
+		if creds.Region != "" {
+			fmt.Fprintf(&b, "region = %s\n", creds.Region)
+		}
+	case "rclone":
+		header = fmt.Sprintf("[%s]", creds.Profile)
+		fmt.Fprintf(&b, "type = s3\n")
+		fmt.Fprintf(&b, "provider = Other\n")
+		fmt.Fprintf(&b, "access_key_id = %s\n", creds.AccessKeyID)
+		fmt.Fprintf(&b, "secret_access_key = %s\n", creds.SecretAccessKey)
+		fmt.Fprintf(&b, "endpoint = %s\n", creds.Endpoint)
+		if creds.Region != "" {
+			fmt.Fprintf(&b, "region = %s\n", creds.Region)
+		}
+	case "s3cmd":
+		// s3cmd reads a single [default] section, so each profile gets its own file
+		header = "[default]"
+		fmt.Fprintf(&b, "access_key = %s\n", creds.AccessKeyID)
+		fmt.Fprintf(&b, "secret_key = %s\n", creds.SecretAccessKey)
+		fmt.Fprintf(&b, "host_base = %s\n", creds.Endpoint.Host)
+		fmt.Fprintf(&b, "host_bucket = %s\n", creds.Endpoint.Host)
+		if creds.Endpoint.Scheme == "https" {
+			fmt.Fprintf(&b, "use_https = True\n")
+		} else {
+			fmt.Fprintf(&b, "use_https = False\n")
+		}
+		if creds.Region != "" {
+			fmt.Fprintf(&b, "bucket_location = %s\n", creds.Region)
+		}
+	default:
+		return "", "", fmt.Errorf("unsupported --format %q: use one of %s", format, strings.Join(objectStorageConfigFormats, ", "))
+	}
+
+	return header, b.String(), nil
+}
+
+// defaultObjectStorageConfigPath is the file a client reads its configuration from
+func defaultObjectStorageConfigPath(format, profile string) string {
+	switch format {
+	case "aws":
+		return "~/.aws/config"
+	case "rclone":
+		return "~/.config/rclone/rclone.conf"
+	default:
+		return "~/.s3cfg-" + profile
+	}
+}
+
+// replaceINISection swaps the section starting with header inside content, appending it when absent
+func replaceINISection(content, header, body string) string {
+	section := header + "\n" + strings.TrimSuffix(body, "\n")
+
+	var out []string
+	inSection := false
+	replaced := false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "[") {
+			inSection = trimmed == header
+			if inSection {
+				if !replaced {
+					out = append(out, section)
+					replaced = true
+				}
+				continue
+			}
+			// Keep a blank line between the replaced section and the next one
+			if len(out) > 0 && out[len(out)-1] == section {
+				out = append(out, "")
+			}
+		}
+
+		if !inSection {
+			out = append(out, line)
+		}
+	}
+
+	if !replaced {
+		for len(out) > 0 && out[len(out)-1] == "" {
+			out = out[:len(out)-1]
+		}
+		if len(out) > 0 {
+			out = append(out, "")
+		}
+		out = append(out, section)
+	}
+
+	return strings.Join(out, "\n") + "\n"
+}
+
+// writeINISection rewrites only one section of path, keeping the file private to the user
+func writeINISection(path, header, body string) error {
+	err := updateManagedFile(osFilesystem{}, path, 0700, 0600, func(content string) (string, error) {
+		return replaceINISection(content, header, body), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", path, err)
+	}
+	return nil
+}
+
+func (o *ObjectStorageConfigOperation) run(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	endpoint, _ := cmd.Flags().GetString("endpoint")
+	accessKeyID, _ := cmd.Flags().GetString("access-key-id")
+	secretAccessKey, _ := cmd.Flags().GetString("secret-access-key")
+	region, _ := cmd.Flags().GetString("region")
+	profile, _ := cmd.Flags().GetString("profile")
+	file, _ := cmd.Flags().GetString("file")
+	printOnly, _ := cmd.Flags().GetBool("print")
+
+	if accessKeyID == "" || secretAccessKey == "" {
+		return fmt.Errorf("--access-key-id and --secret-access-key are required")
+	}
+	if profile == "" {
+		profile = defaultObjectStorageProfile
+	}
+
+	endpointURL, err := parseObjectStorageEndpoint(endpoint)
+	if err != nil {
+		return err
+	}
+
+	header, body, err := objectStorageSection(format, objectStorageCredentials{
+		Profile:         profile,
+		Endpoint:        endpointURL,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		Region:          region,
+	})
+	if err != nil {
+		return err
+	}
+
+	if printOnly {
+		fmt.Fprintf(os.Stdout, "%s\n%s", header, body)
+		return nil
+	}
+
+	if file == "" {
+		file = defaultObjectStorageConfigPath(format, profile)
+	}
+	path, err := homedir.Expand(file)
+	if err != nil {
+		return err
+	}
+
+	if err := writeINISection(path, header, body); err != nil {
+		printError(err.Error())
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "✅ Wrote the %s profile %q to %s\n", format, profile, path)
+	return nil
+}
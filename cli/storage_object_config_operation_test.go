@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestObjectStorageSection(t *testing.T) {
+	endpoint, err := parseObjectStorageEndpoint("s3.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds := objectStorageCredentials{
+		Profile:         "latitude",
+		Endpoint:        endpoint,
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "SECRET",
+		Region:          "sao",
+	}
+
+	tests := []struct {
+		format     string
+		wantHeader string
+		wantBody   string
+	}{
+		{
+			format:     "aws",
+			wantHeader: "[profile latitude]",
+			wantBody:   "aws_access_key_id = AKID\naws_secret_access_key = SECRET\nendpoint_url = https://s3.example.com\nregion = sao\n",
+		},
+		{
+			format:     "rclone",
+			wantHeader: "[latitude]",
+			wantBody:   "type = s3\nprovider = Other\naccess_key_id = AKID\nsecret_access_key = SECRET\nendpoint = https://s3.example.com\nregion = sao\n",
+		},
+		{
+			format:     "s3cmd",
+			wantHeader: "[default]",
+			wantBody:   "access_key = AKID\nsecret_key = SECRET\nhost_base = s3.example.com\nhost_bucket = s3.example.com\nuse_https = True\nbucket_location = sao\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			header, body, err := objectStorageSection(tt.format, creds)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if header != tt.wantHeader || body != tt.wantBody {
+				t.Errorf("objectStorageSection() = %q, %q, want %q, %q", header, body, tt.wantHeader, tt.wantBody)
+			}
+		})
+	}
+
+	if _, _, err := objectStorageSection("minio", creds); err == nil {
+		t.Error("objectStorageSection() accepted an unsupported format")
+	}
+}
+
+func TestParseObjectStorageEndpoint(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"s3.example.com":             "https://s3.example.com",
+		"http://localhost:9000/":     "http://localhost:9000",
+		"https://s3.example.com/foo": "https://s3.example.com",
+		"ftp://s3.example.com":       "",
+		"https://":                   "",
+	} {
+		got, err := parseObjectStorageEndpoint(endpoint)
+		if want == "" {
+			if err == nil {
+				t.Errorf("parseObjectStorageEndpoint(%q) = %s, want an error", endpoint, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != want {
+			t.Errorf("parseObjectStorageEndpoint(%q) = %v, %v, want %s", endpoint, got, err, want)
+		}
+	}
+}
+
+func TestWriteINISection_KeepsOtherSections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	existing := "[default]\nregion = us-east-1\n\n[profile latitude]\naws_access_key_id = OLD\n\n[profile other]\nregion = eu-west-1\n"
+	if err := os.WriteFile(path, []byte(existing), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	body := "aws_access_key_id = NEW\n"
+	for i := 0; i < 2; i++ {
+		if err := writeINISection(path, "[profile latitude]", body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[default]\nregion = us-east-1\n\n[profile latitude]\naws_access_key_id = NEW\n\n[profile other]\nregion = eu-west-1\n"
+	if string(content) != want {
+		t.Errorf("config =\n%s\nwant\n%s", content, want)
+	}
+
+	if err := writeINISection(path, "[profile new]", body); err != nil {
+		t.Fatal(err)
+	}
+	content, _ = os.ReadFile(path)
+	if !strings.HasSuffix(string(content), "region = eu-west-1\n\n[profile new]\naws_access_key_id = NEW\n") {
+		t.Errorf("new section not appended:\n%s", content)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("config mode = %v, want 0600", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,536 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	latitudeshgosdk "github.com/latitudesh/latitudesh-go-sdk"
+	"github.com/latitudesh/latitudesh-go-sdk/models/operations"
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/latitudesh/lsh/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// dashboardPageSize is how many records a dashboard pane loads
+const dashboardPageSize int64 = 100
+
+func makeOperationUICmd() (*cobra.Command, error) {
+	operation := UIOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type UIOperation struct {
+	OptionsFlags cmdflag.Flags
+}
+
+func (o *UIOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "ui",
+		Short: "Open the interactive dashboard",
+		Long: `Open a dashboard to browse projects, servers, VLANs, volumes, tags and SSH keys, refreshed periodically.
+
+Switch panes with tab or 1-6, type / to filter and r to refresh. On the servers pane, b reboots,
+i reinstalls with the current operating system, t adds a tag and d schedules the deletion of the
+selected server. Reboot, reinstall and deletion ask for confirmation first.`,
+		Args:   cobra.NoArgs,
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.registerFlags(cmd)
+
+	return cmd, nil
+}
+
+func (o *UIOperation) registerFlags(cmd *cobra.Command) {
+	o.OptionsFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	optionsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "project",
+			Label:       "Project ID or Slug",
+			Description: "Only show the servers, VLANs and volumes of a project",
+			Required:    false,
+			Env:         true,
+		},
+		&cmdflag.Int64{
+			Name:        "refresh",
+			Label:       "Refresh interval",
+			Description: "Seconds between refreshes of the pane on screen, 0 to refresh manually (default 30)",
+			Required:    false,
+			Validators:  []cmdflag.Validator{cmdflag.AtLeast(0)},
+		},
+	}
+
+	o.OptionsFlags.Register(optionsSchema)
+}
+
+func (o *UIOperation) preRun(cmd *cobra.Command, args []string) {
+	o.OptionsFlags.PreRun(cmd, args)
+}
+
+func (o *UIOperation) run(cmd *cobra.Command, args []string) error {
+	project, _ := cmd.Flags().GetString("project")
+	refresh, _ := cmd.Flags().GetInt64("refresh")
+	if !cmd.Flags().Changed("refresh") {
+		refresh = 30
+	}
+	if refresh < 0 {
+		return fmt.Errorf("--refresh must be 0 or more seconds")
+	}
+
+	client, err := newStorageClient()
+	if err != nil {
+		return err
+	}
+
+	var filterProject *string
+	if project != "" {
+		filterProject = &project
+	}
+
+	return tui.RunDashboard(dashboardPanes(client, filterProject), time.Duration(refresh)*time.Second)
+}
+
+// dashboardColumn is a column of a dashboard pane, read from a path in the JSON record such as
+// attributes.region.site.slug
+type dashboardColumn struct {
+	title string
+	width int
+	path  string
+}
+
+// dashboardResource is a resource listed by the dashboard
+type dashboardResource struct {
+	title   string
+	columns []dashboardColumn
+	list    func(ctx context.Context) (*http.Response, error)
+	actions []tui.DashboardAction
+}
+
+func dashboardPanes(client *latitudeshgosdk.Latitudesh, filterProject *string) []tui.DashboardPane {
+	pageSize := dashboardPageSize
+
+	resources := []dashboardResource{
+		{
+			title: "Projects",
+			columns: []dashboardColumn{
+				{title: "ID", width: 22, path: "id"},
+				{title: "Name", width: 24, path: "attributes.name"},
+				{title: "Slug", width: 20, path: "attributes.slug"},
+				{title: "Environment", width: 12, path: "attributes.environment"},
+				{title: "Servers", width: 8, path: "attributes.stats.servers"},
+			},
+			list: func(ctx context.Context) (*http.Response, error) {
+				response, err := client.Projects.List(ctx, operations.GetProjectsRequest{PageSize: &pageSize})
+				if err != nil {
+					return nil, err
+				}
+				return response.HTTPMeta.Response, nil
+			},
+		},
+		{
+			title: "Servers",
+			columns: []dashboardColumn{
+				{title: "ID", width: 18, path: "id"},
+				{title: "Hostname", width: 20, path: "attributes.hostname"},
+				{title: "Status", width: 12, path: "attributes.status"},
+				{title: "Plan", width: 16, path: "attributes.plan.name"},
+				{title: "Site", width: 6, path: "attributes.region.site.slug"},
+				{title: "IPv4", width: 16, path: "attributes.primary_ipv4"},
+				{title: "Project", width: 16, path: "attributes.project.name"},
+				{title: "Tags", width: 20, path: "attributes.tags.name"},
+			},
+			list: func(ctx context.Context) (*http.Response, error) {
+				response, err := client.Servers.List(ctx, operations.GetServersRequest{FilterProject: filterProject, PageSize: &pageSize})
+				if err != nil {
+					return nil, err
+				}
+				return response.HTTPMeta.Response, nil
+			},
+			actions: serverDashboardActions(client),
+		},
+		{
+			title: "VLANs",
+			columns: []dashboardColumn{
+				{title: "ID", width: 18, path: "id"},
+				{title: "VID", width: 6, path: "attributes.vid"},
+				{title: "Description", width: 30, path: "attributes.description"},
+				{title: "Site", width: 6, path: "attributes.region.site.slug"},
+				{title: "Assignments", width: 12, path: "attributes.assignments_count"},
+			},
+			list: func(ctx context.Context) (*http.Response, error) {
+				response, err := client.PrivateNetworks.List(ctx, operations.GetVirtualNetworksRequest{FilterProject: filterProject, PageSize: &pageSize})
+				if err != nil {
+					return nil, err
+				}
+				return response.HTTPMeta.Response, nil
+			},
+		},
+		{
+			title: "Volumes",
+			columns: []dashboardColumn{
+				{title: "ID", width: 18, path: "id"},
+				{title: "Name", width: 24, path: "attributes.name"},
+				{title: "Size (GB)", width: 10, path: "attributes.size_in_gb"},
+				{title: "Status", width: 14, path: "attributes.status"},
+				{title: "Project", width: 16, path: "attributes.project.name"},
+			},
+			list: func(ctx context.Context) (*http.Response, error) {
+				response, err := client.Storage.GetStorageVolumes(ctx, filterProject)
+				if err != nil {
+					return nil, err
+				}
+				return response.HTTPMeta.Response, nil
+			},
+		},
+		{
+			title: "Tags",
+			columns: []dashboardColumn{
+				{title: "ID", width: 18, path: "id"},
+				{title: "Name", width: 24, path: "attributes.name"},
+				{title: "Description", width: 30, path: "attributes.description"},
+				{title: "Color", width: 8, path: "attributes.color"},
+			},
+			list: func(ctx context.Context) (*http.Response, error) {
+				response, err := client.Tags.List(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return response.HTTPMeta.Response, nil
+			},
+		},
+		{
+			title: "SSH Keys",
+			columns: []dashboardColumn{
+				{title: "ID", width: 18, path: "id"},
+				{title: "Name", width: 24, path: "attributes.name"},
+				{title: "Fingerprint", width: 48, path: "attributes.fingerprint"},
+				{title: "Created", width: 26, path: "attributes.created_at"},
+			},
+			list: func(ctx context.Context) (*http.Response, error) {
+				response, err := client.SSHKeys.ListAll(ctx, nil)
+				if err != nil {
+					return nil, err
+				}
+				return response.HTTPMeta.Response, nil
+			},
+		},
+	}
+
+	panes := make([]tui.DashboardPane, len(resources))
+	for i, resource := range resources {
+		panes[i] = resource.pane()
+	}
+	return panes
+}
+
+func (r dashboardResource) pane() tui.DashboardPane {
+	columns := make([]tui.DashboardColumn, len(r.columns))
+	for i, column := range r.columns {
+		columns[i] = tui.DashboardColumn{Title: column.title, Width: column.width}
+	}
+
+	return tui.DashboardPane{
+		Title:   r.title,
+		Columns: columns,
+		Actions: r.actions,
+		Load: func() ([]tui.DashboardRow, error) {
+			response, err := r.list(context.Background())
+			if err != nil {
+				return nil, err
+			}
+			if response == nil {
+				return nil, fmt.Errorf("no response from API")
+			}
+
+			body, err := io.ReadAll(response.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			return dashboardRows(body, r.columns)
+		},
+	}
+}
+
+// dashboardRows decodes the data of a list response into rows. The record is kept on each row for
+// the actions
+func dashboardRows(body []byte, columns []dashboardColumn) ([]tui.DashboardRow, error) {
+	var response struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	rows := make([]tui.DashboardRow, 0, len(response.Data))
+	for _, record := range response.Data {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = jsonPathValue(record, column.path)
+		}
+		rows = append(rows, tui.DashboardRow{ID: jsonPathValue(record, "id"), Cells: cells, Data: record})
+	}
+
+	return rows, nil
+}
+
+// jsonPathValue renders the value at a dot separated path of a decoded JSON record. Lists are
+// joined, so attributes.tags.name lists the names of all the tags
+func jsonPathValue(value interface{}, path string) string {
+	if path == "" {
+		switch v := value.(type) {
+		case nil:
+			return ""
+		case string:
+			return v
+		case float64:
+			return fmt.Sprintf("%g", v)
+		case bool:
+			return fmt.Sprintf("%t", v)
+		case []interface{}:
+			values := make([]string, 0, len(v))
+			for _, item := range v {
+				if s := jsonPathValue(item, ""); s != "" {
+					values = append(values, s)
+				}
+			}
+			return strings.Join(values, ", ")
+		default:
+			encoded, _ := json.Marshal(v)
+			return string(encoded)
+		}
+	}
+
+	key, rest, _ := strings.Cut(path, ".")
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return jsonPathValue(v[key], rest)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s := jsonPathValue(item, path); s != "" {
+				values = append(values, s)
+			}
+		}
+		return strings.Join(values, ", ")
+	}
+
+	return ""
+}
+
+func serverDashboardActions(client *latitudeshgosdk.Latitudesh) []tui.DashboardAction {
+	hostname := func(row tui.DashboardRow) string {
+		if name := jsonPathValue(row.Data, "attributes.hostname"); name != "" {
+			return name
+		}
+		return row.ID
+	}
+
+	return []tui.DashboardAction{
+		{
+			Key:  "b",
+			Name: "reboot",
+			Confirm: func(row tui.DashboardRow) string {
+				return fmt.Sprintf("Reboot %s (%s)?", hostname(row), row.ID)
+			},
+			Run: func(row tui.DashboardRow, _ string) (string, error) {
+				if lsh.DryRun {
+					return fmt.Sprintf("dry-run: skipped reboot of %s.", hostname(row)), nil
+				}
+
+				_, err := client.Servers.RunAction(context.Background(), row.ID, operations.CreateServerActionServersRequestBody{
+					Data: operations.CreateServerActionServersData{
+						Type:       operations.CreateServerActionServersTypeActions,
+						Attributes: &operations.CreateServerActionServersAttributes{Action: operations.CreateServerActionActionReboot},
+					},
+				})
+				if err != nil {
+					return "", fmt.Errorf("failed to reboot %s: %w", hostname(row), err)
+				}
+				return fmt.Sprintf("Rebooting %s.", hostname(row)), nil
+			},
+		},
+		{
+			Key:  "i",
+			Name: "reinstall",
+			Confirm: func(row tui.DashboardRow) string {
+				return fmt.Sprintf("Reinstall %s (%s) with %s? All data on the server is erased.",
+					hostname(row), row.ID, jsonPathValue(row.Data, "attributes.operating_system.slug"))
+			},
+			Run: func(row tui.DashboardRow, _ string) (string, error) {
+				operatingSystem := jsonPathValue(row.Data, "attributes.operating_system.slug")
+				if operatingSystem == "" {
+					return "", fmt.Errorf("%s has no operating system to reinstall, use lsh servers reinstall", hostname(row))
+				}
+				if lsh.DryRun {
+					return fmt.Sprintf("dry-run: skipped reinstall of %s.", hostname(row)), nil
+				}
+
+				name := hostname(row)
+				_, err := client.Servers.Reinstall(context.Background(), row.ID, operations.CreateServerReinstallServersRequestBody{
+					Data: operations.CreateServerReinstallServersData{
+						Type: operations.CreateServerReinstallServersTypeReinstalls,
+						Attributes: &operations.CreateServerReinstallServersAttributes{
+							OperatingSystem: operations.CreateServerReinstallServersOperatingSystem(operatingSystem).ToPointer(),
+							Hostname:        &name,
+						},
+					},
+				})
+				if err != nil {
+					return "", fmt.Errorf("failed to reinstall %s: %w", name, err)
+				}
+				return fmt.Sprintf("Reinstalling %s with %s.", name, operatingSystem), nil
+			},
+		},
+		{
+			Key:    "t",
+			Name:   "tag",
+			Prompt: "Tag name or ID to add",
+			Run: func(row tui.DashboardRow, tag string) (string, error) {
+				tagID, err := resolveDashboardTag(client, tag)
+				if err != nil {
+					return "", err
+				}
+
+				// The API replaces the tags, so they are read again rather than from the row, which may
+				// predate tags added since the last refresh
+				server, err := fetchDashboardServer(client, row.ID)
+				if err != nil {
+					return "", fmt.Errorf("failed to read the tags of %s: %w", hostname(row), err)
+				}
+				row.Data = server
+
+				tagIDs := serverTagIDs(server)
+				for _, id := range tagIDs {
+					if id == tagID {
+						return fmt.Sprintf("%s is already tagged %s.", hostname(row), tag), nil
+					}
+				}
+				if lsh.DryRun {
+					return fmt.Sprintf("dry-run: skipped tagging %s.", hostname(row)), nil
+				}
+
+				// The hostname is sent as is, or the SDK would send its placeholder
+				name := hostname(row)
+				_, err = client.Servers.Update(context.Background(), row.ID, operations.UpdateServerServersRequestBody{
+					Data: &operations.UpdateServerServersData{
+						ID:   &row.ID,
+						Type: operations.UpdateServerServersTypeServers.ToPointer(),
+						Attributes: &operations.UpdateServerServersAttributes{
+							Hostname: &name,
+							Tags:     append(tagIDs, tagID),
+						},
+					},
+				})
+				if err != nil {
+					return "", fmt.Errorf("failed to tag %s: %w", name, err)
+				}
+				return fmt.Sprintf("Tagged %s with %s.", name, tag), nil
+			},
+		},
+		{
+			Key:  "d",
+			Name: "schedule deletion",
+			Confirm: func(row tui.DashboardRow) string {
+				return fmt.Sprintf("Schedule the deletion of %s (%s) at the end of its billing cycle?", hostname(row), row.ID)
+			},
+			Run: func(row tui.DashboardRow, _ string) (string, error) {
+				if lsh.DryRun {
+					return fmt.Sprintf("dry-run: skipped scheduling the deletion of %s.", hostname(row)), nil
+				}
+
+				if _, err := client.Servers.ScheduleDeletion(context.Background(), row.ID); err != nil {
+					return "", fmt.Errorf("failed to schedule the deletion of %s: %w", hostname(row), err)
+				}
+				return fmt.Sprintf("Scheduled the deletion of %s.", hostname(row)), nil
+			},
+		},
+	}
+}
+
+// serverTagIDs returns the IDs of the tags of a decoded server record
+func serverTagIDs(server interface{}) []string {
+	ids := []string{}
+	for _, id := range strings.Split(jsonPathValue(server, "attributes.tags.id"), ", ") {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// fetchDashboardServer reads the current record of a server
+func fetchDashboardServer(client *latitudeshgosdk.Latitudesh, id string) (map[string]interface{}, error) {
+	response, err := client.Servers.Get(context.Background(), id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(response.HTTPMeta.Response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return dashboardRecord(body)
+}
+
+// dashboardRecord decodes the data of a get response
+func dashboardRecord(body []byte) (map[string]interface{}, error) {
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if response.Data == nil {
+		return nil, fmt.Errorf("no record in response")
+	}
+
+	return response.Data, nil
+}
+
+// resolveDashboardTag returns the ID of a tag given by name or ID
+func resolveDashboardTag(client *latitudeshgosdk.Latitudesh, tag string) (string, error) {
+	response, err := client.Tags.List(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	body, err := io.ReadAll(response.HTTPMeta.Response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return findTagID(body, tag)
+}
+
+// findTagID matches a tag by ID, or by name ignoring case, in a tags list response
+func findTagID(body []byte, tag string) (string, error) {
+	rows, err := dashboardRows(body, []dashboardColumn{{path: "attributes.name"}})
+	if err != nil {
+		return "", err
+	}
+
+	for _, row := range rows {
+		if row.ID == tag || strings.EqualFold(row.Cells[0], tag) {
+			return row.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("tag %q not found, create it with lsh tags create", tag)
+}
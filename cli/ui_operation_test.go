@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDashboardRows(t *testing.T) {
+	body, err := os.ReadFile("testdata/servers.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := []dashboardColumn{
+		{path: "attributes.hostname"},
+		{path: "attributes.region.site.slug"},
+		{path: "attributes.tags.name"},
+		{path: "attributes.missing.field"},
+	}
+
+	rows, err := dashboardRows(body, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) < 2 {
+		t.Fatalf("rows = %d, want the servers of the fixture", len(rows))
+	}
+
+	if rows[0].ID != "sv_2GmAlJ6BXlK1a" {
+		t.Errorf("ID = %q, want sv_2GmAlJ6BXlK1a", rows[0].ID)
+	}
+	want := []string{"web-01", "SAO2", "web, Production API", ""}
+	if !reflect.DeepEqual(rows[0].Cells, want) {
+		t.Errorf("cells = %q, want %q", rows[0].Cells, want)
+	}
+	if got := rows[1].Cells[2]; got != "" {
+		t.Errorf("tags of an untagged server = %q, want empty", got)
+	}
+
+	if got := serverTagIDs(rows[0].Data); !reflect.DeepEqual(got, []string{"tag_web", "tag_prod"}) {
+		t.Errorf("serverTagIDs() = %v", got)
+	}
+	if got := serverTagIDs(rows[1].Data); len(got) != 0 {
+		t.Errorf("serverTagIDs() of an untagged server = %v", got)
+	}
+}
+
+func TestDashboardRows_InvalidBody(t *testing.T) {
+	if _, err := dashboardRows([]byte("<html>"), nil); err == nil {
+		t.Fatal("expected an error for a body that is not JSON")
+	}
+}
+
+func TestDashboardRecord(t *testing.T) {
+	body := []byte(`{"data": {"id": "sv_1", "attributes": {"tags": [{"id": "tag_web"}, {"id": "tag_new"}]}}}`)
+
+	record, err := dashboardRecord(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := serverTagIDs(record); !reflect.DeepEqual(got, []string{"tag_web", "tag_new"}) {
+		t.Errorf("serverTagIDs() = %v", got)
+	}
+
+	if _, err := dashboardRecord([]byte(`{"errors": []}`)); err == nil {
+		t.Error("expected an error for a response without a record")
+	}
+}
+
+func TestJSONPathValue(t *testing.T) {
+	record := map[string]interface{}{
+		"id": "vlan_1",
+		"attributes": map[string]interface{}{
+			"vid":      float64(2049),
+			"enabled":  true,
+			"assigned": []interface{}{"sv_1", "sv_2"},
+			"region":   map[string]interface{}{"site": map[string]interface{}{"slug": "SAO2"}},
+		},
+	}
+
+	tests := map[string]string{
+		"id":                          "vlan_1",
+		"attributes.vid":              "2049",
+		"attributes.enabled":          "true",
+		"attributes.assigned":         "sv_1, sv_2",
+		"attributes.region.site.slug": "SAO2",
+		"attributes.region.site":      `{"slug":"SAO2"}`,
+		"attributes.unknown":          "",
+		"id.nested":                   "",
+	}
+
+	for path, want := range tests {
+		if got := jsonPathValue(record, path); got != want {
+			t.Errorf("jsonPathValue(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestFindTagID(t *testing.T) {
+	body := []byte(`{"data": [
+		{"id": "tag_web", "attributes": {"name": "web"}},
+		{"id": "tag_prod", "attributes": {"name": "Production API"}}
+	]}`)
+
+	tests := []struct {
+		tag     string
+		want    string
+		wantErr bool
+	}{
+		{tag: "tag_prod", want: "tag_prod"},
+		{tag: "production api", want: "tag_prod"},
+		{tag: "web", want: "tag_web"},
+		{tag: "db", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := findTagID(body, tt.tag)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("findTagID(%q) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("findTagID(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
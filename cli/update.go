@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"fmt"
 	"log"
+	"os"
 	"runtime"
 
 	"github.com/latitudesh/lsh/internal/updater"
@@ -14,20 +16,60 @@ const (
 	ARCH = runtime.GOARCH
 )
 
+// Exit codes of 'lsh update --check'
+const (
+	updateCheckExitUpToDate  = 0
+	updateCheckExitAvailable = 1
+	updateCheckExitFailed    = 2
+)
+
 func makeOperationUpdateCmd() (*cobra.Command, error) {
 	// updateCmd represents the update command
 	cmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update the CLI to latest version",
-		Long:  `update will verify if a new version is out and update the cli to it.`,
-		RunE:  runOperationUpdate,
+		Long: `update will verify if a new version is out and update the cli to it.
+
+Use --version to install a specific release, for example to downgrade, and --channel prerelease
+to also consider pre-releases. The replaced binary is kept, so 'lsh update rollback' can restore it.
+
+When Homebrew or a deb/rpm package installed lsh, the binary is left alone and the package manager
+command to run is printed instead.
+
+With --check, nothing is installed and the exit code reports the result:
+  0  already at the latest version
+  1  a new version is available
+  2  the check failed`,
+		RunE: runOperationUpdate,
 	}
 
+	cmd.Flags().String("version", "", "Release to install, for example v1.2.3")
+	cmd.Flags().String("channel", updater.ChannelStable, "Release channel to update from: stable or prerelease")
+	cmd.Flags().Bool("check", false, "Only report whether a new version is available")
+
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore the version replaced by the last update",
+		Long:  `rollback restores the previous binary kept by 'lsh update'. Use --version to pick an older backup.`,
+		Args:  cobra.NoArgs,
+		RunE:  runOperationUpdateRollback,
+	}
+	rollbackCmd.Flags().String("version", "", "Backed up version to restore, defaults to the most recent one")
+
+	cmd.AddCommand(rollbackCmd)
+
 	return cmd, nil
 }
 
 func runOperationUpdate(cmd *cobra.Command, args []string) error {
-	latestVersion, err := verifyLatestVersion()
+	targetVersion, _ := cmd.Flags().GetString("version")
+	channel, _ := cmd.Flags().GetString("channel")
+	check, _ := cmd.Flags().GetBool("check")
+
+	latestVersion, err := verifyLatestVersion(targetVersion, channel)
+	if check {
+		return exitWithCode(cmd, updateCheckExitCode(latestVersion, err))
+	}
 	if err != nil {
 		return err
 	}
@@ -44,18 +86,67 @@ func runOperationUpdate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func verifyLatestVersion() (string, error) {
-	release, err := updater.LatestLshRelease()
+// updateCheckExitCode prints the result of 'lsh update --check' and returns its exit code
+func updateCheckExitCode(latestVersion string, err error) int {
+	switch {
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Could not check for updates: %v\n", err)
+		return updateCheckExitFailed
+	case latestVersion == "":
+		fmt.Printf("lsh %s is up to date.\n", version.Version)
+		return updateCheckExitUpToDate
+	default:
+		fmt.Printf("lsh %s is available (current: %s). Run '%s' to install it.\n", latestVersion, version.Version, updater.UpgradeCommand())
+		return updateCheckExitAvailable
+	}
+}
+
+// verifyLatestVersion returns the release to install, or an empty string when it is already
+// installed. targetVersion pins a release, otherwise the newest release of channel is used
+func verifyLatestVersion(targetVersion, channel string) (string, error) {
+	var release *updater.GithubRelease
+	var err error
+
+	if targetVersion != "" {
+		release, err = updater.LshRelease(targetVersion)
+	} else {
+		release, err = updater.LatestLshReleaseOn(channel)
+	}
 	if err != nil {
 		return "", err
 	}
 
 	releaseVersion := release.TagName
 
-	if releaseVersion == version.Version {
+	// A pinned release is installed even when older, that is how a downgrade is done
+	if targetVersion != "" {
+		if releaseVersion == version.Version {
+			return "", nil
+		}
+		log.Printf("Installing %s", releaseVersion)
+		return releaseVersion, nil
+	}
+
+	if updater.IsDevBuild(version.Version) {
+		return "", fmt.Errorf("lsh %s is a development build, use --version to install a release", version.Version)
+	}
+
+	if !updater.IsNewerVersion(releaseVersion, version.Version) {
 		return "", nil
 	}
 
 	log.Printf("New version found: %s", releaseVersion)
 	return releaseVersion, nil
 }
+
+func runOperationUpdateRollback(cmd *cobra.Command, args []string) error {
+	targetVersion, _ := cmd.Flags().GetString("version")
+
+	restored, err := updater.Rollback(targetVersion)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Rolled back from %s to %s.", version.Version, restored)
+	return nil
+}
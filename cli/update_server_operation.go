@@ -4,11 +4,13 @@ import (
 	"fmt"
 
 	"github.com/go-openapi/swag"
+	"github.com/latitudesh/lsh/client"
 	"github.com/latitudesh/lsh/client/servers"
 	"github.com/latitudesh/lsh/cmd/lsh"
 	"github.com/latitudesh/lsh/internal/api/resource"
 	"github.com/latitudesh/lsh/internal/cmdflag"
 	"github.com/latitudesh/lsh/internal/utils"
+	"github.com/latitudesh/lsh/models"
 
 	"github.com/spf13/cobra"
 )
@@ -27,13 +29,17 @@ func makeOperationServersUpdateServerCmd() (*cobra.Command, error) {
 type UpdateServerOperation struct {
 	PathParamFlags      cmdflag.Flags
 	BodyAttributesFlags cmdflag.Flags
+	BulkFlags           serverBulkFlags
 }
 
 func (o *UpdateServerOperation) Register() (*cobra.Command, error) {
 	cmd := &cobra.Command{
-		Use:    "update",
-		Short:  "Update a server",
-		Long:   "Update server information.",
+		Use:   "update",
+		Short: "Update a server",
+		Long: `Update server information.
+
+Use --selector or --all instead of --id to update every matching server, e.g.:
+  lsh servers update --selector site=SAO,status=on --billing monthly`,
 		RunE:   o.run,
 		PreRun: o.preRun,
 	}
@@ -64,6 +70,7 @@ func (o *UpdateServerOperation) registerFlags(cmd *cobra.Command) {
 			Label:       "Hostname",
 			Description: "Hostname",
 			Required:    false,
+			Validators:  []cmdflag.Validator{cmdflag.Hostname()},
 		},
 		&cmdflag.String{
 			Name:        "billing",
@@ -82,13 +89,16 @@ func (o *UpdateServerOperation) registerFlags(cmd *cobra.Command) {
 
 	o.PathParamFlags.Register(pathParamsSchema)
 	o.BodyAttributesFlags.Register(bodyFlagsSchema)
+	o.BulkFlags.register(cmd)
 }
 
 func (o *UpdateServerOperation) PromptQueryParams(params interface{}) {
 }
 
 func (o *UpdateServerOperation) preRun(cmd *cobra.Command, args []string) {
-	o.PathParamFlags.PreRun(cmd, args)
+	if !o.BulkFlags.enabled() {
+		o.PathParamFlags.PreRun(cmd, args)
+	}
 	o.BodyAttributesFlags.PreRun(cmd, args)
 }
 
@@ -98,6 +108,10 @@ func (o *UpdateServerOperation) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if o.BulkFlags.enabled() {
+		return o.runBulk(cmd, appCli)
+	}
+
 	params := servers.NewUpdateServerParams()
 	o.PathParamFlags.AssignValues(params)
 	o.BodyAttributesFlags.AssignValues(params.Body.Data.Attributes)
@@ -124,3 +138,31 @@ func (o *UpdateServerOperation) run(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+func (o *UpdateServerOperation) runBulk(cmd *cobra.Command, appCli *client.LatitudeShAPI) error {
+	if cmd.Flags().Changed("hostname") {
+		return fmt.Errorf("--hostname cannot be used with --selector or --all")
+	}
+
+	attributes := servers.UpdateServerParamsBodyAttributes{}
+	o.BodyAttributesFlags.AssignFlagValues(&attributes)
+
+	if swag.IsZero(attributes) {
+		fmt.Println("Skipped action: no params provided")
+		return nil
+	}
+
+	return runServerBulkAction(cmd, appCli, &o.BulkFlags, serverBulkAction{
+		Name: "update",
+		Run: func(server *models.ServerData) error {
+			params := servers.NewUpdateServerParams()
+			params.ID = server.ID
+			params.Body.Data.ID = server.ID
+			serverAttributes := attributes
+			params.Body.Data.Attributes = &serverAttributes
+
+			_, err := appCli.Servers.UpdateServer(params, nil)
+			return err
+		},
+	})
+}
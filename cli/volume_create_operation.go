@@ -50,6 +50,7 @@ func (o *VolumeCreateOperation) registerFlags(cmd *cobra.Command) {
 			Label:       "Project ID or Slug",
 			Description: "The project to create the volume storage in",
 			Required:    true,
+			Env:         true,
 		},
 		&cmdflag.String{
 			Name:        "plan",
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/latitudesh/lsh/internal/output/table"
+	"github.com/latitudesh/lsh/internal/renderer"
+)
+
+// volumeSortFields are the values accepted by 'lsh volume list --sort'
+var volumeSortFields = []string{"name", "size", "created_at", "location", "plan", "project", "status"}
+
+type Volumes struct {
+	Data []*Volume
+}
+
+func (m *Volumes) GetData() []renderer.ResponseData {
+	var data []renderer.ResponseData
+
+	for _, v := range m.Data {
+		data = append(data, v)
+	}
+
+	return data
+}
+
+// Volume is a volume storage, decoded from the API response and rendered as returned
+type Volume struct {
+	ID         string           `json:"id"`
+	Attributes VolumeAttributes `json:"attributes"`
+
+	raw json.RawMessage
+}
+
+type VolumeAttributes struct {
+	Name        string          `json:"name"`
+	SizeInGb    int64           `json:"size_in_gb"`
+	CreatedAt   string          `json:"created_at"`
+	ConnectorID string          `json:"connector_id"`
+	Status      string          `json:"status"`
+	Plan        volumeReference `json:"plan"`
+	Location    volumeReference `json:"location"`
+	Region      volumeReference `json:"region"`
+	Project     volumeReference `json:"project"`
+	Initiators  []struct {
+		Nqn string `json:"nqn"`
+	} `json:"initiators"`
+}
+
+// volumeReference is a related resource, given either as a string or as an object
+type volumeReference struct {
+	Value string
+}
+
+func (r *volumeReference) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		r.Value = value
+		return nil
+	}
+
+	var object struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+		Site *struct {
+			Slug string `json:"slug"`
+		} `json:"site"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		// Unknown shapes are left blank rather than failing the whole list
+		return nil
+	}
+
+	switch {
+	case object.Site != nil && object.Site.Slug != "":
+		r.Value = object.Site.Slug
+	case object.Slug != "":
+		r.Value = object.Slug
+	case object.Name != "":
+		r.Value = object.Name
+	default:
+		r.Value = object.ID
+	}
+	return nil
+}
+
+func (m *Volume) UnmarshalJSON(data []byte) error {
+	type volume Volume
+	if err := json.Unmarshal(data, (*volume)(m)); err != nil {
+		return err
+	}
+
+	m.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON renders the volume as returned by the API
+func (m *Volume) MarshalJSON() ([]byte, error) {
+	if m.raw != nil {
+		return m.raw, nil
+	}
+
+	type volume Volume
+	return json.Marshal((*volume)(m))
+}
+
+func (m *Volume) GetData() []renderer.ResponseData {
+	return []renderer.ResponseData{m}
+}
+
+// location is the site of the volume
+func (m *Volume) location() string {
+	if m.Attributes.Location.Value != "" {
+		return m.Attributes.Location.Value
+	}
+	return m.Attributes.Region.Value
+}
+
+// attachedHosts counts the client NQNs authorized on the volume
+func (m *Volume) attachedHosts() int {
+	return len(m.Attributes.Initiators)
+}
+
+func (m *Volume) TableRow() table.Row {
+	attr := m.Attributes
+
+	return table.Row{
+		"id": table.Cell{
+			Label: "ID",
+			Value: table.String(m.ID),
+		},
+		"name": table.Cell{
+			Label: "Name",
+			Value: table.String(attr.Name),
+		},
+		"size": table.Cell{
+			Label: "Size (GB)",
+			Value: table.Int(attr.SizeInGb),
+		},
+		"plan": table.Cell{
+			Label: "Plan",
+			Value: table.String(attr.Plan.Value),
+		},
+		"location": table.Cell{
+			Label: "Location",
+			Value: table.String(m.location()),
+		},
+		"project": table.Cell{
+			Label: "Project",
+			Value: table.String(attr.Project.Value),
+		},
+		"connector_id": table.Cell{
+			Label: "Connector ID",
+			Value: table.String(attr.ConnectorID),
+		},
+		"attached_hosts": table.Cell{
+			Label: "Attached Hosts",
+			Value: table.Int(int64(m.attachedHosts())),
+		},
+		"status": table.Cell{
+			Label: "Status",
+			Value: table.String(attr.Status),
+		},
+	}
+}
+
+// volumeFilters are the client-side filters of 'lsh volume list'
+type volumeFilters struct {
+	Location string
+	Plan     string
+	// Attached is "true" for volumes with authorized hosts, "false" for the others, or blank
+	Attached string
+}
+
+// filterVolumes keeps the volumes matching every filter
+func filterVolumes(volumes []*Volume, filters volumeFilters) ([]*Volume, error) {
+	if filters.Attached != "" && filters.Attached != "true" && filters.Attached != "false" {
+		return nil, fmt.Errorf("--attached must be true or false")
+	}
+
+	var filtered []*Volume
+	for _, volume := range volumes {
+		if filters.Location != "" && !strings.EqualFold(volume.location(), filters.Location) {
+			continue
+		}
+		if filters.Plan != "" && !strings.EqualFold(volume.Attributes.Plan.Value, filters.Plan) {
+			continue
+		}
+		if filters.Attached != "" && (volume.attachedHosts() > 0) != (filters.Attached == "true") {
+			continue
+		}
+		filtered = append(filtered, volume)
+	}
+
+	return filtered, nil
+}
+
+// sortVolumes orders the volumes by one of volumeSortFields, keeping the API order for ties
+func sortVolumes(volumes []*Volume, field string, descending bool) error {
+	var less func(a, b *Volume) bool
+	switch field {
+	case "":
+		return nil
+	case "name":
+		less = func(a, b *Volume) bool {
+			return strings.ToLower(a.Attributes.Name) < strings.ToLower(b.Attributes.Name)
+		}
+	case "size":
+		less = func(a, b *Volume) bool { return a.Attributes.SizeInGb < b.Attributes.SizeInGb }
+	case "created_at":
+		less = func(a, b *Volume) bool { return a.Attributes.CreatedAt < b.Attributes.CreatedAt }
+	case "location":
+		less = func(a, b *Volume) bool { return a.location() < b.location() }
+	case "plan":
+		less = func(a, b *Volume) bool { return a.Attributes.Plan.Value < b.Attributes.Plan.Value }
+	case "project":
+		less = func(a, b *Volume) bool { return a.Attributes.Project.Value < b.Attributes.Project.Value }
+	case "status":
+		less = func(a, b *Volume) bool { return a.Attributes.Status < b.Attributes.Status }
+	default:
+		return fmt.Errorf("unsupported --sort %q: use one of %s", field, strings.Join(volumeSortFields, ", "))
+	}
+
+	sort.SliceStable(volumes, func(i, j int) bool {
+		if descending {
+			return less(volumes[j], volumes[i])
+		}
+		return less(volumes[i], volumes[j])
+	})
+	return nil
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func loadTestVolumes(t *testing.T) []*Volume {
+	t.Helper()
+
+	var response struct {
+		Data []*Volume `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(readFixture(t, "testdata/volumes.json")), &response); err != nil {
+		t.Fatal(err)
+	}
+	return response.Data
+}
+
+func TestVolumeTableRow(t *testing.T) {
+	volumes := loadTestVolumes(t)
+
+	row := volumes[0].TableRow()
+	want := map[string]string{
+		"id":             "vol_ZdKRPHylXqOa4",
+		"name":           "postgres-data",
+		"size":           "500",
+		"plan":           "storage-nvme",
+		"location":       "SAO2",
+		"project":        "acme-web",
+		"connector_id":   testSubsystemNQN,
+		"attached_hosts": "1",
+		"status":         "active",
+	}
+	for id, value := range want {
+		if row[id].Value != value {
+			t.Errorf("TableRow()[%s] = %q, want %q", id, row[id].Value, value)
+		}
+	}
+
+	if location := volumes[1].TableRow()["location"].Value; location != "DAL" {
+		t.Errorf("TableRow()[location] = %q, want DAL", location)
+	}
+}
+
+func TestVolumeMarshalJSON_KeepsAPIFields(t *testing.T) {
+	volumes := loadTestVolumes(t)
+
+	output, err := json.Marshal(volumes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), `"namespace_id":1`) || !strings.Contains(string(output), `"city":"São Paulo"`) {
+		t.Errorf("MarshalJSON() dropped API fields: %s", output)
+	}
+}
+
+func TestFilterAndSortVolumes(t *testing.T) {
+	tests := []struct {
+		name       string
+		filters    volumeFilters
+		sort       string
+		descending bool
+		want       []string
+		wantErr    bool
+	}{
+		{name: "no filter", want: []string{"vol_ZdKRPHylXqOa4", "vol_8Wb3KpOQ5lmq4"}},
+		{name: "location", filters: volumeFilters{Location: "sao2"}, want: []string{"vol_ZdKRPHylXqOa4"}},
+		{name: "plan", filters: volumeFilters{Plan: "storage-nvme"}, want: []string{"vol_ZdKRPHylXqOa4", "vol_8Wb3KpOQ5lmq4"}},
+		{name: "not attached", filters: volumeFilters{Attached: "false"}, want: []string{"vol_8Wb3KpOQ5lmq4"}},
+		{name: "sorted by name", sort: "name", want: []string{"vol_8Wb3KpOQ5lmq4", "vol_ZdKRPHylXqOa4"}},
+		{name: "largest first", sort: "size", descending: true, want: []string{"vol_8Wb3KpOQ5lmq4", "vol_ZdKRPHylXqOa4"}},
+		{name: "oldest first", sort: "created_at", want: []string{"vol_8Wb3KpOQ5lmq4", "vol_ZdKRPHylXqOa4"}},
+		{name: "invalid attached", filters: volumeFilters{Attached: "yes"}, wantErr: true},
+		{name: "invalid sort", sort: "color", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			volumes, err := filterVolumes(loadTestVolumes(t), tt.filters)
+			if err == nil {
+				err = sortVolumes(volumes, tt.sort, tt.descending)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, volume := range volumes {
+				got = append(got, volume.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("volumes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
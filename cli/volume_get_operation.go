@@ -7,7 +7,6 @@ import (
 	latitudeshgosdk "github.com/latitudesh/latitudesh-go-sdk"
 	"github.com/latitudesh/lsh/cmd/lsh"
 	"github.com/latitudesh/lsh/internal/cmdflag"
-	"github.com/latitudesh/lsh/internal/output"
 	"github.com/latitudesh/lsh/internal/utils"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -86,26 +85,21 @@ func (o *VolumeGetOperation) run(cmd *cobra.Command, args []string) error {
 
 	// NOTE: The SDK doesn't seem to have a GetStorageVolume (singular) method yet
 	// For now, use list and filter by ID
-	response, err := client.Storage.GetStorageVolumes(ctx, nil)
+	data, err := fetchVolumeData(ctx, client, nil)
 	if err != nil {
-		utils.PrintError(err)
 		return nil
 	}
 
 	// Filter the response to find the matching volume
-	if response != nil && response.Object != nil && response.Object.Data != nil {
-		for _, volume := range response.Object.Data {
-			if volume.ID != nil && *volume.ID == volumeID {
-				if !lsh.Debug {
-					// Display single volume as JSON
-					output.RenderAsJSON(volume)
-				}
-				return nil
+	for _, volume := range data {
+		if volume.ID == volumeID {
+			if !lsh.Debug {
+				utils.Render(volume.GetData())
 			}
+			return nil
 		}
-		// Volume not found
-		return fmt.Errorf("volume with ID '%s' not found", volumeID)
 	}
 
-	return fmt.Errorf("no data returned from API")
+	// Volume not found
+	return fmt.Errorf("volume with ID '%s' not found", volumeID)
 }
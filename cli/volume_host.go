@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	hostNQNPath        = "/etc/nvme/hostnqn"
+	procMountsPath     = "/proc/mounts"
+	sysfsRoot          = "/sys"
+	multipathParamPath = "/sys/module/nvme_core/parameters/multipath"
+)
+
+var (
+	listSubsysSubsystemPattern  = regexp.MustCompile(`^(nvme-subsys\d+)\s+-\s+NQN=(\S+)`)
+	listSubsysControllerPattern = regexp.MustCompile(`^[\s\\|]*[+\\]-\s+(nvme\d+)\s+(\S+)\s*(.*)$`)
+	shellSafePattern            = regexp.MustCompile(`^[A-Za-z0-9_./:=,@%+-]+$`)
+)
+
+// commandExecutor runs the system commands of the volume workflows
+type commandExecutor interface {
+	// Run executes the command and returns its trimmed combined output
+	Run(name string, args ...string) (string, error)
+	// LookPath reports where an installed command is
+	LookPath(name string) (string, error)
+}
+
+// hostFilesystem is the part of the filesystem the volume workflows read and write
+type hostFilesystem interface {
+	ReadFile(path string) ([]byte, error)
+	// WriteFile atomically replaces the content of path
+	WriteFile(path string, data []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+	Remove(path string) error
+	Stat(path string) (os.FileInfo, error)
+	ReadDir(path string) ([]os.DirEntry, error)
+	Glob(pattern string) ([]string, error)
+}
+
+// volumeHost is the server a volume is mounted on
+type volumeHost struct {
+	exec   commandExecutor
+	fs     hostFilesystem
+	sleep  func(time.Duration)
+	dryRun bool
+}
+
+// newVolumeHost returns the local host, or a host printing the command plan when dryRun is set
+func newVolumeHost(dryRun bool) *volumeHost {
+	if dryRun {
+		fmt.Fprintf(os.Stdout, "[DRY-RUN] Nothing will be changed. Commands and file changes are printed instead.\n")
+		return &volumeHost{
+			exec:   dryRunExecutor{out: os.Stdout},
+			fs:     dryRunFilesystem{out: os.Stdout},
+			sleep:  func(time.Duration) {},
+			dryRun: true,
+		}
+	}
+
+	return &volumeHost{
+		exec:  systemExecutor{},
+		fs:    osFilesystem{},
+		sleep: time.Sleep,
+	}
+}
+
+// systemExecutor runs commands on the local host
+type systemExecutor struct{}
+
+func (systemExecutor) Run(name string, args ...string) (string, error) {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	return strings.TrimSpace(string(output)), err
+}
+
+func (systemExecutor) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+// osFilesystem is the local filesystem
+type osFilesystem struct{}
+
+func (osFilesystem) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (osFilesystem) WriteFile(path string, data []byte, perm os.FileMode) error {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp := path + ".lsh.tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func (osFilesystem) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (osFilesystem) Remove(path string) error {
+	return os.Remove(path)
+}
+
+func (osFilesystem) Stat(path string) (os.FileInfo, error) {
+	return os.Stat(path)
+}
+
+func (osFilesystem) ReadDir(path string) ([]os.DirEntry, error) {
+	return os.ReadDir(path)
+}
+
+func (osFilesystem) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
+
+// dryRunExecutor prints the commands instead of running them
+type dryRunExecutor struct {
+	out io.Writer
+}
+
+func (e dryRunExecutor) Run(name string, args ...string) (string, error) {
+	fmt.Fprintf(e.out, "[DRY-RUN] $ %s\n", shellCommand(name, args...))
+	return "", nil
+}
+
+func (e dryRunExecutor) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+// dryRunFilesystem reads the local filesystem and prints the changes instead of making them
+type dryRunFilesystem struct {
+	osFilesystem
+	out io.Writer
+}
+
+func (f dryRunFilesystem) WriteFile(path string, data []byte, perm os.FileMode) error {
+	fmt.Fprintf(f.out, "[DRY-RUN] write %s:\n", path)
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		fmt.Fprintln(f.out, strings.TrimRight("[DRY-RUN]   "+line, " "))
+	}
+	return nil
+}
+
+func (f dryRunFilesystem) MkdirAll(path string, perm os.FileMode) error {
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintf(f.out, "[DRY-RUN] $ %s\n", shellCommand("mkdir", "-p", path))
+	}
+	return nil
+}
+
+func (f dryRunFilesystem) Remove(path string) error {
+	fmt.Fprintf(f.out, "[DRY-RUN] $ %s\n", shellCommand("rm", path))
+	return nil
+}
+
+// shellCommand formats a command the way it would be typed in a shell
+func shellCommand(name string, args ...string) string {
+	parts := []string{name}
+	for _, arg := range args {
+		if !shellSafePattern.MatchString(arg) {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// nvmeSubsystem is a subsystem listed by nvme list-subsys
+type nvmeSubsystem struct {
+	Name        string
+	NQN         string
+	Controllers []nvmeController
+}
+
+// nvmeController is a path to a subsystem
+type nvmeController struct {
+	Name      string `json:"name"`
+	Transport string `json:"transport"`
+	Address   string `json:"address"`
+	ServiceID string `json:"service_id"`
+	State     string `json:"state"`
+}
+
+// parseListSubsys parses the output of nvme list-subsys, as printed by nvme-cli 1.x and 2.x
+func parseListSubsys(output string) []nvmeSubsystem {
+	var subsystems []nvmeSubsystem
+
+	for _, line := range strings.Split(output, "\n") {
+		if match := listSubsysSubsystemPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			subsystems = append(subsystems, nvmeSubsystem{Name: match[1], NQN: match[2]})
+			continue
+		}
+
+		match := listSubsysControllerPattern.FindStringSubmatch(line)
+		if match == nil || len(subsystems) == 0 {
+			continue
+		}
+
+		controller := nvmeController{Name: match[1], Transport: match[2]}
+		for _, field := range strings.FieldsFunc(match[3], func(r rune) bool { return r == ',' || r == ' ' }) {
+			key, value, found := strings.Cut(field, "=")
+			switch {
+			case !found:
+				controller.State = field
+			case key == "traddr":
+				controller.Address = value
+			case key == "trsvcid":
+				controller.ServiceID = value
+			}
+		}
+
+		current := &subsystems[len(subsystems)-1]
+		current.Controllers = append(current.Controllers, controller)
+	}
+
+	return subsystems
+}
+
+// findSubsystem returns the subsystem with the given NQN
+func findSubsystem(subsystems []nvmeSubsystem, nqn string) (nvmeSubsystem, bool) {
+	for _, subsystem := range subsystems {
+		if subsystem.NQN == nqn {
+			return subsystem, true
+		}
+	}
+	return nvmeSubsystem{}, false
+}
+
+// listSubsystems runs nvme list-subsys, which fails when no subsystem is connected
+func (h *volumeHost) listSubsystems() []nvmeSubsystem {
+	output, err := h.exec.Run("nvme", "list-subsys")
+	if err != nil {
+		return nil
+	}
+	return parseListSubsys(output)
+}
+
+// readMounts returns the mounted filesystems
+func (h *volumeHost) readMounts() ([]mountEntry, error) {
+	content, err := h.fs.ReadFile(procMountsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", procMountsPath, err)
+	}
+	return parseMounts(string(content)), nil
+}
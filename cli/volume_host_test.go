@@ -0,0 +1,352 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeExecutor answers commands from canned results and records what was run
+type fakeExecutor struct {
+	results   map[string]fakeResult
+	installed map[string]bool
+	calls     []string
+}
+
+type fakeResult struct {
+	output string
+	err    error
+}
+
+// fakeExitError is a command exiting with a non-zero code
+type fakeExitError int
+
+func (e fakeExitError) Error() string { return fmt.Sprintf("exit status %d", int(e)) }
+func (e fakeExitError) ExitCode() int { return int(e) }
+
+func (e *fakeExecutor) Run(name string, args ...string) (string, error) {
+	command := shellCommand(name, args...)
+	e.calls = append(e.calls, command)
+	result := e.results[command]
+	return result.output, result.err
+}
+
+func (e *fakeExecutor) LookPath(name string) (string, error) {
+	if e.installed[name] {
+		return "/usr/sbin/" + name, nil
+	}
+	return "", exec.ErrNotFound
+}
+
+// rootedFilesystem serves absolute paths from a temporary directory
+type rootedFilesystem struct {
+	root string
+}
+
+func (f rootedFilesystem) path(path string) string {
+	return filepath.Join(f.root, path)
+}
+
+func (f rootedFilesystem) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(f.path(path))
+}
+
+func (f rootedFilesystem) WriteFile(path string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(f.path(path), data, perm)
+}
+
+func (f rootedFilesystem) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(f.path(path), perm)
+}
+
+func (f rootedFilesystem) Remove(path string) error {
+	return os.Remove(f.path(path))
+}
+
+func (f rootedFilesystem) Stat(path string) (os.FileInfo, error) {
+	return os.Stat(f.path(path))
+}
+
+func (f rootedFilesystem) ReadDir(path string) ([]os.DirEntry, error) {
+	return os.ReadDir(f.path(path))
+}
+
+func (f rootedFilesystem) Glob(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(f.path(pattern))
+	for i, match := range matches {
+		matches[i] = strings.TrimPrefix(match, f.root)
+	}
+	return matches, err
+}
+
+// newTestVolumeHost returns a host running canned commands on a temporary root filesystem
+func newTestVolumeHost(t *testing.T, results map[string]fakeResult) (*volumeHost, *fakeExecutor, rootedFilesystem) {
+	t.Helper()
+
+	executor := &fakeExecutor{results: results, installed: map[string]bool{"nvme": true}}
+	fs := rootedFilesystem{root: t.TempDir()}
+
+	return &volumeHost{exec: executor, fs: fs, sleep: func(time.Duration) {}}, executor, fs
+}
+
+func writeTestFile(t *testing.T, fs rootedFilesystem, path, content string) {
+	t.Helper()
+
+	if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFixture(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestParseListSubsys(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		want    []nvmeSubsystem
+	}{
+		{
+			name:    "nvme-cli 1.x",
+			fixture: "testdata/nvme-list-subsys-v1.txt",
+			want: []nvmeSubsystem{
+				{
+					Name:        "nvme-subsys0",
+					NQN:         "nqn.2014.08.org.nvmexpress:80868086PHLJ9401008Y1P0FGN",
+					Controllers: []nvmeController{{Name: "nvme0", Transport: "pcie", State: "live"}},
+				},
+				{
+					Name: "nvme-subsys1",
+					NQN:  testSubsystemNQN,
+					Controllers: []nvmeController{
+						{Name: "nvme1", Transport: "tcp", Address: "67.213.118.147", ServiceID: "4420", State: "live"},
+					},
+				},
+			},
+		},
+		{
+			name:    "nvme-cli 2.x with multiple paths",
+			fixture: "testdata/nvme-list-subsys-v2.txt",
+			want: []nvmeSubsystem{
+				{
+					Name: "nvme-subsys1",
+					NQN:  testSubsystemNQN,
+					Controllers: []nvmeController{
+						{Name: "nvme1", Transport: "tcp", Address: "67.213.118.147", ServiceID: "4420", State: "live"},
+						{Name: "nvme2", Transport: "tcp", Address: "67.213.118.148", ServiceID: "4420", State: "connecting"},
+					},
+				},
+			},
+		},
+		{
+			name: "no subsystem connected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := ""
+			if tt.fixture != "" {
+				output = readFixture(t, tt.fixture)
+			}
+
+			got := parseListSubsys(output)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseListSubsys() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisconnectExisting(t *testing.T) {
+	tests := []struct {
+		name           string
+		listSubsys     fakeResult
+		wantDisconnect bool
+	}{
+		{
+			name:           "connected with nvme-cli 1.x",
+			listSubsys:     fakeResult{output: readFixture(t, "testdata/nvme-list-subsys-v1.txt")},
+			wantDisconnect: true,
+		},
+		{
+			name:           "connected with nvme-cli 2.x",
+			listSubsys:     fakeResult{output: readFixture(t, "testdata/nvme-list-subsys-v2.txt")},
+			wantDisconnect: true,
+		},
+		{
+			name:       "not connected",
+			listSubsys: fakeResult{err: fakeExitError(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, executor, _ := newTestVolumeHost(t, map[string]fakeResult{"nvme list-subsys": tt.listSubsys})
+
+			host.disconnectExisting(testSubsystemNQN)
+
+			disconnect := "nvme disconnect -n " + testSubsystemNQN
+			gotDisconnect := len(executor.calls) == 2 && executor.calls[1] == disconnect
+			if gotDisconnect != tt.wantDisconnect {
+				t.Errorf("commands = %q, want disconnect %t", executor.calls, tt.wantDisconnect)
+			}
+		})
+	}
+}
+
+func TestMountVolumeFilesystem(t *testing.T) {
+	const (
+		device     = "/dev/nvme1n1"
+		mountpoint = "/mnt/data"
+	)
+
+	tests := []struct {
+		name     string
+		format   string
+		force    bool
+		fsType   fakeResult
+		mounts   string
+		wantCmds []string
+		wantErr  bool
+	}{
+		{
+			name:   "formats a blank device",
+			format: "xfs",
+			fsType: fakeResult{err: fakeExitError(2)},
+			wantCmds: []string{
+				"blkid -p -o export " + device,
+				"mkfs.xfs " + device,
+				"blkid -p -o value -s UUID " + device,
+				"mount -t xfs " + device + " " + mountpoint,
+			},
+		},
+		{
+			name:   "keeps an existing filesystem",
+			format: "xfs",
+			fsType: fakeResult{output: "DEVNAME=" + device + "\nUUID=0b7e3f5c\nBLOCK_SIZE=4096\nTYPE=ext4"},
+			wantCmds: []string{
+				"blkid -p -o export " + device,
+				"blkid -p -o value -s UUID " + device,
+				"mount -t ext4 " + device + " " + mountpoint,
+			},
+		},
+		{
+			name:   "already mounted",
+			fsType: fakeResult{output: "TYPE=ext4"},
+			mounts: device + " " + mountpoint + " ext4 rw,relatime 0 0\n",
+			wantCmds: []string{
+				"blkid -p -o export " + device,
+				"blkid -p -o value -s UUID " + device,
+			},
+		},
+		{
+			name:    "partition table without a filesystem",
+			format:  "xfs",
+			fsType:  fakeResult{output: "DEVNAME=" + device + "\nPTUUID=5e3c1f2a\nPTTYPE=gpt"},
+			wantErr: true,
+		},
+		{
+			name:   "partition table formatted with --force",
+			format: "xfs",
+			force:  true,
+			fsType: fakeResult{output: "PTUUID=5e3c1f2a\nPTTYPE=gpt"},
+			wantCmds: []string{
+				"blkid -p -o export " + device,
+				"mkfs.xfs -f " + device,
+				"blkid -p -o value -s UUID " + device,
+				"mount -t xfs " + device + " " + mountpoint,
+			},
+		},
+		{
+			name:    "blank device without --format",
+			fsType:  fakeResult{err: fakeExitError(2)},
+			wantErr: true,
+		},
+		{
+			name:    "mountpoint used by another device",
+			fsType:  fakeResult{output: "TYPE=ext4"},
+			mounts:  "/dev/sdb1 " + mountpoint + " ext4 rw,relatime 0 0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, executor, fs := newTestVolumeHost(t, map[string]fakeResult{
+				"blkid -p -o export " + device:        tt.fsType,
+				"blkid -p -o value -s UUID " + device: {output: "0b7e3f5c-7c1a-4d4e-9a57-5c2f0e6c9b1d"},
+			})
+			writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys1/subsysnqn", testSubsystemNQN+"\n")
+			writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys1/nvme1n1/size", "0")
+			writeTestFile(t, fs, procMountsPath, tt.mounts)
+
+			got, err := host.mountVolumeFilesystem(testSubsystemNQN, tt.format, mountpoint, tt.force)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("mountVolumeFilesystem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(executor.calls, tt.wantCmds) {
+				t.Errorf("commands = %q, want %q", executor.calls, tt.wantCmds)
+			}
+			if got.UUID != "0b7e3f5c-7c1a-4d4e-9a57-5c2f0e6c9b1d" || got.Mountpoint != mountpoint {
+				t.Errorf("mountVolumeFilesystem() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestDryRunPlan(t *testing.T) {
+	var out bytes.Buffer
+	host := &volumeHost{
+		exec:   dryRunExecutor{out: &out},
+		fs:     dryRunFilesystem{out: &out},
+		sleep:  func(time.Duration) {},
+		dryRun: true,
+	}
+
+	if err := host.connectNVMeoF([]nvmePortal{{Address: "67.213.118.147", ServiceID: "4420"}}, testSubsystemNQN, []string{"--ctrl-loss-tmo=-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := host.fs.WriteFile("/etc/nvme/hostnqn", []byte("nqn.2014-08.org.nvmexpress:uuid:host\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := host.verifyConnection(testSubsystemNQN, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[DRY-RUN] $ nvme connect -t tcp -a 67.213.118.147 -s 4420 -n " + testSubsystemNQN + " --ctrl-loss-tmo=-1\n" +
+		"[DRY-RUN] write /etc/nvme/hostnqn:\n" +
+		"[DRY-RUN]   nqn.2014-08.org.nvmexpress:uuid:host\n" +
+		"[DRY-RUN] $ nvme list-subsys\n"
+	if out.String() != want {
+		t.Errorf("dry-run plan =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestShellCommand(t *testing.T) {
+	got := shellCommand("sh", "-c", "echo 'hi'")
+	want := `sh -c 'echo '\''hi'\'''`
+	if got != want {
+		t.Errorf("shellCommand() = %s, want %s", got, want)
+	}
+}
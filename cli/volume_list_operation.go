@@ -3,11 +3,12 @@ package cli
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	latitudeshgosdk "github.com/latitudesh/latitudesh-go-sdk"
 	"github.com/latitudesh/lsh/cmd/lsh"
 	"github.com/latitudesh/lsh/internal/cmdflag"
-	"github.com/latitudesh/lsh/internal/output"
+	"github.com/latitudesh/lsh/internal/renderer"
 	"github.com/latitudesh/lsh/internal/utils"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -26,13 +27,14 @@ func makeOperationVolumeListCmd() (*cobra.Command, error) {
 
 type VolumeListOperation struct {
 	QueryParamFlags cmdflag.Flags
+	OptionsFlags    cmdflag.Flags
 }
 
 func (o *VolumeListOperation) Register() (*cobra.Command, error) {
 	cmd := &cobra.Command{
 		Use:    "list",
 		Short:  "List all volume storages",
-		Long:   "List all volume storages for your team, optionally filtered by project",
+		Long:   "List all volume storages for your team, optionally filtered by project, location, plan or attached hosts, and sorted",
 		RunE:   o.run,
 		PreRun: o.preRun,
 	}
@@ -44,6 +46,7 @@ func (o *VolumeListOperation) Register() (*cobra.Command, error) {
 
 func (o *VolumeListOperation) registerFlags(cmd *cobra.Command) {
 	o.QueryParamFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.OptionsFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
 
 	queryParamsSchema := &cmdflag.FlagsSchema{
 		&cmdflag.String{
@@ -51,20 +54,72 @@ func (o *VolumeListOperation) registerFlags(cmd *cobra.Command) {
 			Label:       "Project ID or Slug",
 			Description: "Filter volume storages by project ID or slug",
 			Required:    false,
+			Env:         true,
+		},
+	}
+
+	optionsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "location",
+			Label:       "Location",
+			Description: "Filter volume storages by location, e.g. SAO2",
+			Required:    false,
+		},
+		&cmdflag.String{
+			Name:        "plan",
+			Label:       "Plan",
+			Description: "Filter volume storages by plan",
+			Required:    false,
+		},
+		&cmdflag.String{
+			Name:        "attached",
+			Label:       "Attached",
+			Description: "Only list volume storages with (true) or without (false) attached hosts",
+			Options:     []string{"true", "false"},
+			Required:    false,
+		},
+		&cmdflag.String{
+			Name:        "sort",
+			Label:       "Sort by",
+			Description: fmt.Sprintf("Sort volume storages by one of: %s", strings.Join(volumeSortFields, ", ")),
+			Options:     volumeSortFields,
+			Required:    false,
+		},
+		&cmdflag.Bool{
+			Name:        "desc",
+			Label:       "Descending",
+			Description: "Sort in descending order",
+			Required:    false,
 		},
 	}
 
 	o.QueryParamFlags.Register(queryParamsSchema)
+	o.OptionsFlags.Register(optionsSchema)
+
+	utils.RegisterWatchFlag(cmd)
 }
 
 func (o *VolumeListOperation) preRun(cmd *cobra.Command, args []string) {
 	o.QueryParamFlags.PreRun(cmd, args)
+	o.OptionsFlags.PreRun(cmd, args)
 }
 
 func (o *VolumeListOperation) run(cmd *cobra.Command, args []string) error {
 	// Get optional project filter
 	project, _ := cmd.Flags().GetString("project")
 
+	filters := volumeFilters{}
+	filters.Location, _ = cmd.Flags().GetString("location")
+	filters.Plan, _ = cmd.Flags().GetString("plan")
+	filters.Attached, _ = cmd.Flags().GetString("attached")
+	sortField, _ := cmd.Flags().GetString("sort")
+	descending, _ := cmd.Flags().GetBool("desc")
+
+	interval, err := utils.WatchInterval(cmd, args)
+	if err != nil {
+		return err
+	}
+
 	if lsh.DryRun {
 		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
 		return nil
@@ -87,18 +142,47 @@ func (o *VolumeListOperation) run(cmd *cobra.Command, args []string) error {
 		filterProject = &project
 	}
 
+	// filterAndSort applies the client-side filters and sort to a page of fetched volumes
+	filterAndSort := func(data []*Volume) (*Volumes, error) {
+		data, err := filterVolumes(data, filters)
+		if err != nil {
+			return nil, err
+		}
+		if err := sortVolumes(data, sortField, descending); err != nil {
+			return nil, err
+		}
+
+		return &Volumes{Data: data}, nil
+	}
+
+	if interval > 0 {
+		return renderer.Watch("Volume Storages", interval, func() ([]renderer.ResponseData, error) {
+			data, err := fetchVolumeData(ctx, client, filterProject)
+			if err != nil {
+				return nil, err
+			}
+
+			volumes, err := filterAndSort(data)
+			if err != nil {
+				return nil, err
+			}
+			return volumes.GetData(), nil
+		})
+	}
+
 	// Call the API
-	response, err := client.Storage.GetStorageVolumes(ctx, filterProject)
+	data, err := fetchVolumeData(ctx, client, filterProject)
 	if err != nil {
-		utils.PrintError(err)
 		return nil
 	}
 
+	volumes, err := filterAndSort(data)
+	if err != nil {
+		return err
+	}
+
 	if !lsh.Debug {
-		if response.Object != nil && response.Object.Data != nil {
-			// Display volumes as JSON
-			output.RenderAsJSON(response.Object.Data)
-		}
+		utils.Render(volumes.GetData())
 	}
 
 	return nil
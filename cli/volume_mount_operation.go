@@ -3,10 +3,12 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,6 +21,9 @@ import (
 	"github.com/spf13/viper"
 )
 
+// supportedVolumeFilesystems are the filesystems volume mount can create
+var supportedVolumeFilesystems = []string{"ext4", "xfs"}
+
 const (
 	colorRed    = "\033[0;31m"
 	colorGreen  = "\033[0;32m"
@@ -55,14 +60,27 @@ func (o *VolumeMountOperation) Register() (*cobra.Command, error) {
 
 The mount process:
 - Volume ID: Used to fetch connector_id (subsystem NQN) automatically
-- Client NQN (--nqn or auto-detected): Sent to API to authorize this client
+- Client NQN (--nqn or auto-detected): Sent to API to authorize this client. An existing
+  /etc/nvme/hostnqn is never replaced: use 'lsh volume nqn set --force' to change it
 - Subsystem NQN: Auto-fetched from volume storage's connector_id
 - Gateway: The NVMe-oF gateway IP and port (defaults to 67.213.118.147:4420)
 
+Several gateways can be given with --gateway-ip (comma-separated, IP or IP:port), or
+discovered with --discover, to connect every path to the volume. Native NVMe multipath
+then merges them into one device. --ctrl-loss-tmo and --reconnect-delay tune how long
+and how often lost paths are reconnected, so storage survives a gateway failover.
+
+With --mountpoint, the volume is also formatted (only when it has no filesystem yet,
+using --format) and mounted. --persist reconnects and mounts it again on boot through
+a systemd unit (or /etc/nvme/discovery.conf) and an /etc/fstab entry keyed by the
+filesystem UUID; --persist=false removes those entries.
+
 This command must be run with sudo/root privileges on the target server.
 
 Example:
-  sudo lsh volume mount --id vol_abc123`,
+  sudo lsh volume mount --id vol_abc123
+  sudo lsh volume mount --id vol_abc123 --format xfs --mountpoint /data --persist
+  sudo lsh volume mount --id vol_abc123 --gateway-ip 67.213.118.147,67.213.118.148 --ctrl-loss-tmo -1`,
 		RunE:   o.run,
 		PreRun: o.preRun,
 	}
@@ -92,10 +110,10 @@ func (o *VolumeMountOperation) registerFlags(cmd *cobra.Command) {
 			Description: "NVMe Qualified Name of the server (will auto-detect if not provided)",
 			Required:    false,
 		},
-		&cmdflag.String{
+		&cmdflag.StringSlice{
 			Name:        "gateway-ip",
-			Label:       "Gateway IP",
-			Description: "Override the gateway IP address (optional, default: 67.213.118.147)",
+			Label:       "Gateway IPs",
+			Description: "Override the gateway IP addresses, as IP or IP:port, comma-separated for multipath (optional, default: 67.213.118.147)",
 			Required:    false,
 		},
 		&cmdflag.String{
@@ -104,12 +122,57 @@ func (o *VolumeMountOperation) registerFlags(cmd *cobra.Command) {
 			Description: "Override the gateway port (optional, default: 4420)",
 			Required:    false,
 		},
+		&cmdflag.Bool{
+			Name:        "discover",
+			Label:       "Discover portals",
+			Description: "Discover every gateway portal exposing the volume through the gateways' discovery service",
+			Required:    false,
+		},
+		&cmdflag.Int64{
+			Name:        "ctrl-loss-tmo",
+			Label:       "Controller loss timeout",
+			Description: "Seconds to keep reconnecting a lost path before giving up, -1 to retry forever (optional, nvme-cli default: 600)",
+			Required:    false,
+			Validators:  []cmdflag.Validator{cmdflag.AtLeast(-1)},
+		},
+		&cmdflag.Int64{
+			Name:        "reconnect-delay",
+			Label:       "Reconnect delay",
+			Description: "Seconds between reconnection attempts of a lost path (optional, nvme-cli default: 10)",
+			Required:    false,
+			Validators:  []cmdflag.Validator{cmdflag.AtLeast(1)},
+		},
 		&cmdflag.String{
 			Name:        "subsystem-nqn",
 			Label:       "Subsystem NQN",
 			Description: "Override the subsystem NQN (optional, auto-fetched from volume storage's connector_id)",
 			Required:    false,
 		},
+		&cmdflag.String{
+			Name:        "format",
+			Label:       "Filesystem",
+			Description: "Filesystem to create when the volume is blank (ext4 or xfs)",
+			Options:     supportedVolumeFilesystems,
+			Required:    false,
+		},
+		&cmdflag.Bool{
+			Name:        "force",
+			Label:       "Force format",
+			Description: "With --format, also format a volume holding a partition table or other data signatures, destroying them",
+			Required:    false,
+		},
+		&cmdflag.String{
+			Name:        "mountpoint",
+			Label:       "Mountpoint",
+			Description: "Directory to mount the volume on, e.g. /data",
+			Required:    false,
+		},
+		&cmdflag.Bool{
+			Name:        "persist",
+			Label:       "Persist mount",
+			Description: "Reconnect and mount the volume on boot (--persist=false removes the boot entries)",
+			Required:    false,
+		},
 	}
 
 	o.PathParamFlags.Register(pathParamsSchema)
@@ -133,8 +196,8 @@ func printError(msg string) {
 	fmt.Fprintf(os.Stderr, "%s[ERROR]%s %s\n", colorRed, colorReset, msg)
 }
 
-// checkRoot verifies if the command is running as root
-func checkRoot() error {
+// checkRoot verifies if the volume subcommand is running as root
+func checkRoot(subcommand string) error {
 	if os.Geteuid() != 0 {
 		return fmt.Errorf(`this command must be run as root (use sudo)
 
@@ -145,108 +208,58 @@ This command requires root privileges to:
 - Connect to NVMe-oF targets
 
 Usage:
-  sudo lsh volume mount --id <VOLUME_ID>
+  sudo lsh volume %s --id <VOLUME_ID>
 
 Note: Your API key will be automatically detected from your user config,
       so make sure you've logged in first:
-  lsh login <API_KEY>`)
+  lsh login <API_KEY>`, subcommand)
 	}
 	return nil
 }
 
-// getHostNQN attempts to read the host NQN from /etc/nvme/hostnqn
-// If the file doesn't exist, it generates a new NQN and creates the file
-func getHostNQN() (string, error) {
-	// Try to read existing NQN
-	content, err := os.ReadFile("/etc/nvme/hostnqn")
-	if err == nil {
-		nqn := strings.TrimSpace(string(content))
-		if nqn != "" {
-			return nqn, nil
-		}
+// getHostNQN reads the host NQN from /etc/nvme/hostnqn, generating one only when there is none yet
+func (h *volumeHost) getHostNQN() (string, error) {
+	nqn, err := h.readHostNQN()
+	if err != nil || nqn != "" {
+		return nqn, err
 	}
 
-	// File doesn't exist or is empty - generate a new NQN
-	printWarning("/etc/nvme/hostnqn not found or empty, generating new NQN...")
+	printWarning(fmt.Sprintf("%s not found or empty, generating new NQN...", hostNQNPath))
 
-	// Generate NQN using nvme-cli
-	cmd := exec.Command("nvme", "gen-hostnqn")
-	output, err := cmd.Output()
+	nqn, err = h.generateHostNQN()
 	if err != nil {
-		return "", fmt.Errorf("failed to generate NQN (is nvme-cli installed?): %w", err)
+		return "", err
 	}
-
-	nqn := strings.TrimSpace(string(output))
-	if nqn == "" {
-		return "", fmt.Errorf("generated NQN is empty")
-	}
-
 	printStatus(fmt.Sprintf("Generated new NQN: %s", nqn))
 
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll("/etc/nvme", 0755); err != nil {
-		return "", fmt.Errorf("failed to create /etc/nvme directory: %w", err)
+	if err := h.writeHostNQN(nqn, false); err != nil {
+		return "", err
 	}
 
-	// Write the NQN to file
-	if err := os.WriteFile("/etc/nvme/hostnqn", []byte(nqn+"\n"), 0644); err != nil {
-		return "", fmt.Errorf("failed to write /etc/nvme/hostnqn: %w", err)
-	}
-
-	printStatus("Created /etc/nvme/hostnqn with new NQN")
-
 	return nqn, nil
 }
 
-// ensureHostNQN ensures /etc/nvme/hostnqn exists and contains the correct NQN
-func ensureHostNQN(nqn string) error {
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll("/etc/nvme", 0755); err != nil {
-		return fmt.Errorf("failed to create /etc/nvme directory: %w", err)
-	}
-
-	// Check if file exists and has the correct NQN
-	if content, err := os.ReadFile("/etc/nvme/hostnqn"); err == nil {
-		currentNQN := strings.TrimSpace(string(content))
-		if currentNQN == nqn {
-			printStatus("Host NQN already configured correctly")
-			return nil
-		}
-		printWarning(fmt.Sprintf("Updating host NQN from %s to %s", currentNQN, nqn))
-	}
-
-	// Write the NQN
-	if err := os.WriteFile("/etc/nvme/hostnqn", []byte(nqn+"\n"), 0644); err != nil {
-		return fmt.Errorf("failed to write /etc/nvme/hostnqn: %w", err)
-	}
-
-	printStatus("Host NQN configured successfully")
-	return nil
-}
-
-// runCommand executes a shell command and returns the output
-func runCommand(name string, args ...string) (string, error) {
-	cmd := exec.Command(name, args...)
-	output, err := cmd.CombinedOutput()
-	return strings.TrimSpace(string(output)), err
+// ensureHostNQN ensures /etc/nvme/hostnqn contains nqn, refusing to replace another NQN
+func (h *volumeHost) ensureHostNQN(nqn string) error {
+	return h.writeHostNQN(nqn, false)
 }
 
 // installNvmeCli attempts to auto-install nvme-cli based on the OS
-func installNvmeCli() error {
+func (h *volumeHost) installNvmeCli() error {
 	printWarning("nvme-cli is not installed. Attempting to install...")
 
 	// Try apt (Ubuntu/Debian)
-	if _, err := exec.LookPath("apt"); err == nil {
+	if _, err := h.exec.LookPath("apt"); err == nil {
 		printStatus("Detected apt package manager (Ubuntu/Debian)")
 		printStatus("Running: apt update && apt install -y nvme-cli")
 
 		// Update package list
-		if _, err := runCommand("apt", "update"); err != nil {
+		if _, err := h.exec.Run("apt", "update"); err != nil {
 			return fmt.Errorf("failed to update apt: %w", err)
 		}
 
 		// Install nvme-cli
-		if _, err := runCommand("apt", "install", "-y", "nvme-cli"); err != nil {
+		if _, err := h.exec.Run("apt", "install", "-y", "nvme-cli"); err != nil {
 			return fmt.Errorf("failed to install nvme-cli via apt: %w", err)
 		}
 
@@ -255,11 +268,11 @@ func installNvmeCli() error {
 	}
 
 	// Try yum (CentOS/RHEL)
-	if _, err := exec.LookPath("yum"); err == nil {
+	if _, err := h.exec.LookPath("yum"); err == nil {
 		printStatus("Detected yum package manager (CentOS/RHEL)")
 		printStatus("Running: yum install -y nvme-cli")
 
-		if _, err := runCommand("yum", "install", "-y", "nvme-cli"); err != nil {
+		if _, err := h.exec.Run("yum", "install", "-y", "nvme-cli"); err != nil {
 			return fmt.Errorf("failed to install nvme-cli via yum: %w", err)
 		}
 
@@ -268,11 +281,11 @@ func installNvmeCli() error {
 	}
 
 	// Try dnf (Fedora/newer RHEL)
-	if _, err := exec.LookPath("dnf"); err == nil {
+	if _, err := h.exec.LookPath("dnf"); err == nil {
 		printStatus("Detected dnf package manager (Fedora/newer RHEL)")
 		printStatus("Running: dnf install -y nvme-cli")
 
-		if _, err := runCommand("dnf", "install", "-y", "nvme-cli"); err != nil {
+		if _, err := h.exec.Run("dnf", "install", "-y", "nvme-cli"); err != nil {
 			return fmt.Errorf("failed to install nvme-cli via dnf: %w", err)
 		}
 
@@ -284,12 +297,12 @@ func installNvmeCli() error {
 }
 
 // checkPrerequisites verifies that all required tools are installed
-func checkPrerequisites() error {
+func (h *volumeHost) checkPrerequisites() error {
 	printStatus("Checking prerequisites...")
 
 	// Check if nvme-cli is installed, if not try to install it
-	if _, err := exec.LookPath("nvme"); err != nil {
-		if err := installNvmeCli(); err != nil {
+	if _, err := h.exec.LookPath("nvme"); err != nil {
+		if err := h.installNvmeCli(); err != nil {
 			return fmt.Errorf(`nvme-cli installation failed: %w
 Please install manually:
   Ubuntu/Debian: sudo apt install nvme-cli
@@ -301,143 +314,387 @@ Please install manually:
 
 	// Load NVMe TCP module
 	printStatus("Loading NVMe-oF TCP module...")
-	if _, err := runCommand("modprobe", "nvme_tcp"); err != nil {
+	if _, err := h.exec.Run("modprobe", "nvme_tcp"); err != nil {
 		printWarning("nvme_tcp module may already be loaded")
 	}
 
 	// Check multipath setting (informational)
-	if multipathStatus, err := runCommand("cat", "/sys/module/nvme_core/parameters/multipath"); err == nil {
-		printStatus(fmt.Sprintf("NVMe multipath is: %s", multipathStatus))
+	if multipathStatus, err := h.fs.ReadFile(multipathParamPath); err == nil {
+		printStatus(fmt.Sprintf("NVMe multipath is: %s", strings.TrimSpace(string(multipathStatus))))
 	}
 
 	return nil
 }
 
-// testConnectivity tests network connectivity to the gateway
-func testConnectivity(gatewayIP string) error {
-	printStatus(fmt.Sprintf("Testing connectivity to %s...", gatewayIP))
+// testConnectivity tests network connectivity to each gateway portal and returns the reachable ones
+func (h *volumeHost) testConnectivity(portals []nvmePortal) ([]nvmePortal, error) {
+	var reachable []nvmePortal
+	for _, portal := range portals {
+		printStatus(fmt.Sprintf("Testing connectivity to %s...", portal.Address))
 
-	cmd := exec.Command("ping", "-c", "2", "-W", "2", gatewayIP)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("cannot reach gateway at %s", gatewayIP)
+		if _, err := h.exec.Run("ping", "-c", "2", "-W", "2", portal.Address); err != nil {
+			printWarning(fmt.Sprintf("Cannot reach gateway at %s, skipping this path", portal.Address))
+			continue
+		}
+		reachable = append(reachable, portal)
 	}
 
-	printStatus("Gateway is reachable")
-	return nil
+	if len(reachable) == 0 {
+		return nil, fmt.Errorf("cannot reach any gateway")
+	}
+
+	printStatus(fmt.Sprintf("%d of %d gateways are reachable", len(reachable), len(portals)))
+	return reachable, nil
 }
 
 // disconnectExisting disconnects any existing connection to the subsystem
-func disconnectExisting(subsystemNQN string) {
+func (h *volumeHost) disconnectExisting(subsystemNQN string) {
 	printStatus("Checking for existing connections...")
 
-	output, err := runCommand("nvme", "list-subsys")
-	if err != nil {
-		// nvme list-subsys might fail if no devices, that's ok
-		return
-	}
-
-	if strings.Contains(output, subsystemNQN) {
+	if _, connected := findSubsystem(h.listSubsystems(), subsystemNQN); connected {
 		printWarning("Already connected. Disconnecting...")
-		runCommand("nvme", "disconnect", "-n", subsystemNQN)
-		time.Sleep(2 * time.Second)
+		h.exec.Run("nvme", "disconnect", "-n", subsystemNQN)
+		h.sleep(2 * time.Second)
 	}
 }
 
-// connectNVMeoF connects to the NVMe-oF target
-func connectNVMeoF(gatewayIP, gatewayPort, subsystemNQN string) error {
+// connectNVMeoF connects to the NVMe-oF target through every portal, one path each
+func (h *volumeHost) connectNVMeoF(portals []nvmePortal, subsystemNQN string, options []string) error {
 	printStatus("Connecting to NVMe-oF target...")
-	printStatus(fmt.Sprintf("  Gateway: %s:%s", gatewayIP, gatewayPort))
 	printStatus(fmt.Sprintf("  Subsystem: %s", subsystemNQN))
 
-	_, err := runCommand("nvme", "connect", "-t", "tcp", "-a", gatewayIP, "-s", gatewayPort, "-n", subsystemNQN)
-	if err != nil {
+	connected := 0
+	for _, portal := range portals {
+		printStatus(fmt.Sprintf("  Gateway: %s", portal))
+		if output, err := h.exec.Run("nvme", connectArgs(portal, subsystemNQN, options)...); err != nil {
+			printWarning(fmt.Sprintf("Connection through %s failed: %s", portal, output))
+			continue
+		}
+		connected++
+	}
+
+	if connected == 0 {
 		return fmt.Errorf(`connection failed. Please check:
   1. Gateway is accessible from this server
   2. Client NQN is authorized on the gateway
   3. Volume storage is properly configured`)
 	}
 
-	printStatus("Successfully connected!")
+	printStatus(fmt.Sprintf("Successfully connected through %d of %d paths!", connected, len(portals)))
 	return nil
 }
 
-// verifyConnection verifies the connection and shows available devices
-func verifyConnection(subsystemNQN string) error {
+// verifyConnection verifies the connection, its paths and native multipath, and shows available
+// devices, with formatting hints when the volume is not mounted by lsh
+func (h *volumeHost) verifyConnection(subsystemNQN string, expectedPaths int, showHints bool) error {
 	printStatus("Verifying connection...")
-	time.Sleep(3 * time.Second)
+	h.sleep(3 * time.Second)
 
 	// Check if subsystem is connected
-	output, err := runCommand("nvme", "list-subsys")
-	if err != nil || !strings.Contains(output, subsystemNQN) {
-		return fmt.Errorf("subsystem not found after connection")
+	subsystem, connected := findSubsystem(h.listSubsystems(), subsystemNQN)
+	if h.dryRun {
+		return nil
 	}
-
-	// Find the NVMe device (dynamically detects nvme0, nvme1, nvme2, etc.)
-	lines := strings.Split(output, "\n")
-	var nvmeDevice string
-	for _, line := range lines {
-		// Look for lines with NVMe controller info (e.g., " +- nvme1 tcp traddr=...")
-		if strings.Contains(line, "nvme") {
-			fields := strings.Fields(line)
-			for _, field := range fields {
-				// Find field that starts with "nvme" followed by a number
-				if strings.HasPrefix(field, "nvme") && !strings.Contains(field, "/") && !strings.Contains(field, "-") {
-					nvmeDevice = field
-					break
-				}
-			}
-			if nvmeDevice != "" {
-				break // Found it, stop searching
-			}
-		}
+	if !connected {
+		return fmt.Errorf("subsystem not found after connection")
 	}
 
-	if nvmeDevice == "" {
+	if len(subsystem.Controllers) == 0 {
 		printError("Could not detect NVMe controller from nvme list-subsys")
-		printWarning("Output was:")
-		fmt.Fprintf(os.Stderr, "%s\n", output)
 		return fmt.Errorf("could not find NVMe device - check if connection succeeded")
 	}
 
-	printStatus(fmt.Sprintf("✓ Detected NVMe controller: %s", nvmeDevice))
-
-	// Check for block devices using find (e.g., /dev/nvme0n1, /dev/nvme1n1, etc.)
-	blockDevices, _ := runCommand("find", "/dev", "-name", fmt.Sprintf("%sn*", nvmeDevice))
-	if blockDevices != "" {
-		printStatus("Volume devices available:")
-		devices := strings.Split(blockDevices, "\n")
-		var validDevices []string
-		for _, dev := range devices {
-			dev = strings.TrimSpace(dev)
-			if dev != "" {
-				validDevices = append(validDevices, dev)
-				fmt.Fprintf(os.Stdout, "  %s\n", dev)
-			}
+	live := 0
+	for _, controller := range subsystem.Controllers {
+		printStatus(fmt.Sprintf("✓ Detected NVMe controller: %s (%s %s:%s %s)", controller.Name, controller.Transport, controller.Address, controller.ServiceID, controller.State))
+		if controller.State == "live" {
+			live++
 		}
+	}
 
-		if len(validDevices) > 0 {
-			fmt.Fprintf(os.Stdout, "\n")
-			printStatus("To use the volume, format and mount it. For example:")
-			dev := validDevices[0] // Use first device
-			deviceName := strings.TrimPrefix(dev, "/dev/")
-			mountpoint := fmt.Sprintf("/mnt/%s", deviceName)
-			fmt.Fprintf(os.Stdout, "  sudo mkfs.ext4 %s\n", dev)
-			fmt.Fprintf(os.Stdout, "  sudo mkdir -p %s\n", mountpoint)
-			fmt.Fprintf(os.Stdout, "  sudo mount %s %s\n\n", dev, mountpoint)
+	if live < expectedPaths {
+		printWarning(fmt.Sprintf("Only %d of %d paths are live", live, expectedPaths))
+	}
+	if len(subsystem.Controllers) > 1 {
+		if h.multipathEnabled() {
+			printStatus(fmt.Sprintf("✓ Native NVMe multipath is active (%d paths)", len(subsystem.Controllers)))
+		} else {
+			printWarning("Native NVMe multipath is disabled: every path shows up as a separate device")
+			printWarning("Enable it with nvme_core.multipath=Y on the kernel command line and reboot")
 		}
-	} else {
+	}
+
+	// Find the block devices of the subsystem (e.g., /dev/nvme0n1, /dev/nvme1n1, etc.)
+	devices := h.findSubsystemDevices(subsystemNQN)
+	if len(devices) == 0 {
 		printWarning("No devices found. The volume may not be accessible yet.")
 		printWarning("Wait a few seconds and check: sudo nvme list")
+		return nil
+	}
+
+	printStatus("Volume devices available:")
+	for _, dev := range devices {
+		fmt.Fprintf(os.Stdout, "  %s\n", dev)
+	}
+
+	if showHints {
+		fmt.Fprintf(os.Stdout, "\n")
+		printStatus("To use the volume, format and mount it. For example:")
+		dev := devices[0] // Use first device
+		deviceName := strings.TrimPrefix(dev, "/dev/")
+		mountpoint := fmt.Sprintf("/mnt/%s", deviceName)
+		fmt.Fprintf(os.Stdout, "  sudo lsh volume mount --id <VOLUME_ID> --format ext4 --mountpoint %s --persist\n", mountpoint)
+		fmt.Fprintf(os.Stdout, "or manually:\n")
+		fmt.Fprintf(os.Stdout, "  sudo mkfs.ext4 %s\n", dev)
+		fmt.Fprintf(os.Stdout, "  sudo mkdir -p %s\n", mountpoint)
+		fmt.Fprintf(os.Stdout, "  sudo mount %s %s\n\n", dev, mountpoint)
 	}
 
 	return nil
 }
 
-func (o *VolumeMountOperation) run(cmd *cobra.Command, args []string) error {
-	// Check if running as root
-	if err := checkRoot(); err != nil {
+// volumeRecord is the part of a volume storage the host-side commands use
+type volumeRecord struct {
+	ID          string
+	Name        string
+	ConnectorID string
+}
+
+// fetchVolumeData lists the volume storages of the team, optionally filtered by project, printing
+// the error
+func fetchVolumeData(ctx context.Context, client *latitudeshgosdk.Latitudesh, filterProject *string) ([]*Volume, error) {
+	data, err := listVolumeData(ctx, client, filterProject)
+	if err != nil {
 		printError(err.Error())
-		return err
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// listVolumeData lists the volume storages of the team, optionally filtered by project, leaving the
+// error to the caller
+func listVolumeData(ctx context.Context, client *latitudeshgosdk.Latitudesh, filterProject *string) ([]*Volume, error) {
+	volumesResponse, err := client.Storage.GetStorageVolumes(ctx, filterProject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch volume storage details: %w", err)
+	}
+
+	// Parse response body manually to get volume data
+	if volumesResponse == nil || volumesResponse.HTTPMeta.Response == nil {
+		return nil, fmt.Errorf("failed to get response from API")
+	}
+
+	bodyBytes, err := io.ReadAll(volumesResponse.HTTPMeta.Response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Parse JSON response
+	var responseData struct {
+		Data []*Volume `json:"data"`
+	}
+
+	if err := json.Unmarshal(bodyBytes, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return responseData.Data, nil
+}
+
+// fetchVolumes lists the volume storages of the team
+func fetchVolumes(ctx context.Context, client *latitudeshgosdk.Latitudesh) ([]volumeRecord, error) {
+	data, err := fetchVolumeData(ctx, client, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return volumeRecords(data), nil
+}
+
+// volumeRecords keeps the fields of the volume storages used by the host-side commands
+func volumeRecords(data []*Volume) []volumeRecord {
+	volumes := make([]volumeRecord, 0, len(data))
+	for _, volume := range data {
+		volumes = append(volumes, volumeRecord{ID: volume.ID, Name: volume.Attributes.Name, ConnectorID: volume.Attributes.ConnectorID})
+	}
+
+	return volumes
+}
+
+// fetchVolumeConnectorID returns the connector_id (subsystem NQN) of a volume
+func fetchVolumeConnectorID(ctx context.Context, client *latitudeshgosdk.Latitudesh, volumeID string) (string, error) {
+	if lsh.Debug {
+		fmt.Fprintf(os.Stdout, "[DEBUG] Fetching volume storage details to get connector_id\n")
+	}
+
+	volumes, err := fetchVolumes(ctx, client)
+	if err != nil {
+		return "", err
+	}
+
+	// Find the volume by ID
+	for _, volume := range volumes {
+		if volume.ID != volumeID {
+			continue
+		}
+
+		if volume.ConnectorID == "" {
+			printError("Volume storage does not have a connector_id configured")
+			printError("The volume storage must have a connector_id before mounting")
+			return "", fmt.Errorf("connector_id not found for volume storage %s", volumeID)
+		}
+
+		printStatus(fmt.Sprintf("✓ Retrieved connector_id (subsystem NQN): %s", volume.ConnectorID))
+		return volume.ConnectorID, nil
+	}
+
+	printError(fmt.Sprintf("Volume storage not found: %s", volumeID))
+	return "", fmt.Errorf("volume storage %s not found", volumeID)
+}
+
+func isSupportedVolumeFilesystem(format string) bool {
+	for _, supported := range supportedVolumeFilesystems {
+		if supported == format {
+			return true
+		}
+	}
+	return false
+}
+
+// deviceSignatures returns what blkid finds on device, such as TYPE for a filesystem or PTTYPE for
+// a partition table. It is empty only when the device is blank
+func (h *volumeHost) deviceSignatures(device string) (map[string]string, error) {
+	signatures := map[string]string{}
+
+	output, err := h.exec.Run("blkid", "-p", "-o", "export", device)
+	if err != nil {
+		// blkid exits with 2 when no signature is found
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+			return signatures, nil
+		}
+		return nil, fmt.Errorf("blkid %s failed: %s", device, output)
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok && key != "DEVNAME" {
+			signatures[key] = value
+		}
+	}
+	return signatures, nil
+}
+
+// describeSignatures names what a device without a filesystem holds, for the refusal to format it
+func describeSignatures(signatures map[string]string) string {
+	if ptType := signatures["PTTYPE"]; ptType != "" {
+		return fmt.Sprintf("a %s partition table", ptType)
+	}
+
+	keys := make([]string, 0, len(signatures))
+	for key := range signatures {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return fmt.Sprintf("data signatures (%s)", strings.Join(keys, ", "))
+}
+
+// mkfsForceFlags overwrite the existing signatures, which mkfs otherwise refuses or asks about
+var mkfsForceFlags = map[string]string{"ext4": "-F", "xfs": "-f"}
+
+// mountVolumeFilesystem creates a filesystem on the volume when it is blank and mounts it. A
+// volume holding a partition table or other signatures is only formatted with force
+func (h *volumeHost) mountVolumeFilesystem(subsystemNQN, format, mountpoint string, force bool) (volumePersistence, error) {
+	persistence := volumePersistence{SubsystemNQN: subsystemNQN, Mountpoint: mountpoint}
+
+	devices := h.findSubsystemDevices(subsystemNQN)
+	if len(devices) == 0 && h.dryRun {
+		devices = []string{"<volume device>"}
+	}
+	if len(devices) == 0 {
+		return persistence, fmt.Errorf("no NVMe device found for subsystem %s", subsystemNQN)
+	}
+	device := devices[0]
+
+	signatures, err := h.deviceSignatures(device)
+	if err != nil {
+		return persistence, err
+	}
+	fsType := signatures["TYPE"]
+
+	switch {
+	case fsType == "" && h.dryRun && format == "":
+		printWarning(fmt.Sprintf("%s must already have a filesystem, or --format is needed", device))
+		fsType = "auto"
+	case fsType == "" && format == "":
+		return persistence, fmt.Errorf("%s has no filesystem: pass --format %s to create one", device, strings.Join(supportedVolumeFilesystems, "|"))
+	case fsType == "" && len(signatures) > 0 && !force:
+		return persistence, fmt.Errorf("%s has no filesystem but holds %s: pass --force to format it anyway, destroying them", device, describeSignatures(signatures))
+	case fsType == "":
+		args := []string{device}
+		if len(signatures) > 0 {
+			printWarning(fmt.Sprintf("%s holds %s, formatting it anyway (--force)", device, describeSignatures(signatures)))
+			args = []string{mkfsForceFlags[format], device}
+		} else {
+			printStatus(fmt.Sprintf("%s is blank, creating %s filesystem...", device, format))
+		}
+		if output, err := h.exec.Run("mkfs."+format, args...); err != nil {
+			return persistence, fmt.Errorf("mkfs.%s failed: %s", format, output)
+		}
+		fsType = format
+		printStatus(fmt.Sprintf("✓ Created %s filesystem on %s", format, device))
+	case format != "" && format != fsType:
+		printWarning(fmt.Sprintf("%s already has a %s filesystem, not formatting it as %s", device, fsType, format))
+	default:
+		printStatus(fmt.Sprintf("✓ Found existing %s filesystem on %s", fsType, device))
+	}
+	persistence.FSType = fsType
+
+	uuid, err := h.exec.Run("blkid", "-p", "-o", "value", "-s", "UUID", device)
+	if uuid == "" && h.dryRun {
+		uuid, err = "<filesystem UUID>", nil
+	}
+	if err != nil || uuid == "" {
+		return persistence, fmt.Errorf("could not read the filesystem UUID of %s", device)
+	}
+	persistence.UUID = uuid
+
+	mounts, err := h.readMounts()
+	if err != nil {
+		return persistence, err
+	}
+
+	for _, mount := range mounts {
+		if mount.Mountpoint != mountpoint {
+			continue
+		}
+		if mount.Source == device {
+			printStatus(fmt.Sprintf("✓ %s is already mounted on %s", device, mountpoint))
+			return persistence, nil
+		}
+		return persistence, fmt.Errorf("%s is already in use by %s", mountpoint, mount.Source)
+	}
+
+	if err := h.fs.MkdirAll(mountpoint, 0755); err != nil {
+		return persistence, fmt.Errorf("failed to create %s: %w", mountpoint, err)
+	}
+
+	if output, err := h.exec.Run("mount", "-t", fsType, device, mountpoint); err != nil {
+		return persistence, fmt.Errorf("mount failed: %s", output)
+	}
+	printStatus(fmt.Sprintf("✓ Mounted %s on %s", device, mountpoint))
+
+	return persistence, nil
+}
+
+func (o *VolumeMountOperation) run(cmd *cobra.Command, args []string) error {
+	// Check if running as root, a dry-run only prints the command plan
+	if !lsh.DryRun {
+		if err := checkRoot("mount"); err != nil {
+			printError(err.Error())
+			return err
+		}
 	}
 
 	// Get the volume ID from flags
@@ -446,10 +703,52 @@ func (o *VolumeMountOperation) run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error getting volume ID: %w", err)
 	}
 
+	format, _ := cmd.Flags().GetString("format")
+	mountpoint, _ := cmd.Flags().GetString("mountpoint")
+	persist, _ := cmd.Flags().GetBool("persist")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if format != "" && !isSupportedVolumeFilesystem(format) {
+		return fmt.Errorf("unsupported --format %q: use one of %s", format, strings.Join(supportedVolumeFilesystems, ", "))
+	}
+	if persist && mountpoint == "" {
+		return fmt.Errorf("--persist requires --mountpoint")
+	}
+	if mountpoint != "" && !filepath.IsAbs(mountpoint) {
+		return fmt.Errorf("--mountpoint must be an absolute path")
+	}
+
+	// Get override values or use defaults
+	gatewayIPs, _ := cmd.Flags().GetStringSlice("gateway-ip")
+	gatewayPort, _ := cmd.Flags().GetString("gateway-port")
+	discover, _ := cmd.Flags().GetBool("discover")
+
+	if gatewayPort == "" {
+		gatewayPort = defaultGatewayPort // Default NVMe-oF port
+	}
+
+	// Hardcoded gateway for now
+	if len(gatewayIPs) == 0 {
+		gatewayIPs = []string{defaultGatewayIP}
+		printStatus(fmt.Sprintf("Using default gateway IP: %s", defaultGatewayIP))
+	}
+
+	portals, err := parsePortals(gatewayIPs, gatewayPort)
+	if err != nil {
+		return err
+	}
+
+	connectOptions, err := reconnectOptions(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	host := newVolumeHost(lsh.DryRun)
+
 	fmt.Fprintf(os.Stdout, "\n🔧 Preparing server for volume mount...\n\n")
 
 	// STEP 1: Install prerequisites (nvme-cli) BEFORE getting NQN
-	if err := checkPrerequisites(); err != nil {
+	if err := host.checkPrerequisites(); err != nil {
 		printError(err.Error())
 		return err
 	}
@@ -464,7 +763,7 @@ func (o *VolumeMountOperation) run(cmd *cobra.Command, args []string) error {
 	} else {
 		// Try to auto-detect or generate (nvme-cli is now guaranteed to be installed)
 		printStatus("Getting server NQN...")
-		detectedNQN, err := getHostNQN()
+		detectedNQN, err := host.getHostNQN()
 		if err != nil {
 			printError(fmt.Sprintf("Could not get or generate NQN: %v", err))
 			printError("\nOr provide NQN manually:")
@@ -475,11 +774,102 @@ func (o *VolumeMountOperation) run(cmd *cobra.Command, args []string) error {
 		printStatus(fmt.Sprintf("✓ Using NQN: %s", nqn))
 	}
 
+	// Check the NQN before authorizing it, so another host identity is never replaced silently
+	if err := host.ensureHostNQN(nqn); err != nil {
+		printError(fmt.Sprintf("Failed to ensure host NQN: %v", err))
+		return err
+	}
+
+	// Step 1: Fetch volume storage details to get connector_id (subsystem NQN)
+	subsystemNQN, _ := cmd.Flags().GetString("subsystem-nqn")
+
 	if lsh.DryRun {
 		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		if subsystemNQN == "" {
+			subsystemNQN = fmt.Sprintf("<connector_id of %s>", volumeID)
+		}
+		fmt.Fprintf(os.Stdout, "[DRY-RUN] POST /storage/volumes/%s/mount nqn=%s\n", volumeID, nqn)
+	} else if err := authorizeVolumeMount(cmd, volumeID, nqn, &subsystemNQN); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "\n📡 Connecting to NVMe-oF storage...\n\n")
+	if discover {
+		portals = host.discoverPortals(portals, subsystemNQN)
+	}
+	for _, portal := range portals {
+		printStatus(fmt.Sprintf("Gateway: %s", portal))
+	}
+	printStatus(fmt.Sprintf("Subsystem NQN: %s", subsystemNQN))
+
+	portals, err = host.testConnectivity(portals)
+	if err != nil {
+		printError(fmt.Sprintf("Connectivity test failed: %v", err))
+		return err
+	}
+
+	host.disconnectExisting(subsystemNQN)
+
+	if err := host.connectNVMeoF(portals, subsystemNQN, connectOptions); err != nil {
+		printError(fmt.Sprintf("NVMe-oF connection failed: %v", err))
+		return err
+	}
+
+	if err := host.verifyConnection(subsystemNQN, len(portals), mountpoint == ""); err != nil {
+		printError(fmt.Sprintf("Connection verification failed: %v", err))
+		return err
+	}
+
+	if mountpoint != "" {
+		fmt.Fprintf(os.Stdout, "\n💾 Mounting filesystem...\n\n")
+
+		persistence, err := host.mountVolumeFilesystem(subsystemNQN, format, mountpoint, force)
+		if err != nil {
+			printError(err.Error())
+			return err
+		}
+
+		if persist {
+			persistence.VolumeID = volumeID
+			persistence.Portals = portals
+			persistence.ConnectOptions = connectOptions
+
+			fmt.Fprintf(os.Stdout, "\n🔁 Persisting mount across reboots...\n\n")
+			if err := host.writeVolumePersistence(persistence); err != nil {
+				printError(err.Error())
+				return err
+			}
+		}
+	}
+
+	if cmd.Flags().Changed("persist") && !persist {
+		fmt.Fprintf(os.Stdout, "\n🧹 Removing boot entries...\n\n")
+		if err := host.removeVolumePersistence(volumeID); err != nil {
+			printError(err.Error())
+			return err
+		}
+	}
+
+	if lsh.DryRun {
+		fmt.Fprintf(os.Stdout, "\n[DRY-RUN] Volume mount plan complete. Nothing was changed.\n")
 		return nil
 	}
 
+	fmt.Fprintf(os.Stdout, "\n✅ Volume mount complete!\n")
+	fmt.Fprintf(os.Stdout, "\nConnection Summary:\n")
+	fmt.Fprintf(os.Stdout, "  Client NQN: %s\n", nqn)
+	fmt.Fprintf(os.Stdout, "  Subsystem NQN: %s\n", subsystemNQN)
+	if mountpoint != "" {
+		fmt.Fprintf(os.Stdout, "  Mountpoint: %s\n", mountpoint)
+		fmt.Fprintf(os.Stdout, "  Persistent: %t\n", persist)
+	}
+
+	return nil
+}
+
+// authorizeVolumeMount authorizes the client NQN on the volume, fetching the
+// subsystem NQN from the volume's connector_id when it is not provided
+func authorizeVolumeMount(cmd *cobra.Command, volumeID, nqn string, subsystemNQN *string) error {
 	// Get API key - try both "authorization" and "Authorization" for compatibility
 	apiKey := viper.GetString("authorization")
 	if apiKey == "" {
@@ -495,76 +885,18 @@ func (o *VolumeMountOperation) run(cmd *cobra.Command, args []string) error {
 		latitudeshgosdk.WithSecurity(apiKey),
 	)
 
-	// Step 1: Fetch volume storage details to get connector_id (subsystem NQN)
-	subsystemNQN, _ := cmd.Flags().GetString("subsystem-nqn")
-
-	if subsystemNQN == "" {
+	if *subsystemNQN == "" {
 		// Auto-fetch connector_id from API
 		fmt.Fprintf(os.Stdout, "\n📋 Fetching volume details...\n")
 		printStatus(fmt.Sprintf("Volume ID: %s", volumeID))
 
-		if lsh.Debug {
-			fmt.Fprintf(os.Stdout, "[DEBUG] Fetching volume storage details to get connector_id\n")
-		}
-
-		volumesResponse, err := client.Storage.GetStorageVolumes(ctx, nil)
+		connectorID, err := fetchVolumeConnectorID(ctx, client, volumeID)
 		if err != nil {
-			printError(fmt.Sprintf("Failed to fetch volume storage details: %v", err))
-			utils.PrintError(err)
 			return err
 		}
-
-		// Parse response body manually to get volume data
-		if volumesResponse != nil && volumesResponse.HTTPMeta.Response != nil {
-			bodyBytes, err := io.ReadAll(volumesResponse.HTTPMeta.Response.Body)
-			if err != nil {
-				printError(fmt.Sprintf("Failed to read response body: %v", err))
-				return err
-			}
-
-			// Parse JSON response
-			var responseData struct {
-				Data []struct {
-					ID         string `json:"id"`
-					Type       string `json:"type"`
-					Attributes struct {
-						ConnectorID *string `json:"connector_id"`
-					} `json:"attributes"`
-				} `json:"data"`
-			}
-
-			if err := json.Unmarshal(bodyBytes, &responseData); err != nil {
-				printError(fmt.Sprintf("Failed to parse response: %v", err))
-				return err
-			}
-
-			// Find the volume by ID
-			var found bool
-			for _, volume := range responseData.Data {
-				if volume.ID == volumeID {
-					if volume.Attributes.ConnectorID != nil && *volume.Attributes.ConnectorID != "" {
-						subsystemNQN = *volume.Attributes.ConnectorID
-						printStatus(fmt.Sprintf("✓ Retrieved connector_id (subsystem NQN): %s", subsystemNQN))
-						found = true
-						break
-					} else {
-						printError("Volume storage does not have a connector_id configured")
-						printError("The volume storage must have a connector_id before mounting")
-						return fmt.Errorf("connector_id not found for volume storage %s", volumeID)
-					}
-				}
-			}
-
-			if !found {
-				printError(fmt.Sprintf("Volume storage not found: %s", volumeID))
-				return fmt.Errorf("volume storage %s not found", volumeID)
-			}
-		} else {
-			printError("No response from API")
-			return fmt.Errorf("failed to get response from API")
-		}
+		*subsystemNQN = connectorID
 	} else {
-		printStatus(fmt.Sprintf("Using provided subsystem NQN: %s", subsystemNQN))
+		printStatus(fmt.Sprintf("Using provided subsystem NQN: %s", *subsystemNQN))
 	}
 
 	fmt.Fprintf(os.Stdout, "\n📦 Authorizing client and mounting volume...\n")
@@ -607,51 +939,5 @@ func (o *VolumeMountOperation) run(cmd *cobra.Command, args []string) error {
 		printWarning("No response from API")
 	}
 
-	// Get override values or use defaults
-	gatewayIP, _ := cmd.Flags().GetString("gateway-ip")
-	gatewayPort, _ := cmd.Flags().GetString("gateway-port")
-
-	// Hardcoded gateway for now
-	if gatewayIP == "" {
-		gatewayIP = "67.213.118.147" // Hardcoded gateway IP
-		printStatus(fmt.Sprintf("Using default gateway IP: %s", gatewayIP))
-	}
-
-	if gatewayPort == "" {
-		gatewayPort = "4420" // Default NVMe-oF port
-	}
-
-	fmt.Fprintf(os.Stdout, "\n📡 Connecting to NVMe-oF storage...\n\n")
-	printStatus(fmt.Sprintf("Gateway: %s:%s", gatewayIP, gatewayPort))
-	printStatus(fmt.Sprintf("Subsystem NQN: %s", subsystemNQN))
-
-	// Execute mount steps (prerequisites already checked)
-	if err := ensureHostNQN(nqn); err != nil {
-		printError(fmt.Sprintf("Failed to ensure host NQN: %v", err))
-		return err
-	}
-
-	if err := testConnectivity(gatewayIP); err != nil {
-		printError(fmt.Sprintf("Connectivity test failed: %v", err))
-		return err
-	}
-
-	disconnectExisting(subsystemNQN)
-
-	if err := connectNVMeoF(gatewayIP, gatewayPort, subsystemNQN); err != nil {
-		printError(fmt.Sprintf("NVMe-oF connection failed: %v", err))
-		return err
-	}
-
-	if err := verifyConnection(subsystemNQN); err != nil {
-		printError(fmt.Sprintf("Connection verification failed: %v", err))
-		return err
-	}
-
-	fmt.Fprintf(os.Stdout, "\n✅ Volume mount complete!\n")
-	fmt.Fprintf(os.Stdout, "\nConnection Summary:\n")
-	fmt.Fprintf(os.Stdout, "  Client NQN: %s\n", nqn)
-	fmt.Fprintf(os.Stdout, "  Subsystem NQN: %s\n", subsystemNQN)
-
 	return nil
 }
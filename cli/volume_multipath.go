@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	defaultGatewayIP   = "67.213.118.147"
+	defaultGatewayPort = "4420"
+)
+
+// nvmePortal is a gateway address a subsystem is reachable through
+type nvmePortal struct {
+	Address   string
+	ServiceID string
+}
+
+func (p nvmePortal) String() string {
+	return net.JoinHostPort(p.Address, p.ServiceID)
+}
+
+// parsePortals parses gateways given as IP or IP:port, without duplicates
+func parsePortals(values []string, defaultPort string) ([]nvmePortal, error) {
+	var portals []nvmePortal
+	seen := make(map[nvmePortal]bool)
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		portal := nvmePortal{Address: strings.Trim(value, "[]"), ServiceID: defaultPort}
+		if host, port, err := net.SplitHostPort(value); err == nil {
+			portal = nvmePortal{Address: host, ServiceID: port}
+		}
+
+		if net.ParseIP(portal.Address) == nil {
+			return nil, fmt.Errorf("invalid gateway %q: expected an IP address or IP:port", value)
+		}
+		if seen[portal] {
+			continue
+		}
+		seen[portal] = true
+		portals = append(portals, portal)
+	}
+
+	return portals, nil
+}
+
+// discoveryLogEntry is a record of the discovery log returned by nvme discover
+type discoveryLogEntry struct {
+	Transport    string
+	Address      string
+	ServiceID    string
+	SubsystemNQN string
+}
+
+// parseDiscoveryLog parses the output of nvme discover
+func parseDiscoveryLog(output string) []discoveryLogEntry {
+	var entries []discoveryLogEntry
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "=====Discovery Log Entry") {
+			entries = append(entries, discoveryLogEntry{})
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found || len(entries) == 0 {
+			continue
+		}
+
+		entry := &entries[len(entries)-1]
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "trtype":
+			entry.Transport = value
+		case "traddr":
+			entry.Address = value
+		case "trsvcid":
+			entry.ServiceID = value
+		case "subnqn":
+			entry.SubsystemNQN = value
+		}
+	}
+
+	return entries
+}
+
+// subsystemPortals returns the TCP portals of the discovery log exposing the subsystem
+func subsystemPortals(entries []discoveryLogEntry, subsystemNQN string) []nvmePortal {
+	var portals []nvmePortal
+	for _, entry := range entries {
+		if entry.Transport == "tcp" && entry.SubsystemNQN == subsystemNQN {
+			portals = append(portals, nvmePortal{Address: entry.Address, ServiceID: entry.ServiceID})
+		}
+	}
+	return portals
+}
+
+// reconnectOptions builds the nvme connect options tuning how paths survive a gateway failover
+func reconnectOptions(flags *pflag.FlagSet) ([]string, error) {
+	var options []string
+
+	if flags.Changed("ctrl-loss-tmo") {
+		ctrlLossTmo, _ := flags.GetInt64("ctrl-loss-tmo")
+		if ctrlLossTmo < -1 {
+			return nil, fmt.Errorf("--ctrl-loss-tmo must be -1 (retry forever) or a number of seconds")
+		}
+		options = append(options, fmt.Sprintf("--ctrl-loss-tmo=%d", ctrlLossTmo))
+	}
+
+	if flags.Changed("reconnect-delay") {
+		reconnectDelay, _ := flags.GetInt64("reconnect-delay")
+		if reconnectDelay < 1 {
+			return nil, fmt.Errorf("--reconnect-delay must be at least 1 second")
+		}
+		options = append(options, fmt.Sprintf("--reconnect-delay=%d", reconnectDelay))
+	}
+
+	return options, nil
+}
+
+// connectArgs are the nvme connect arguments for a path to the subsystem
+func connectArgs(portal nvmePortal, subsystemNQN string, options []string) []string {
+	args := []string{"connect", "-t", "tcp", "-a", portal.Address, "-s", portal.ServiceID, "-n", subsystemNQN}
+	return append(args, options...)
+}
+
+// discoverPortals asks the discovery service of each gateway for the portals exposing the subsystem,
+// falling back to the gateways themselves when nothing is discovered
+func (h *volumeHost) discoverPortals(gateways []nvmePortal, subsystemNQN string) []nvmePortal {
+	printStatus("Discovering gateway portals...")
+
+	var portals []nvmePortal
+	seen := make(map[nvmePortal]bool)
+	for _, gateway := range gateways {
+		output, err := h.exec.Run("nvme", "discover", "-t", "tcp", "-a", gateway.Address, "-s", gateway.ServiceID)
+		if err != nil {
+			printWarning(fmt.Sprintf("Discovery failed on %s: %s", gateway, output))
+			continue
+		}
+
+		for _, portal := range subsystemPortals(parseDiscoveryLog(output), subsystemNQN) {
+			if !seen[portal] {
+				seen[portal] = true
+				portals = append(portals, portal)
+			}
+		}
+	}
+
+	if len(portals) == 0 {
+		if !h.dryRun {
+			printWarning("No portal discovered for this volume, using the given gateways")
+		}
+		return gateways
+	}
+
+	for _, portal := range portals {
+		printStatus(fmt.Sprintf("✓ Discovered portal %s", portal))
+	}
+	return portals
+}
+
+// multipathEnabled reports whether native NVMe multipath is active in the kernel
+func (h *volumeHost) multipathEnabled() bool {
+	content, err := h.fs.ReadFile(multipathParamPath)
+	return err == nil && strings.TrimSpace(string(content)) == "Y"
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestParsePortals(t *testing.T) {
+	tests := []struct {
+		values  []string
+		want    []nvmePortal
+		wantErr bool
+	}{
+		{
+			values: []string{"67.213.118.147", "67.213.118.148:4421", "67.213.118.147"},
+			want: []nvmePortal{
+				{Address: "67.213.118.147", ServiceID: "4420"},
+				{Address: "67.213.118.148", ServiceID: "4421"},
+			},
+		},
+		{
+			values: []string{"[2001:db8::1]:4420", "2001:db8::2"},
+			want: []nvmePortal{
+				{Address: "2001:db8::1", ServiceID: "4420"},
+				{Address: "2001:db8::2", ServiceID: "4420"},
+			},
+		},
+		{values: []string{"gateway.example.com"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePortals(tt.values, "4420")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePortals(%q) error = %v, wantErr %v", tt.values, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePortals(%q) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestDiscoverPortals(t *testing.T) {
+	gateway := nvmePortal{Address: "67.213.118.147", ServiceID: "4420"}
+
+	tests := []struct {
+		name     string
+		discover fakeResult
+		want     []nvmePortal
+	}{
+		{
+			name:     "portals of the subsystem",
+			discover: fakeResult{output: readFixture(t, "testdata/nvme-discover.txt")},
+			want: []nvmePortal{
+				{Address: "67.213.118.147", ServiceID: "4420"},
+				{Address: "67.213.118.148", ServiceID: "4420"},
+			},
+		},
+		{
+			name:     "falls back to the gateway",
+			discover: fakeResult{output: "Failed to write to /dev/nvme-fabrics", err: fakeExitError(1)},
+			want:     []nvmePortal{gateway},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, _, _ := newTestVolumeHost(t, map[string]fakeResult{
+				"nvme discover -t tcp -a 67.213.118.147 -s 4420": tt.discover,
+			})
+
+			got := host.discoverPortals([]nvmePortal{gateway}, testSubsystemNQN)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("discoverPortals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectNVMeoF_EveryPath(t *testing.T) {
+	portals := []nvmePortal{
+		{Address: "67.213.118.147", ServiceID: "4420"},
+		{Address: "67.213.118.148", ServiceID: "4420"},
+	}
+	options := []string{"--ctrl-loss-tmo=-1", "--reconnect-delay=5"}
+	second := "nvme connect -t tcp -a 67.213.118.148 -s 4420 -n " + testSubsystemNQN + " --ctrl-loss-tmo=-1 --reconnect-delay=5"
+
+	host, executor, _ := newTestVolumeHost(t, map[string]fakeResult{second: {err: fakeExitError(1)}})
+	if err := host.connectNVMeoF(portals, testSubsystemNQN, options); err != nil {
+		t.Fatalf("connectNVMeoF() error = %v, want the failed path to be skipped", err)
+	}
+
+	want := []string{
+		"nvme connect -t tcp -a 67.213.118.147 -s 4420 -n " + testSubsystemNQN + " --ctrl-loss-tmo=-1 --reconnect-delay=5",
+		second,
+	}
+	if !reflect.DeepEqual(executor.calls, want) {
+		t.Errorf("commands = %q, want %q", executor.calls, want)
+	}
+
+	host, _, _ = newTestVolumeHost(t, map[string]fakeResult{second: {err: fakeExitError(1)}})
+	if err := host.connectNVMeoF(portals[1:], testSubsystemNQN, options); err == nil {
+		t.Error("connectNVMeoF() succeeded without any path")
+	}
+}
+
+func TestReconnectOptions(t *testing.T) {
+	newFlags := func(args ...string) *pflag.FlagSet {
+		flags := pflag.NewFlagSet("mount", pflag.ContinueOnError)
+		flags.Int64("ctrl-loss-tmo", 0, "")
+		flags.Int64("reconnect-delay", 0, "")
+		if err := flags.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return flags
+	}
+
+	tests := []struct {
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{args: nil},
+		{args: []string{"--ctrl-loss-tmo=-1", "--reconnect-delay=5"}, want: []string{"--ctrl-loss-tmo=-1", "--reconnect-delay=5"}},
+		{args: []string{"--ctrl-loss-tmo=0"}, want: []string{"--ctrl-loss-tmo=0"}},
+		{args: []string{"--ctrl-loss-tmo=-2"}, wantErr: true},
+		{args: []string{"--reconnect-delay=0"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := reconnectOptions(newFlags(tt.args...))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("reconnectOptions(%q) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("reconnectOptions(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,435 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/latitudesh/latitudesh-go-sdk/models/operations"
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/latitudesh/lsh/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// nqnPattern is the NVMe Qualified Name format: nqn.yyyy-mm.<reverse domain>[:<identifier>]
+var nqnPattern = regexp.MustCompile(`^nqn\.\d{4}-\d{2}\.[A-Za-z0-9][A-Za-z0-9.-]*(:\S+)?$`)
+
+// maxNQNLength is the size limit of an NQN set by the NVMe specification
+const maxNQNLength = 223
+
+func validateNQN(nqn string) error {
+	if len(nqn) > maxNQNLength {
+		return fmt.Errorf("invalid NQN %q: longer than %d bytes", nqn, maxNQNLength)
+	}
+	if !nqnPattern.MatchString(nqn) {
+		return fmt.Errorf("invalid NQN %q: expected nqn.yyyy-mm.<reverse domain>:<identifier>", nqn)
+	}
+	return nil
+}
+
+// readHostNQN returns the NQN of /etc/nvme/hostnqn, or an empty string when there is none
+func (h *volumeHost) readHostNQN() (string, error) {
+	content, err := h.fs.ReadFile(hostNQNPath)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", hostNQNPath, err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// generateHostNQN asks nvme-cli for a new NQN derived from the host UUID
+func (h *volumeHost) generateHostNQN() (string, error) {
+	output, err := h.exec.Run("nvme", "gen-hostnqn")
+	if err != nil {
+		return "", fmt.Errorf("failed to generate NQN (is nvme-cli installed?): %w", err)
+	}
+
+	nqn := strings.TrimSpace(output)
+	if nqn == "" && h.dryRun {
+		nqn = "<nqn generated by nvme gen-hostnqn>"
+	}
+	if nqn == "" {
+		return "", fmt.Errorf("generated NQN is empty")
+	}
+	return nqn, nil
+}
+
+// writeHostNQN writes nqn to /etc/nvme/hostnqn. Another NQN already there is only replaced with
+// force, since the targets that authorized it would no longer accept this host
+func (h *volumeHost) writeHostNQN(nqn string, force bool) error {
+	current, err := h.readHostNQN()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case current == nqn:
+		printStatus("Host NQN already configured correctly")
+		return nil
+	case current != "" && !force:
+		return fmt.Errorf(`%s already contains %s
+Volumes and other NVMe-oF targets authorized for it would no longer accept this server.
+Use the existing NQN, or replace it with 'sudo lsh volume nqn set --nqn %s --force'`, hostNQNPath, current, nqn)
+	case current != "":
+		printWarning(fmt.Sprintf("Replacing host NQN %s with %s", current, nqn))
+	}
+
+	if err := h.fs.MkdirAll(filepath.Dir(hostNQNPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", filepath.Dir(hostNQNPath), err)
+	}
+
+	if err := h.fs.WriteFile(hostNQNPath, []byte(nqn+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", hostNQNPath, err)
+	}
+
+	printStatus(fmt.Sprintf("✓ Wrote %s to %s", nqn, hostNQNPath))
+	return nil
+}
+
+// authorizedVolumes keeps the volumes whose initiators include nqn
+func authorizedVolumes(volumes []*Volume, nqn string) []*Volume {
+	var authorized []*Volume
+	for _, volume := range volumes {
+		for _, initiator := range volume.Attributes.Initiators {
+			if initiator.Nqn == nqn {
+				authorized = append(authorized, volume)
+				break
+			}
+		}
+	}
+	return authorized
+}
+
+// checkNQNRoot makes sure the command can write /etc/nvme/hostnqn
+func checkNQNRoot(subcommand string) error {
+	if lsh.DryRun || os.Geteuid() == 0 {
+		return nil
+	}
+
+	err := fmt.Errorf("this command must be run as root to write %s (use sudo lsh volume nqn %s)", hostNQNPath, subcommand)
+	printError(err.Error())
+	return err
+}
+
+// nqnOrHostNQN returns the --nqn flag, defaulting to the NQN of this server
+func nqnOrHostNQN(cmd *cobra.Command) (string, error) {
+	nqn, _ := cmd.Flags().GetString("nqn")
+	if nqn == "" {
+		var err error
+		if nqn, err = newVolumeHost(false).readHostNQN(); err != nil {
+			return "", err
+		}
+	}
+	if nqn == "" {
+		return "", fmt.Errorf("no host NQN configured: pass --nqn or run 'sudo lsh volume nqn generate'")
+	}
+
+	return nqn, validateNQN(nqn)
+}
+
+func volumeIDFlag() *cmdflag.String {
+	return &cmdflag.String{
+		Name:        "id",
+		Label:       "Volume Storage ID",
+		Description: "The ID of the volume storage",
+		Required:    true,
+	}
+}
+
+func forceFlag(description string) *cmdflag.Bool {
+	return &cmdflag.Bool{
+		Name:        "force",
+		Label:       "Force",
+		Description: description,
+		Required:    false,
+	}
+}
+
+func hostNQNFlag(description string, required bool) *cmdflag.String {
+	return &cmdflag.String{
+		Name:        "nqn",
+		Label:       "NVMe Qualified Name (NQN)",
+		Description: description,
+		Required:    required,
+	}
+}
+
+func makeOperationVolumeNQNShowCmd() (*cobra.Command, error) {
+	operation := VolumeNQNShowOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type VolumeNQNShowOperation struct{}
+
+func (o *VolumeNQNShowOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the NQN of this server",
+		Long:  "Show the host NQN of this server from /etc/nvme/hostnqn and the volumes that have authorized it",
+		RunE:  o.run,
+	}
+
+	return cmd, nil
+}
+
+func (o *VolumeNQNShowOperation) run(cmd *cobra.Command, args []string) error {
+	nqn, err := newVolumeHost(false).readHostNQN()
+	if err != nil {
+		return err
+	}
+	if nqn == "" {
+		printWarning(fmt.Sprintf("%s not found or empty: run 'sudo lsh volume nqn generate' to create a host NQN", hostNQNPath))
+		return nil
+	}
+
+	fmt.Fprintf(os.Stdout, "Host NQN: %s\n", nqn)
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		return nil
+	}
+
+	client, err := newStorageClient()
+	if err != nil {
+		printWarning(fmt.Sprintf("Skipping authorized volumes: %v", err))
+		return nil
+	}
+
+	volumes, err := fetchVolumeData(context.Background(), client, nil)
+	if err != nil {
+		return err
+	}
+
+	authorized := authorizedVolumes(volumes, nqn)
+	if len(authorized) == 0 {
+		fmt.Fprintf(os.Stdout, "No volume has authorized this NQN.\n")
+		return nil
+	}
+
+	fmt.Fprintf(os.Stdout, "\nAuthorized volumes:\n")
+	if !lsh.Debug {
+		data := &Volumes{Data: authorized}
+		utils.Render(data.GetData())
+	}
+
+	return nil
+}
+
+func makeOperationVolumeNQNGenerateCmd() (*cobra.Command, error) {
+	operation := VolumeNQNGenerateOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type VolumeNQNGenerateOperation struct {
+	OptionsFlags cmdflag.Flags
+}
+
+func (o *VolumeNQNGenerateOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a new NQN for this server",
+		Long: `Generate a new host NQN with 'nvme gen-hostnqn' and write it to /etc/nvme/hostnqn.
+An existing NQN is only replaced with --force. This command must be run with sudo/root privileges.`,
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.OptionsFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.OptionsFlags.Register(&cmdflag.FlagsSchema{forceFlag("Replace the existing host NQN")})
+
+	return cmd, nil
+}
+
+func (o *VolumeNQNGenerateOperation) preRun(cmd *cobra.Command, args []string) {
+	o.OptionsFlags.PreRun(cmd, args)
+}
+
+func (o *VolumeNQNGenerateOperation) run(cmd *cobra.Command, args []string) error {
+	force, _ := cmd.Flags().GetBool("force")
+
+	if err := checkNQNRoot("generate"); err != nil {
+		return err
+	}
+
+	host := newVolumeHost(lsh.DryRun)
+	if err := host.checkPrerequisites(); err != nil {
+		printError(err.Error())
+		return err
+	}
+
+	// Check before generating, so a refused overwrite does not print a new NQN
+	if current, err := host.readHostNQN(); err != nil {
+		return err
+	} else if current != "" && !force {
+		err := fmt.Errorf("%s already contains %s: pass --force to replace it", hostNQNPath, current)
+		printError(err.Error())
+		return err
+	}
+
+	nqn, err := host.generateHostNQN()
+	if err != nil {
+		printError(err.Error())
+		return err
+	}
+
+	if err := host.writeHostNQN(nqn, force); err != nil {
+		printError(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func makeOperationVolumeNQNSetCmd() (*cobra.Command, error) {
+	operation := VolumeNQNSetOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type VolumeNQNSetOperation struct {
+	BodyAttributesFlags cmdflag.Flags
+	OptionsFlags        cmdflag.Flags
+}
+
+func (o *VolumeNQNSetOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set the NQN of this server",
+		Long: `Write the given host NQN to /etc/nvme/hostnqn. An existing NQN is only replaced with
+--force. This command must be run with sudo/root privileges.`,
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.BodyAttributesFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.OptionsFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	o.BodyAttributesFlags.Register(&cmdflag.FlagsSchema{hostNQNFlag("The host NQN to use", true)})
+	o.OptionsFlags.Register(&cmdflag.FlagsSchema{forceFlag("Replace the existing host NQN")})
+
+	return cmd, nil
+}
+
+func (o *VolumeNQNSetOperation) preRun(cmd *cobra.Command, args []string) {
+	o.BodyAttributesFlags.PreRun(cmd, args)
+	o.OptionsFlags.PreRun(cmd, args)
+}
+
+func (o *VolumeNQNSetOperation) run(cmd *cobra.Command, args []string) error {
+	nqn, _ := cmd.Flags().GetString("nqn")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if err := validateNQN(nqn); err != nil {
+		return err
+	}
+
+	if err := checkNQNRoot("set"); err != nil {
+		return err
+	}
+
+	if err := newVolumeHost(lsh.DryRun).writeHostNQN(nqn, force); err != nil {
+		printError(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func makeOperationVolumeNQNAuthorizeCmd() (*cobra.Command, error) {
+	operation := VolumeNQNAuthorizeOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type VolumeNQNAuthorizeOperation struct {
+	PathParamFlags      cmdflag.Flags
+	BodyAttributesFlags cmdflag.Flags
+}
+
+func (o *VolumeNQNAuthorizeOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "authorize",
+		Short: "Authorize an NQN on a volume storage",
+		Long: `Authorize a host NQN to connect to a volume storage, without connecting this server.
+Defaults to the NQN of this server.`,
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.PathParamFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.BodyAttributesFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	o.PathParamFlags.Register(&cmdflag.FlagsSchema{volumeIDFlag()})
+	o.BodyAttributesFlags.Register(&cmdflag.FlagsSchema{hostNQNFlag("The NQN to authorize (defaults to the NQN of this server)", false)})
+
+	return cmd, nil
+}
+
+func (o *VolumeNQNAuthorizeOperation) preRun(cmd *cobra.Command, args []string) {
+	o.PathParamFlags.PreRun(cmd, args)
+	o.BodyAttributesFlags.PreRun(cmd, args)
+}
+
+func (o *VolumeNQNAuthorizeOperation) run(cmd *cobra.Command, args []string) error {
+	volumeID, _ := cmd.Flags().GetString("id")
+
+	nqn, err := nqnOrHostNQN(cmd)
+	if err != nil {
+		return err
+	}
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		return nil
+	}
+
+	client, err := newStorageClient()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "\n🔑 Authorizing %s on volume storage %s...\n", nqn, volumeID)
+	_, err = client.Storage.PostStorageVolumesMount(context.Background(), volumeID, operations.PostStorageVolumesMountRequestBody{
+		Data: operations.PostStorageVolumesMountData{
+			Type: operations.PostStorageVolumesMountTypeVolumes,
+			Attributes: operations.PostStorageVolumesMountAttributes{
+				Nqn: nqn,
+			},
+		},
+	})
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	printStatus("✓ NQN authorized")
+	return nil
+}
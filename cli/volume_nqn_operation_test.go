@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+const testHostNQN = "nqn.2014-08.org.nvmexpress:uuid:4c4c4544-0044-4810-8052-b3c04f4d5132"
+
+func TestValidateNQN(t *testing.T) {
+	for nqn, valid := range map[string]bool{
+		testHostNQN:                                           true,
+		testSubsystemNQN:                                      true,
+		"nqn.2014-08.org.nvmexpress.discovery":                true,
+		"uuid:4c4c4544-0044-4810-8052":                        false,
+		"nqn.14-08.org.nvmexpress:uuid:1234":                  false,
+		"nqn.2014-08.org.nvmexpress:uuid 1234":                false,
+		"nqn.2014-08.org.example:" + strings.Repeat("a", 220): false,
+	} {
+		if err := validateNQN(nqn); (err == nil) != valid {
+			t.Errorf("validateNQN(%q) = %v, want valid %t", nqn, err, valid)
+		}
+	}
+}
+
+func TestWriteHostNQN(t *testing.T) {
+	const otherNQN = "nqn.2014-08.org.nvmexpress:uuid:00000000-0000-0000-0000-000000000001"
+
+	tests := []struct {
+		name    string
+		current string
+		force   bool
+		want    string
+		wantErr bool
+	}{
+		{name: "no NQN yet", want: testHostNQN + "\n"},
+		{name: "same NQN", current: testHostNQN + "\n", want: testHostNQN + "\n"},
+		{name: "refuses to replace another NQN", current: otherNQN + "\n", want: otherNQN + "\n", wantErr: true},
+		{name: "replaces with force", current: otherNQN + "\n", force: true, want: testHostNQN + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, _, fs := newTestVolumeHost(t, nil)
+			if tt.current != "" {
+				writeTestFile(t, fs, hostNQNPath, tt.current)
+			}
+
+			err := host.writeHostNQN(testHostNQN, tt.force)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("writeHostNQN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			content, _ := fs.ReadFile(hostNQNPath)
+			if string(content) != tt.want {
+				t.Errorf("%s = %q, want %q", hostNQNPath, content, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetHostNQN_GeneratesOnlyWhenMissing(t *testing.T) {
+	host, executor, fs := newTestVolumeHost(t, map[string]fakeResult{
+		"nvme gen-hostnqn": {output: testHostNQN},
+	})
+
+	nqn, err := host.getHostNQN()
+	if err != nil || nqn != testHostNQN {
+		t.Fatalf("getHostNQN() = %q, %v", nqn, err)
+	}
+
+	if nqn, err := host.getHostNQN(); err != nil || nqn != testHostNQN {
+		t.Fatalf("getHostNQN() = %q, %v", nqn, err)
+	}
+	if len(executor.calls) != 1 {
+		t.Errorf("commands = %q, want a single nvme gen-hostnqn", executor.calls)
+	}
+	if content, _ := fs.ReadFile(hostNQNPath); string(content) != testHostNQN+"\n" {
+		t.Errorf("%s = %q", hostNQNPath, content)
+	}
+}
+
+func TestAuthorizedVolumes(t *testing.T) {
+	volumes := loadTestVolumes(t)
+
+	authorized := authorizedVolumes(volumes, testHostNQN)
+	if len(authorized) != 1 || authorized[0].ID != "vol_ZdKRPHylXqOa4" {
+		t.Errorf("authorizedVolumes() = %v, want [vol_ZdKRPHylXqOa4]", authorized)
+	}
+
+	if authorized := authorizedVolumes(volumes, testSubsystemNQN); len(authorized) != 0 {
+		t.Errorf("authorizedVolumes() = %v, want none", authorized)
+	}
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	fstabPath         = "/etc/fstab"
+	discoveryConfPath = "/etc/nvme/discovery.conf"
+	systemdUnitDir    = "/etc/systemd/system"
+)
+
+// volumePersistenceMarker is the comment written above every entry lsh manages for a volume
+func volumePersistenceMarker(volumeID string) string {
+	return "# lsh volume " + volumeID
+}
+
+// volumeUnitName is the systemd unit that reconnects a volume on boot
+func volumeUnitName(volumeID string) string {
+	return fmt.Sprintf("lsh-volume-%s.service", volumeID)
+}
+
+// removeManagedLines drops the marker lines and the entry that follows each of them
+func removeManagedLines(content, marker string) (string, bool) {
+	lines := strings.SplitAfter(content, "\n")
+
+	var kept []string
+	removed := false
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == marker {
+			removed = true
+			i++ // skip the managed entry
+			continue
+		}
+		kept = append(kept, lines[i])
+	}
+
+	return strings.Join(kept, ""), removed
+}
+
+// removeManagedEntries rewrites path without the entries managed under marker
+func (h *volumeHost) removeManagedEntries(path, marker string) (bool, error) {
+	removed := false
+	err := updateManagedFile(h.fs, path, 0755, 0644, func(content string) (string, error) {
+		var updated string
+		updated, removed = removeManagedLines(content, marker)
+		return updated, nil
+	})
+	return removed, err
+}
+
+// removeVolumePersistence removes the fstab, discovery.conf and systemd entries lsh created for the volume
+func (h *volumeHost) removeVolumePersistence(volumeID string) error {
+	for _, path := range []string{fstabPath, discoveryConfPath} {
+		removed, err := h.removeManagedEntries(path, volumePersistenceMarker(volumeID))
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", path, err)
+		}
+		if removed {
+			printStatus(fmt.Sprintf("✓ Removed %s entry for %s", path, volumeID))
+		}
+	}
+
+	unitPath := filepath.Join(systemdUnitDir, volumeUnitName(volumeID))
+	if _, err := h.fs.Stat(unitPath); err == nil {
+		h.exec.Run("systemctl", "disable", volumeUnitName(volumeID))
+		if err := h.fs.Remove(unitPath); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", unitPath, err)
+		}
+		h.exec.Run("systemctl", "daemon-reload")
+		printStatus(fmt.Sprintf("✓ Removed systemd unit %s", volumeUnitName(volumeID)))
+	}
+
+	return nil
+}
+
+// volumePersistence describes how a mounted volume is restored on boot
+type volumePersistence struct {
+	VolumeID       string
+	SubsystemNQN   string
+	Portals        []nvmePortal
+	ConnectOptions []string
+	UUID           string
+	Mountpoint     string
+	FSType         string
+}
+
+// fstabEntry mounts the filesystem by UUID once the network is up, without blocking boot when it is missing
+func (p volumePersistence) fstabEntry() string {
+	return fmt.Sprintf("UUID=%s %s %s defaults,_netdev,nofail 0 0", p.UUID, p.Mountpoint, p.FSType)
+}
+
+// discoveryEntries let nvmf-autoconnect reconnect to every gateway on boot
+func (p volumePersistence) discoveryEntries() []string {
+	var entries []string
+	for _, portal := range p.Portals {
+		entry := fmt.Sprintf("--transport=tcp --traddr=%s --trsvcid=%s", portal.Address, portal.ServiceID)
+		entries = append(entries, strings.Join(append([]string{entry}, p.ConnectOptions...), " "))
+	}
+	return entries
+}
+
+// unitContent is a oneshot unit connecting every path of the subsystem before remote filesystems
+// are mounted; a path that is already connected or down does not fail the unit
+func (p volumePersistence) unitContent(nvmePath string) string {
+	var connects strings.Builder
+	for _, portal := range p.Portals {
+		fmt.Fprintf(&connects, "ExecStart=-%s\n", shellCommand(nvmePath, connectArgs(portal, p.SubsystemNQN, p.ConnectOptions)...))
+	}
+
+	return fmt.Sprintf(`# Managed by lsh: remove with 'lsh volume mount --id %[1]s --persist=false'
+[Unit]
+Description=Connect Latitude.sh volume %[1]s over NVMe-oF
+Wants=network-online.target
+After=network-online.target
+Before=remote-fs-pre.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStartPre=-/sbin/modprobe nvme_tcp
+%[4]sExecStop=%[2]s disconnect -n %[3]s
+
+[Install]
+WantedBy=remote-fs.target
+`, p.VolumeID, nvmePath, p.SubsystemNQN, connects.String())
+}
+
+// upsertManagedLines replaces the entries managed under marker, or appends them, each under its own marker
+func upsertManagedLines(content, marker string, entries ...string) string {
+	content, _ = removeManagedLines(content, marker)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	for _, entry := range entries {
+		content += marker + "\n" + entry + "\n"
+	}
+	return content
+}
+
+// upsertManagedEntry writes the entries managed under marker into path, keeping everything else
+func (h *volumeHost) upsertManagedEntry(path, marker string, entries ...string) error {
+	return updateManagedFile(h.fs, path, 0755, 0644, func(content string) (string, error) {
+		return upsertManagedLines(content, marker, entries...), nil
+	})
+}
+
+// writeVolumePersistence reconnects the volume on boot with a systemd unit, or with
+// /etc/nvme/discovery.conf when systemd is not available, and mounts it through /etc/fstab
+func (h *volumeHost) writeVolumePersistence(p volumePersistence) error {
+	if _, err := h.exec.LookPath("systemctl"); err == nil {
+		nvmePath, err := h.exec.LookPath("nvme")
+		if err != nil && h.dryRun {
+			// nvme-cli would have been installed before this step
+			nvmePath, err = "/usr/sbin/nvme", nil
+		}
+		if err != nil {
+			return fmt.Errorf("nvme-cli not found: %w", err)
+		}
+
+		unitPath := filepath.Join(systemdUnitDir, volumeUnitName(p.VolumeID))
+		if err := h.fs.WriteFile(unitPath, []byte(p.unitContent(nvmePath)), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", unitPath, err)
+		}
+
+		if _, err := h.exec.Run("systemctl", "daemon-reload"); err != nil {
+			return fmt.Errorf("systemctl daemon-reload failed: %w", err)
+		}
+		if output, err := h.exec.Run("systemctl", "enable", volumeUnitName(p.VolumeID)); err != nil {
+			return fmt.Errorf("failed to enable %s: %s", volumeUnitName(p.VolumeID), output)
+		}
+		printStatus(fmt.Sprintf("✓ Enabled systemd unit %s", volumeUnitName(p.VolumeID)))
+	} else {
+		if err := h.upsertManagedEntry(discoveryConfPath, volumePersistenceMarker(p.VolumeID), p.discoveryEntries()...); err != nil {
+			return fmt.Errorf("failed to update %s: %w", discoveryConfPath, err)
+		}
+		printStatus(fmt.Sprintf("✓ Added %s entry (requires nvmf-autoconnect to be enabled)", discoveryConfPath))
+	}
+
+	if err := h.upsertManagedEntry(fstabPath, volumePersistenceMarker(p.VolumeID), p.fstabEntry()); err != nil {
+		return fmt.Errorf("failed to update %s: %w", fstabPath, err)
+	}
+	printStatus(fmt.Sprintf("✓ Added %s entry: %s", fstabPath, p.fstabEntry()))
+
+	return nil
+}
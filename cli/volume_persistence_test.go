@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRemoveManagedLines(t *testing.T) {
+	fstab := "UUID=1111 / ext4 defaults 0 1\n" +
+		"# lsh volume vol_abc123\n" +
+		"UUID=2222 /data ext4 defaults,_netdev,nofail 0 2\n" +
+		"# lsh volume vol_other\n" +
+		"UUID=3333 /other xfs defaults,_netdev,nofail 0 2\n"
+
+	got, removed := removeManagedLines(fstab, volumePersistenceMarker("vol_abc123"))
+	if !removed {
+		t.Fatal("removeManagedLines() did not remove the managed entry")
+	}
+
+	want := "UUID=1111 / ext4 defaults 0 1\n" +
+		"# lsh volume vol_other\n" +
+		"UUID=3333 /other xfs defaults,_netdev,nofail 0 2\n"
+	if got != want {
+		t.Errorf("removeManagedLines() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, removed := removeManagedLines(want, volumePersistenceMarker("vol_abc123")); removed {
+		t.Error("removeManagedLines() removed an entry twice")
+	}
+}
+
+func TestUpsertManagedLines_Idempotent(t *testing.T) {
+	p := volumePersistence{VolumeID: "vol_abc123", UUID: "2222", Mountpoint: "/data", FSType: "xfs"}
+	marker := volumePersistenceMarker(p.VolumeID)
+
+	fstab := "UUID=1111 / ext4 defaults 0 1"
+	once := upsertManagedLines(fstab, marker, p.fstabEntry())
+	twice := upsertManagedLines(once, marker, p.fstabEntry())
+
+	want := "UUID=1111 / ext4 defaults 0 1\n" +
+		"# lsh volume vol_abc123\n" +
+		"UUID=2222 /data xfs defaults,_netdev,nofail 0 0\n"
+	if once != want {
+		t.Errorf("upsertManagedLines() =\n%s\nwant\n%s", once, want)
+	}
+	if twice != once {
+		t.Errorf("upsertManagedLines() is not idempotent:\n%s", twice)
+	}
+
+	p.Mountpoint = "/srv"
+	moved := upsertManagedLines(twice, marker, p.fstabEntry())
+	if strings.Contains(moved, " /data ") || strings.Count(moved, marker) != 1 {
+		t.Errorf("upsertManagedLines() did not replace the previous entry:\n%s", moved)
+	}
+}
+
+func TestVolumeUnitContent(t *testing.T) {
+	p := volumePersistence{
+		VolumeID:     "vol_abc123",
+		SubsystemNQN: testSubsystemNQN,
+		Portals: []nvmePortal{
+			{Address: "67.213.118.147", ServiceID: "4420"},
+			{Address: "67.213.118.148", ServiceID: "4420"},
+		},
+		ConnectOptions: []string{"--ctrl-loss-tmo=-1", "--reconnect-delay=5"},
+	}
+
+	unit := p.unitContent("/usr/sbin/nvme")
+	for _, want := range []string{
+		"Before=remote-fs-pre.target",
+		"ExecStart=-/usr/sbin/nvme connect -t tcp -a 67.213.118.147 -s 4420 -n " + testSubsystemNQN + " --ctrl-loss-tmo=-1 --reconnect-delay=5\n",
+		"ExecStart=-/usr/sbin/nvme connect -t tcp -a 67.213.118.148 -s 4420 -n " + testSubsystemNQN + " --ctrl-loss-tmo=-1 --reconnect-delay=5\n",
+		"ExecStop=/usr/sbin/nvme disconnect -n " + testSubsystemNQN,
+		"WantedBy=remote-fs.target",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit is missing %q:\n%s", want, unit)
+		}
+	}
+}
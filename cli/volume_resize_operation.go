@@ -0,0 +1,303 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	latitudeshgosdk "github.com/latitudesh/latitudesh-go-sdk"
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/spf13/cobra"
+)
+
+func makeOperationVolumeResizeCmd() (*cobra.Command, error) {
+	operation := VolumeResizeOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type VolumeResizeOperation struct {
+	PathParamFlags      cmdflag.Flags
+	BodyAttributesFlags cmdflag.Flags
+	OptionsFlags        cmdflag.Flags
+}
+
+func (o *VolumeResizeOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "resize",
+		Short: "Check the new size of a volume storage and grow its filesystem",
+		Long: `Check that a volume storage was resized to a new size in GB. The API does not resize
+volumes yet: resize the volume from the web dashboard first. This command fails while the
+volume storage is smaller than --size.
+
+With --grow-fs, run on the server the volume is mounted on, this command waits until the API
+reports the new size, rescans the NVMe namespace, waits until it reports the new size too and
+grows the mounted ext4 or xfs filesystem online. --grow-fs must be run with sudo/root privileges.
+
+Example:
+  sudo lsh volume resize --id vol_abc123 --size 200 --grow-fs`,
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.registerFlags(cmd)
+
+	return cmd, nil
+}
+
+func (o *VolumeResizeOperation) registerFlags(cmd *cobra.Command) {
+	o.PathParamFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.BodyAttributesFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.OptionsFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	pathParamsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "id",
+			Label:       "Volume Storage ID",
+			Description: "The ID of the volume storage to resize",
+			Required:    true,
+		},
+	}
+
+	bodyAttributesSchema := &cmdflag.FlagsSchema{
+		&cmdflag.Int64{
+			Name:        "size",
+			Label:       "Size (GB)",
+			Description: "The new size of the volume storage in GB",
+			Required:    true,
+			Validators:  []cmdflag.Validator{cmdflag.AtLeast(1)},
+		},
+	}
+
+	optionsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.Bool{
+			Name:        "grow-fs",
+			Label:       "Grow filesystem",
+			Description: "Rescan the namespace on this server and grow the mounted ext4/xfs filesystem",
+			Required:    false,
+		},
+		&cmdflag.String{
+			Name:        "subsystem-nqn",
+			Label:       "Subsystem NQN",
+			Description: "Override the subsystem NQN (optional, auto-fetched from volume storage's connector_id)",
+			Required:    false,
+		},
+	}
+
+	o.PathParamFlags.Register(pathParamsSchema)
+	o.BodyAttributesFlags.Register(bodyAttributesSchema)
+	o.OptionsFlags.Register(optionsSchema)
+}
+
+func (o *VolumeResizeOperation) preRun(cmd *cobra.Command, args []string) {
+	o.PathParamFlags.PreRun(cmd, args)
+	o.BodyAttributesFlags.PreRun(cmd, args)
+	o.OptionsFlags.PreRun(cmd, args)
+}
+
+// namespaceSize reads the size of a namespace block device from sysfs, in bytes
+func (h *volumeHost) namespaceSize(device string) (uint64, error) {
+	content, err := h.fs.ReadFile(filepath.Join(sysfsRoot, "class", "block", filepath.Base(device), "size"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the size of %s: %w", device, err)
+	}
+
+	// sysfs reports the size in 512-byte sectors whatever the logical block size
+	sectors, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the size of %s: %w", device, err)
+	}
+
+	return sectors * 512, nil
+}
+
+// growVolumeFilesystem rescans the namespace of the subsystem until it reports at least sizeGB
+// and grows the filesystem mounted from it
+func (h *volumeHost) growVolumeFilesystem(subsystemNQN string, sizeGB int64) error {
+	devices := h.findSubsystemDevices(subsystemNQN)
+	if len(devices) == 0 && h.dryRun {
+		devices = []string{"/dev/<volume device>"}
+	}
+	if len(devices) == 0 {
+		return fmt.Errorf("no NVMe device found for subsystem %s: is the volume mounted on this server?", subsystemNQN)
+	}
+	device := devices[0]
+
+	subsystem, _ := findSubsystem(h.listSubsystems(), subsystemNQN)
+	for _, controller := range subsystem.Controllers {
+		if output, err := h.exec.Run("nvme", "ns-rescan", "/dev/"+controller.Name); err != nil {
+			printWarning(fmt.Sprintf("nvme ns-rescan /dev/%s failed: %s", controller.Name, output))
+		}
+	}
+
+	target := uint64(sizeGB) * 1000 * 1000 * 1000
+	err := h.waitFor(fmt.Sprintf("%s to report %d GB", device, sizeGB), volumeWaitTimeout, func() (bool, string, error) {
+		size, err := h.namespaceSize(device)
+		if err != nil {
+			return false, "", err
+		}
+		return size >= target, fmt.Sprintf("%s is %s", device, formatBytes(size)), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	mounts, err := h.readMounts()
+	if err != nil {
+		return err
+	}
+
+	// Only a filesystem on the whole device grows with the namespace, not one on a partition
+	var mounted []mountEntry
+	for _, mount := range mounts {
+		if mount.Source == device {
+			mounted = append(mounted, mount)
+		}
+	}
+	if len(mounted) == 0 && h.dryRun {
+		mounted = []mountEntry{{Source: device, Mountpoint: "<mountpoint>", FSType: "<filesystem>"}}
+	}
+	if len(mounted) == 0 {
+		return fmt.Errorf("%s is not mounted: mount it with 'lsh volume mount --mountpoint' to grow its filesystem online", device)
+	}
+
+	mount := mounted[0]
+	var output string
+	switch mount.FSType {
+	case "ext4":
+		output, err = h.exec.Run("resize2fs", mount.Source)
+	case "xfs":
+		output, err = h.exec.Run("xfs_growfs", mount.Mountpoint)
+	default:
+		if !h.dryRun {
+			return fmt.Errorf("growing %s filesystems is not supported: use one of %s", mount.FSType, strings.Join(supportedVolumeFilesystems, ", "))
+		}
+		output, err = h.exec.Run("resize2fs", mount.Source)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to grow the filesystem on %s: %s", mount.Mountpoint, output)
+	}
+
+	printStatus(fmt.Sprintf("✓ Grew the %s filesystem on %s", mount.FSType, mount.Mountpoint))
+	return nil
+}
+
+// waitForVolumeSize polls the volume storage with fetch until the API reports at least sizeGB
+func (h *volumeHost) waitForVolumeSize(volumeID string, sizeGB int64, fetch func() (*Volume, error)) (*Volume, error) {
+	var volume *Volume
+	err := h.waitFor(fmt.Sprintf("%s to report %d GB", volumeID, sizeGB), volumeWaitTimeout, func() (bool, string, error) {
+		var err error
+		if volume, err = fetch(); err != nil {
+			return false, "", err
+		}
+		return volume.Attributes.SizeInGb >= sizeGB, fmt.Sprintf("%s is %d GB", volumeID, volume.Attributes.SizeInGb), nil
+	})
+	return volume, err
+}
+
+// fetchVolume returns the volume storage with the given ID
+func fetchVolume(ctx context.Context, client *latitudeshgosdk.Latitudesh, volumeID string) (*Volume, error) {
+	volumes, err := fetchVolumeData(ctx, client, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, volume := range volumes {
+		if volume.ID == volumeID {
+			return volume, nil
+		}
+	}
+	return nil, fmt.Errorf("volume storage %s not found", volumeID)
+}
+
+func (o *VolumeResizeOperation) run(cmd *cobra.Command, args []string) error {
+	volumeID, err := cmd.Flags().GetString("id")
+	if err != nil {
+		return fmt.Errorf("error getting volume ID: %w", err)
+	}
+	size, _ := cmd.Flags().GetInt64("size")
+	growFS, _ := cmd.Flags().GetBool("grow-fs")
+	subsystemNQN, _ := cmd.Flags().GetString("subsystem-nqn")
+
+	if size <= 0 {
+		return fmt.Errorf("--size must be a positive number of GB")
+	}
+
+	if growFS && !lsh.DryRun {
+		if err := checkRoot("resize"); err != nil {
+			printError(err.Error())
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stdout, "\n📏 Checking volume storage size...\n")
+	printStatus(fmt.Sprintf("Volume ID: %s", volumeID))
+	printStatus(fmt.Sprintf("New size: %d GB", size))
+
+	host := newVolumeHost(lsh.DryRun)
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		if subsystemNQN == "" {
+			subsystemNQN = fmt.Sprintf("<connector_id of %s>", volumeID)
+		}
+		if _, err := host.waitForVolumeSize(volumeID, size, nil); err != nil {
+			return err
+		}
+	} else {
+		client, err := newStorageClient()
+		if err != nil {
+			return err
+		}
+		fetch := func() (*Volume, error) { return fetchVolume(context.Background(), client, volumeID) }
+
+		volume, err := fetch()
+		if err != nil {
+			printError(err.Error())
+			return err
+		}
+
+		// Without --grow-fs there is nothing to wait for on this server
+		if !growFS && volume.Attributes.SizeInGb < size {
+			err := fmt.Errorf("%s is %d GB: the API does not resize volumes yet, resize it to %d GB from the web dashboard at https://www.latitude.sh", volumeID, volume.Attributes.SizeInGb, size)
+			printError(err.Error())
+			return err
+		}
+
+		// A resize started from the dashboard may still be in progress
+		if volume.Attributes.SizeInGb < size {
+			if volume, err = host.waitForVolumeSize(volumeID, size, fetch); err != nil {
+				printError(err.Error())
+				return err
+			}
+		}
+		printStatus(fmt.Sprintf("✓ %s is %d GB", volumeID, volume.Attributes.SizeInGb))
+
+		if subsystemNQN == "" {
+			subsystemNQN = volume.Attributes.ConnectorID
+		}
+		if growFS && subsystemNQN == "" {
+			return fmt.Errorf("connector_id not found for volume storage %s", volumeID)
+		}
+	}
+
+	if !growFS {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stdout, "\n💾 Growing filesystem...\n\n")
+	if err := host.growVolumeFilesystem(subsystemNQN, size); err != nil {
+		printError(err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWaitFor(t *testing.T) {
+	host, _, _ := newTestVolumeHost(t, nil)
+
+	attempts := 0
+	err := host.waitFor("the namespace", volumeWaitTimeout, func() (bool, string, error) {
+		attempts++
+		return attempts == 3, "checking", nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("waitFor() = %v after %d attempts, want success after 3", err, attempts)
+	}
+
+	err = host.waitFor("the namespace", 2*volumeWaitInterval, func() (bool, string, error) {
+		return false, "still 100 GB", nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "still 100 GB") {
+		t.Errorf("waitFor() error = %v, want a timeout reporting the progress", err)
+	}
+}
+
+func TestWaitForVolumeSize(t *testing.T) {
+	host, _, _ := newTestVolumeHost(t, nil)
+
+	sizes := []int64{100, 100, 200}
+	volume, err := host.waitForVolumeSize("vol_1", 200, func() (*Volume, error) {
+		volume := &Volume{ID: "vol_1", Attributes: VolumeAttributes{SizeInGb: sizes[0]}}
+		if len(sizes) > 1 {
+			sizes = sizes[1:]
+		}
+		return volume, nil
+	})
+	if err != nil || volume.Attributes.SizeInGb != 200 {
+		t.Errorf("waitForVolumeSize() = %v, %v, want the volume at 200 GB", volume, err)
+	}
+
+	_, err = host.waitForVolumeSize("vol_1", 200, func() (*Volume, error) {
+		return nil, errors.New("volume storage vol_1 not found")
+	})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("waitForVolumeSize() error = %v, want the fetch error", err)
+	}
+}
+
+func TestGrowVolumeFilesystem(t *testing.T) {
+	const device = "/dev/nvme1n1"
+
+	tests := []struct {
+		name     string
+		mounts   string
+		sectors  string
+		wantCmds []string
+		wantErr  bool
+	}{
+		{
+			name:    "ext4",
+			mounts:  device + " /data ext4 rw,relatime 0 0\n",
+			sectors: "419430400\n",
+			wantCmds: []string{
+				"nvme list-subsys",
+				"nvme ns-rescan /dev/nvme1",
+				"nvme ns-rescan /dev/nvme2",
+				"resize2fs " + device,
+			},
+		},
+		{
+			name:    "xfs",
+			mounts:  device + " /data xfs rw,relatime 0 0\n",
+			sectors: "419430400\n",
+			wantCmds: []string{
+				"nvme list-subsys",
+				"nvme ns-rescan /dev/nvme1",
+				"nvme ns-rescan /dev/nvme2",
+				"xfs_growfs /data",
+			},
+		},
+		{
+			name:    "not mounted",
+			sectors: "419430400\n",
+			wantErr: true,
+		},
+		{
+			name:    "namespace keeps its old size",
+			mounts:  device + " /data ext4 rw,relatime 0 0\n",
+			sectors: "209715200\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, executor, fs := newTestVolumeHost(t, map[string]fakeResult{
+				"nvme list-subsys": {output: readFixture(t, "testdata/nvme-list-subsys-v2.txt")},
+			})
+			writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys1/subsysnqn", testSubsystemNQN+"\n")
+			writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys1/nvme1n1/size", tt.sectors)
+			writeTestFile(t, fs, "/sys/class/block/nvme1n1/size", tt.sectors)
+			writeTestFile(t, fs, procMountsPath, tt.mounts)
+
+			err := host.growVolumeFilesystem(testSubsystemNQN, 200)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("growVolumeFilesystem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(executor.calls, tt.wantCmds) {
+				t.Errorf("commands = %q, want %q", executor.calls, tt.wantCmds)
+			}
+		})
+	}
+}
@@ -0,0 +1,459 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	latitudeshgosdk "github.com/latitudesh/latitudesh-go-sdk"
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// volumeHealth is the state reported by 'lsh volume status'
+type volumeHealth string
+
+const (
+	volumeHealthy      volumeHealth = "healthy"
+	volumeDegraded     volumeHealth = "degraded"
+	volumeDisconnected volumeHealth = "disconnected"
+	// volumeUnknown is only reported with -o json, when the status could not be determined
+	volumeUnknown volumeHealth = "unknown"
+)
+
+// Exit codes of 'lsh volume status', following the monitoring plugin convention
+const (
+	volumeStatusExitHealthy      = 0
+	volumeStatusExitDegraded     = 1
+	volumeStatusExitDisconnected = 2
+	volumeStatusExitUnknown      = 3
+)
+
+func makeOperationVolumeStatusCmd() (*cobra.Command, error) {
+	operation := VolumeStatusOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type VolumeStatusOperation struct {
+	OptionsFlags cmdflag.Flags
+}
+
+func (o *VolumeStatusOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the health of the volumes attached to this server",
+		Long: `Show the health of the volumes attached to this server. This command correlates the
+volume storages of the API with the local NVMe-oF subsystems, their paths, devices and mounted
+filesystems. Without --id, every volume connected to this server or mounted through
+'lsh volume mount --persist' is checked.
+
+A volume is:
+  healthy       when every path is live, its device is present and its persistent mount is mounted
+  degraded      when some paths are down, the device is missing or the filesystem is not mounted
+  disconnected  when the subsystem is not connected or no path is live
+
+The exit code is 0 when healthy, 1 when degraded, 2 when disconnected and 3 when the
+status could not be determined, so it can be used by monitoring agents. With -o json, the
+statuses are printed as a JSON array, holding a single status of unknown with the error when
+they could not be determined.
+
+Example:
+  lsh volume status --id vol_abc123
+  lsh volume status -o json`,
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.registerFlags(cmd)
+
+	return cmd, nil
+}
+
+func (o *VolumeStatusOperation) registerFlags(cmd *cobra.Command) {
+	o.OptionsFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	optionsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "id",
+			Label:       "Volume Storage ID",
+			Description: "The ID of the volume storage to check (optional, defaults to every volume attached to this server)",
+			Required:    false,
+		},
+	}
+
+	o.OptionsFlags.Register(optionsSchema)
+}
+
+func (o *VolumeStatusOperation) preRun(cmd *cobra.Command, args []string) {
+	o.OptionsFlags.PreRun(cmd, args)
+}
+
+// volumeStatus is the health of a volume on this server
+type volumeStatus struct {
+	VolumeID     string            `json:"volume_id"`
+	Name         string            `json:"name"`
+	SubsystemNQN string            `json:"subsystem_nqn"`
+	Health       volumeHealth      `json:"status"`
+	Multipath    bool              `json:"multipath"`
+	Paths        []nvmeController  `json:"paths"`
+	Devices      []volumeDevice    `json:"devices"`
+	Mounts       []volumeMountInfo `json:"mounts"`
+	Problems     []string          `json:"problems"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// volumeDevice is a namespace block device of a volume
+type volumeDevice struct {
+	Path      string `json:"path"`
+	SizeBytes uint64 `json:"size_bytes,omitempty"`
+}
+
+// volumeMountInfo is a mounted filesystem of a volume and its usage
+type volumeMountInfo struct {
+	Source     string `json:"source"`
+	Mountpoint string `json:"mountpoint"`
+	FSType     string `json:"fs_type"`
+	SizeBytes  uint64 `json:"size_bytes,omitempty"`
+	UsedBytes  uint64 `json:"used_bytes,omitempty"`
+}
+
+// livePaths counts the paths in the live state
+func (s volumeStatus) livePaths() int {
+	live := 0
+	for _, path := range s.Paths {
+		if path.State == "live" {
+			live++
+		}
+	}
+	return live
+}
+
+// evaluate sets the health of the volume from the local state, expectedMountpoint
+// being the mountpoint lsh persisted for the volume, if any
+func (s *volumeStatus) evaluate(connected bool, expectedMountpoint string) {
+	s.Problems = nil
+
+	live := s.livePaths()
+	switch {
+	case !connected:
+		s.Problems = append(s.Problems, "subsystem is not connected")
+	case live == 0:
+		s.Problems = append(s.Problems, "no path is live")
+	}
+	if len(s.Problems) > 0 {
+		s.Health = volumeDisconnected
+		return
+	}
+
+	if live < len(s.Paths) {
+		s.Problems = append(s.Problems, fmt.Sprintf("%d of %d paths are not live", len(s.Paths)-live, len(s.Paths)))
+	}
+	if len(s.Paths) > 1 && !s.Multipath {
+		s.Problems = append(s.Problems, "native NVMe multipath is disabled")
+	}
+	if len(s.Devices) == 0 {
+		s.Problems = append(s.Problems, "no namespace device found")
+	}
+	if expectedMountpoint != "" {
+		mounted := false
+		for _, mount := range s.Mounts {
+			mounted = mounted || mount.Mountpoint == expectedMountpoint
+		}
+		if !mounted {
+			s.Problems = append(s.Problems, fmt.Sprintf("%s is not mounted", expectedMountpoint))
+		}
+	}
+
+	s.Health = volumeHealthy
+	if len(s.Problems) > 0 {
+		s.Health = volumeDegraded
+	}
+}
+
+// managedMountpoint returns the mountpoint of the fstab entry lsh manages for the volume
+func managedMountpoint(fstab, volumeID string) string {
+	lines := strings.Split(fstab, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != volumePersistenceMarker(volumeID) || i+1 >= len(lines) {
+			continue
+		}
+		if fields := strings.Fields(lines[i+1]); len(fields) >= 2 {
+			return unescapeMountField(fields[1])
+		}
+	}
+	return ""
+}
+
+// parseNvmeListSizes returns the size of each device listed by 'nvme list -o json',
+// walking the document since nvme-cli 1.x and 2.x nest devices differently
+func parseNvmeListSizes(output string) map[string]uint64 {
+	sizes := make(map[string]uint64)
+
+	var document interface{}
+	if err := json.Unmarshal([]byte(output), &document); err != nil {
+		return sizes
+	}
+
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case map[string]interface{}:
+			size, hasSize := v["PhysicalSize"].(float64)
+			if path, ok := v["DevicePath"].(string); ok && hasSize {
+				sizes[path] = uint64(size)
+			}
+			if name, ok := v["NameSpace"].(string); ok && hasSize {
+				sizes["/dev/"+name] = uint64(size)
+			}
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(document)
+
+	return sizes
+}
+
+// parseDiskUsage parses the output of 'df -P -k <mountpoint>' into bytes
+func parseDiskUsage(output string) (size, used uint64, err error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return 0, 0, fmt.Errorf("unexpected df output")
+	}
+
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 3 {
+		return 0, 0, fmt.Errorf("unexpected df output")
+	}
+
+	total, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	usedKB, err := strconv.ParseUint(fields[2], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return total * 1024, usedKB * 1024, nil
+}
+
+// volumeStatus collects the local state of the volume and evaluates its health
+func (h *volumeHost) volumeStatus(volume volumeRecord, subsystems []nvmeSubsystem, sizes map[string]uint64, mounts []mountEntry) volumeStatus {
+	status := volumeStatus{VolumeID: volume.ID, Name: volume.Name, SubsystemNQN: volume.ConnectorID}
+
+	status.Multipath = h.multipathEnabled()
+
+	subsystem, connected := findSubsystem(subsystems, volume.ConnectorID)
+	status.Paths = subsystem.Controllers
+
+	devices := h.findSubsystemDevices(volume.ConnectorID)
+	for _, device := range devices {
+		status.Devices = append(status.Devices, volumeDevice{Path: device, SizeBytes: sizes[device]})
+	}
+
+	for _, mount := range volumeMounts(mounts, devices) {
+		info := volumeMountInfo{Source: mount.Source, Mountpoint: mount.Mountpoint, FSType: mount.FSType}
+		if output, err := h.exec.Run("df", "-P", "-k", mount.Mountpoint); err == nil {
+			info.SizeBytes, info.UsedBytes, _ = parseDiskUsage(output)
+		}
+		status.Mounts = append(status.Mounts, info)
+	}
+
+	expectedMountpoint := ""
+	if fstab, err := h.fs.ReadFile(fstabPath); err == nil {
+		expectedMountpoint = managedMountpoint(string(fstab), volume.ID)
+	}
+
+	status.evaluate(connected, expectedMountpoint)
+	return status
+}
+
+// attachedVolumes returns the volumes connected to this server or persisted on it by lsh
+func attachedVolumes(volumes []volumeRecord, subsystems []nvmeSubsystem, fstab string) []volumeRecord {
+	var attached []volumeRecord
+	for _, volume := range volumes {
+		_, connected := findSubsystem(subsystems, volume.ConnectorID)
+		if (volume.ConnectorID != "" && connected) || managedMountpoint(fstab, volume.ID) != "" {
+			attached = append(attached, volume)
+		}
+	}
+	return attached
+}
+
+// volumeStatusExitCode is the exit code of the worst health
+func volumeStatusExitCode(statuses []volumeStatus) int {
+	code := volumeStatusExitHealthy
+	for _, status := range statuses {
+		switch {
+		case status.Health == volumeDisconnected:
+			code = volumeStatusExitDisconnected
+		case status.Health == volumeDegraded && code == volumeStatusExitHealthy:
+			code = volumeStatusExitDegraded
+		}
+	}
+	return code
+}
+
+// formatBytes prints a size in binary units
+func formatBytes(size uint64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// volumeStatusJSON reports whether the statuses are printed as JSON
+func volumeStatusJSON() bool {
+	return viper.GetBool("json") || viper.GetString("output") == "json"
+}
+
+func renderVolumeStatuses(statuses []volumeStatus) {
+	if volumeStatusJSON() {
+		jsonData, err := json.MarshalIndent(statuses, "", "    ")
+		if err != nil {
+			fmt.Println("Could not encode volume status as JSON.")
+			return
+		}
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("No volumes are attached to this server.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tPATHS\tDEVICE\tMOUNTPOINT\tUSAGE")
+
+	for _, status := range statuses {
+		device, mountpoint, usage := "-", "-", "-"
+		if len(status.Devices) > 0 {
+			device = status.Devices[0].Path
+			if status.Devices[0].SizeBytes > 0 {
+				device = fmt.Sprintf("%s (%s)", device, formatBytes(status.Devices[0].SizeBytes))
+			}
+		}
+		if len(status.Mounts) > 0 {
+			mount := status.Mounts[0]
+			mountpoint = fmt.Sprintf("%s (%s)", mount.Mountpoint, mount.FSType)
+			if mount.SizeBytes > 0 {
+				usage = fmt.Sprintf("%s / %s (%d%%)", formatBytes(mount.UsedBytes), formatBytes(mount.SizeBytes), mount.UsedBytes*100/mount.SizeBytes)
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d live\t%s\t%s\t%s\n",
+			status.VolumeID, status.Name, status.Health, status.livePaths(), len(status.Paths), device, mountpoint, usage)
+	}
+	w.Flush()
+
+	for _, status := range statuses {
+		for _, path := range status.Paths {
+			fmt.Fprintf(os.Stdout, "  %s path %s %s %s:%s %s\n", status.VolumeID, path.Name, path.Transport, path.Address, path.ServiceID, path.State)
+		}
+		for _, problem := range status.Problems {
+			printWarning(fmt.Sprintf("%s: %s", status.VolumeID, problem))
+		}
+	}
+}
+
+func (o *VolumeStatusOperation) run(cmd *cobra.Command, args []string) error {
+	volumeID, _ := cmd.Flags().GetString("id")
+
+	if lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		return nil
+	}
+
+	apiKey := viper.GetString("authorization")
+	if apiKey == "" {
+		apiKey = viper.GetString("Authorization")
+	}
+	if apiKey == "" {
+		return volumeStatusUnknown(cmd, volumeID, "API key not found. Please run 'lsh login <API_KEY>' first")
+	}
+
+	client := latitudeshgosdk.New(latitudeshgosdk.WithSecurity(apiKey))
+	data, err := listVolumeData(context.Background(), client, nil)
+	if err != nil {
+		return volumeStatusUnknown(cmd, volumeID, err.Error())
+	}
+	volumes := volumeRecords(data)
+
+	host := newVolumeHost(false)
+	subsystems := host.listSubsystems()
+
+	fstab, _ := host.fs.ReadFile(fstabPath)
+	if volumeID != "" {
+		var selected []volumeRecord
+		for _, volume := range volumes {
+			if volume.ID == volumeID {
+				selected = append(selected, volume)
+			}
+		}
+		if len(selected) == 0 {
+			return volumeStatusUnknown(cmd, volumeID, fmt.Sprintf("Volume storage not found: %s", volumeID))
+		}
+		volumes = selected
+	} else {
+		volumes = attachedVolumes(volumes, subsystems, string(fstab))
+	}
+
+	sizes := map[string]uint64{}
+	if output, err := host.exec.Run("nvme", "list", "-o", "json"); err == nil {
+		sizes = parseNvmeListSizes(output)
+	}
+
+	mounts, err := host.readMounts()
+	if err != nil {
+		return volumeStatusUnknown(cmd, volumeID, err.Error())
+	}
+
+	statuses := make([]volumeStatus, 0, len(volumes))
+	for _, volume := range volumes {
+		statuses = append(statuses, host.volumeStatus(volume, subsystems, sizes, mounts))
+	}
+
+	renderVolumeStatuses(statuses)
+
+	return exitWithCode(cmd, volumeStatusExitCode(statuses))
+}
+
+// volumeStatusUnknown reports that the status of volumeID, empty for every attached volume, could
+// not be determined. With -o json it is an element of the usual array, so monitoring agents parse
+// a single shape
+func volumeStatusUnknown(cmd *cobra.Command, volumeID, msg string) error {
+	if volumeStatusJSON() {
+		renderVolumeStatuses([]volumeStatus{{VolumeID: volumeID, Health: volumeUnknown, Error: msg}})
+	} else {
+		printError(msg)
+	}
+
+	return exitWithCode(cmd, volumeStatusExitUnknown)
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseNvmeListSizes(t *testing.T) {
+	for _, fixture := range []string{"testdata/nvme-list-v1.json", "testdata/nvme-list-v2.json"} {
+		got := parseNvmeListSizes(readFixture(t, fixture))
+		want := map[string]uint64{"/dev/nvme1n1": 107374182400}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("parseNvmeListSizes(%s) = %v, want %v", fixture, got, want)
+		}
+	}
+}
+
+func TestVolumeStatus(t *testing.T) {
+	const (
+		df    = "Filesystem     1024-blocks     Used Available Capacity Mounted on\n/dev/nvme1n1     102626232 20525246  76841830      22% /data"
+		fstab = "/dev/sda1 / ext4 defaults 0 1\n# lsh volume vol_abc123\nUUID=0b7e3f5c /data ext4 defaults,_netdev,nofail 0 0\n"
+	)
+
+	tests := []struct {
+		name         string
+		listSubsys   string
+		mounts       string
+		fstab        string
+		multipath    string
+		wantHealth   volumeHealth
+		wantProblems []string
+	}{
+		{
+			name:       "all paths live and mounted",
+			listSubsys: "nvme-subsys1 - NQN=" + testSubsystemNQN + "\n\\\n +- nvme1 tcp traddr=67.213.118.147,trsvcid=4420 live\n +- nvme2 tcp traddr=67.213.118.148,trsvcid=4420 live\n",
+			mounts:     "/dev/nvme1n1 /data ext4 rw,relatime 0 0\n",
+			fstab:      fstab,
+			multipath:  "Y\n",
+			wantHealth: volumeHealthy,
+		},
+		{
+			name:         "one path down",
+			listSubsys:   readFixture(t, "testdata/nvme-list-subsys-v2.txt"),
+			mounts:       "/dev/nvme1n1 /data ext4 rw,relatime 0 0\n",
+			multipath:    "Y\n",
+			wantHealth:   volumeDegraded,
+			wantProblems: []string{"1 of 2 paths are not live"},
+		},
+		{
+			name:         "persistent mount missing without multipath",
+			listSubsys:   readFixture(t, "testdata/nvme-list-subsys-v2.txt"),
+			fstab:        fstab,
+			multipath:    "N\n",
+			wantHealth:   volumeDegraded,
+			wantProblems: []string{"1 of 2 paths are not live", "native NVMe multipath is disabled", "/data is not mounted"},
+		},
+		{
+			name:         "not connected",
+			fstab:        fstab,
+			wantHealth:   volumeDisconnected,
+			wantProblems: []string{"subsystem is not connected"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, _, fs := newTestVolumeHost(t, map[string]fakeResult{"df -P -k /data": {output: df}})
+			writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys1/subsysnqn", testSubsystemNQN+"\n")
+			writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys1/nvme1n1/size", "0")
+			writeTestFile(t, fs, fstabPath, tt.fstab)
+			if tt.multipath != "" {
+				writeTestFile(t, fs, multipathParamPath, tt.multipath)
+			}
+
+			volume := volumeRecord{ID: "vol_abc123", Name: "data", ConnectorID: testSubsystemNQN}
+			got := host.volumeStatus(volume, parseListSubsys(tt.listSubsys), map[string]uint64{}, parseMounts(tt.mounts))
+
+			if got.Health != tt.wantHealth {
+				t.Errorf("Health = %s, want %s", got.Health, tt.wantHealth)
+			}
+			if !reflect.DeepEqual(got.Problems, tt.wantProblems) {
+				t.Errorf("Problems = %q, want %q", got.Problems, tt.wantProblems)
+			}
+			if tt.mounts != "" && (len(got.Mounts) != 1 || got.Mounts[0].UsedBytes != 20525246*1024) {
+				t.Errorf("Mounts = %+v", got.Mounts)
+			}
+		})
+	}
+}
+
+func TestAttachedVolumesAndExitCode(t *testing.T) {
+	volumes := []volumeRecord{
+		{ID: "vol_abc123", ConnectorID: testSubsystemNQN},
+		{ID: "vol_persisted", ConnectorID: "nqn.2023-01.sh.latitude:vol-persisted"},
+		{ID: "vol_elsewhere", ConnectorID: "nqn.2023-01.sh.latitude:vol-elsewhere"},
+	}
+	subsystems := parseListSubsys(readFixture(t, "testdata/nvme-list-subsys-v1.txt"))
+	fstab := "# lsh volume vol_persisted\nUUID=1234 /backup xfs defaults,_netdev,nofail 0 0\n"
+
+	var got []string
+	for _, volume := range attachedVolumes(volumes, subsystems, fstab) {
+		got = append(got, volume.ID)
+	}
+	if want := []string{"vol_abc123", "vol_persisted"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("attachedVolumes() = %v, want %v", got, want)
+	}
+
+	statuses := []volumeStatus{{Health: volumeHealthy}, {Health: volumeDegraded}}
+	if code := volumeStatusExitCode(statuses); code != volumeStatusExitDegraded {
+		t.Errorf("volumeStatusExitCode() = %d, want %d", code, volumeStatusExitDegraded)
+	}
+	statuses = append(statuses, volumeStatus{Health: volumeDisconnected}, volumeStatus{Health: volumeDegraded})
+	if code := volumeStatusExitCode(statuses); code != volumeStatusExitDisconnected {
+		t.Errorf("volumeStatusExitCode() = %d, want %d", code, volumeStatusExitDisconnected)
+	}
+}
+
+func TestVolumeStatusUnknown(t *testing.T) {
+	cmd := &cobra.Command{Use: "status"}
+
+	var exitErr *ExitError
+	if err := volumeStatusUnknown(cmd, "vol_abc123", "API key not found"); !errors.As(err, &exitErr) || exitErr.Code != volumeStatusExitUnknown {
+		t.Fatalf("volumeStatusUnknown() = %v, want exit code %d", err, volumeStatusExitUnknown)
+	}
+	if !cmd.SilenceErrors {
+		t.Error("the error was already printed, cobra must not print it again")
+	}
+}
@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	latitudeshgosdk "github.com/latitudesh/latitudesh-go-sdk"
+	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/cmdflag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// nvmeNamespacePattern matches namespace block devices such as nvme1n1
+var nvmeNamespacePattern = regexp.MustCompile(`^nvme\d+n\d+$`)
+
+func makeOperationVolumeUnmountCmd() (*cobra.Command, error) {
+	operation := VolumeUnmountOperation{}
+
+	cmd, err := operation.Register()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+type VolumeUnmountOperation struct {
+	PathParamFlags cmdflag.Flags
+	OptionsFlags   cmdflag.Flags
+}
+
+// mountEntry is a line of /proc/mounts
+type mountEntry struct {
+	Source     string
+	Mountpoint string
+	FSType     string
+}
+
+func (o *VolumeUnmountOperation) Register() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "unmount",
+		Short: "Unmount a volume storage from a server",
+		Long: `Unmount a volume storage from a server. This command will:
+  1. Auto-fetch the volume's connector_id (subsystem NQN)
+  2. Unmount every filesystem mounted from the volume's NVMe devices
+  3. Remove the fstab, discovery.conf and systemd entries created by 'lsh volume mount'
+  4. Disconnect the NVMe-oF subsystem with 'nvme disconnect -n'
+
+The NQN of this server stays authorized on the volume, as the API does not revoke NQNs yet:
+revoke it from the web dashboard at https://www.latitude.sh.
+
+This command must be run with sudo/root privileges on the target server.
+
+Example:
+  sudo lsh volume unmount --id vol_abc123`,
+		RunE:   o.run,
+		PreRun: o.preRun,
+	}
+
+	o.registerFlags(cmd)
+
+	return cmd, nil
+}
+
+func (o *VolumeUnmountOperation) registerFlags(cmd *cobra.Command) {
+	o.PathParamFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+	o.OptionsFlags = cmdflag.Flags{FlagSet: cmd.Flags()}
+
+	pathParamsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "id",
+			Label:       "Volume Storage ID",
+			Description: "The ID of the volume storage to unmount",
+			Required:    true,
+		},
+	}
+
+	optionsSchema := &cmdflag.FlagsSchema{
+		&cmdflag.String{
+			Name:        "subsystem-nqn",
+			Label:       "Subsystem NQN",
+			Description: "Override the subsystem NQN (optional, auto-fetched from volume storage's connector_id)",
+			Required:    false,
+		},
+	}
+
+	o.PathParamFlags.Register(pathParamsSchema)
+	o.OptionsFlags.Register(optionsSchema)
+}
+
+func (o *VolumeUnmountOperation) preRun(cmd *cobra.Command, args []string) {
+	o.PathParamFlags.PreRun(cmd, args)
+	o.OptionsFlags.PreRun(cmd, args)
+}
+
+// findSubsystemDevices lists the namespace block devices of a connected subsystem using sysfs
+func (h *volumeHost) findSubsystemDevices(subsystemNQN string) []string {
+	seen := make(map[string]bool)
+	var devices []string
+
+	addNamespaces := func(dir string) {
+		entries, err := h.fs.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if nvmeNamespacePattern.MatchString(name) && !seen[name] {
+				seen[name] = true
+				devices = append(devices, "/dev/"+name)
+			}
+		}
+	}
+
+	nqnFiles, _ := h.fs.Glob(filepath.Join(sysfsRoot, "class", "nvme-subsystem", "*", "subsysnqn"))
+	for _, nqnFile := range nqnFiles {
+		content, err := h.fs.ReadFile(nqnFile)
+		if err != nil || strings.TrimSpace(string(content)) != subsystemNQN {
+			continue
+		}
+
+		// Native multipath exposes the namespaces on the subsystem,
+		// otherwise they are found on each controller
+		subsystemDir := filepath.Dir(nqnFile)
+		addNamespaces(subsystemDir)
+
+		controllers, _ := h.fs.Glob(filepath.Join(subsystemDir, "nvme[0-9]*"))
+		for _, controller := range controllers {
+			if !nvmeNamespacePattern.MatchString(filepath.Base(controller)) {
+				addNamespaces(controller)
+			}
+		}
+	}
+
+	sort.Strings(devices)
+	return devices
+}
+
+// parseMounts parses the content of /proc/mounts
+func parseMounts(content string) []mountEntry {
+	var mounts []mountEntry
+
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		mounts = append(mounts, mountEntry{
+			Source:     unescapeMountField(fields[0]),
+			Mountpoint: unescapeMountField(fields[1]),
+			FSType:     fields[2],
+		})
+	}
+
+	return mounts
+}
+
+// unescapeMountField decodes the octal escapes (e.g. \040 for a space) used in /proc/mounts
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+4 <= len(field) {
+			if value, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(field[i])
+	}
+
+	return b.String()
+}
+
+// volumeMounts returns the mounts whose source is one of the devices or their partitions,
+// deepest mountpoint first so nested mounts are unmounted before their parents
+func volumeMounts(mounts []mountEntry, devices []string) []mountEntry {
+	var matched []mountEntry
+
+	for _, mount := range mounts {
+		for _, device := range devices {
+			if mount.Source == device || strings.HasPrefix(mount.Source, device+"p") {
+				matched = append(matched, mount)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return len(matched[i].Mountpoint) > len(matched[j].Mountpoint)
+	})
+
+	return matched
+}
+
+func (o *VolumeUnmountOperation) run(cmd *cobra.Command, args []string) error {
+	if !lsh.DryRun {
+		if err := checkRoot("unmount"); err != nil {
+			printError(err.Error())
+			return err
+		}
+	}
+
+	volumeID, err := cmd.Flags().GetString("id")
+	if err != nil {
+		return fmt.Errorf("error getting volume ID: %w", err)
+	}
+	subsystemNQN, _ := cmd.Flags().GetString("subsystem-nqn")
+
+	host := newVolumeHost(lsh.DryRun)
+
+	if subsystemNQN == "" && lsh.DryRun {
+		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
+		subsystemNQN = fmt.Sprintf("<connector_id of %s>", volumeID)
+	} else if subsystemNQN == "" {
+		apiKey := viper.GetString("authorization")
+		if apiKey == "" {
+			apiKey = viper.GetString("Authorization")
+		}
+		if apiKey == "" {
+			return fmt.Errorf("API key not found. Please run 'lsh login <API_KEY>' first")
+		}
+
+		fmt.Fprintf(os.Stdout, "\n📋 Fetching volume details...\n")
+		printStatus(fmt.Sprintf("Volume ID: %s", volumeID))
+
+		client := latitudeshgosdk.New(latitudeshgosdk.WithSecurity(apiKey))
+		subsystemNQN, err = fetchVolumeConnectorID(context.Background(), client, volumeID)
+		if err != nil {
+			return err
+		}
+	} else {
+		printStatus(fmt.Sprintf("Using provided subsystem NQN: %s", subsystemNQN))
+	}
+
+	fmt.Fprintf(os.Stdout, "\n📤 Unmounting volume...\n\n")
+
+	devices := host.findSubsystemDevices(subsystemNQN)
+	if len(devices) == 0 {
+		printWarning("No NVMe devices found for this volume")
+	} else {
+		printStatus(fmt.Sprintf("Volume devices: %s", strings.Join(devices, ", ")))
+	}
+
+	procMounts, err := host.readMounts()
+	if err != nil {
+		return err
+	}
+
+	mounts := volumeMounts(procMounts, devices)
+	if len(mounts) == 0 {
+		printStatus("No mounted filesystems found for this volume")
+	}
+
+	for _, mount := range mounts {
+		printStatus(fmt.Sprintf("Unmounting %s (%s)...", mount.Mountpoint, mount.Source))
+		if output, err := host.exec.Run("umount", mount.Mountpoint); err != nil {
+			printError(fmt.Sprintf("Failed to unmount %s: %s", mount.Mountpoint, output))
+			printError(fmt.Sprintf("Check which processes are using it with: sudo fuser -vm %s", mount.Mountpoint))
+			return fmt.Errorf("failed to unmount %s: %w", mount.Mountpoint, err)
+		}
+		printStatus(fmt.Sprintf("✓ Unmounted %s", mount.Mountpoint))
+	}
+
+	if err := host.removeVolumePersistence(volumeID); err != nil {
+		printError(err.Error())
+		return err
+	}
+
+	if _, connected := findSubsystem(host.listSubsystems(), subsystemNQN); connected || host.dryRun {
+		printStatus(fmt.Sprintf("Disconnecting subsystem %s...", subsystemNQN))
+		if output, err := host.exec.Run("nvme", "disconnect", "-n", subsystemNQN); err != nil {
+			printError(fmt.Sprintf("nvme disconnect failed: %s", output))
+			return fmt.Errorf("failed to disconnect %s: %w", subsystemNQN, err)
+		}
+		printStatus("✓ Disconnected from NVMe-oF target")
+	} else {
+		printWarning("Subsystem is not connected, skipping nvme disconnect")
+	}
+
+	if host.dryRun {
+		fmt.Fprintf(os.Stdout, "\n[DRY-RUN] Volume unmount plan complete. Nothing was changed.\n")
+		return nil
+	}
+
+	fmt.Fprintf(os.Stdout, "\n✅ Volume unmount complete!\n")
+	fmt.Fprintf(os.Stdout, "\nSummary:\n")
+	fmt.Fprintf(os.Stdout, "  Unmounted filesystems: %d\n", len(mounts))
+	fmt.Fprintf(os.Stdout, "  Subsystem NQN: %s\n", subsystemNQN)
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testSubsystemNQN = "nqn.2023-01.sh.latitude:vol-abc123"
+
+func TestFindSubsystemDevices(t *testing.T) {
+	host, _, fs := newTestVolumeHost(t, nil)
+
+	// multipath: namespace on the subsystem, hidden per-path devices on the controllers
+	writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys1/subsysnqn", testSubsystemNQN+"\n")
+	writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys1/nvme1n1/size", "0")
+	writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys1/nvme1/nvme1c1n1/size", "0")
+	// non multipath: namespace on the controller
+	writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys2/subsysnqn", testSubsystemNQN+"\n")
+	writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys2/nvme2/nvme2n1/size", "0")
+	// another volume
+	writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys0/subsysnqn", "nqn.2023-01.sh.latitude:vol-other\n")
+	writeTestFile(t, fs, "/sys/class/nvme-subsystem/nvme-subsys0/nvme0n1/size", "0")
+
+	got := host.findSubsystemDevices(testSubsystemNQN)
+	want := []string{"/dev/nvme1n1", "/dev/nvme2n1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findSubsystemDevices() = %v, want %v", got, want)
+	}
+}
+
+func TestVolumeMounts(t *testing.T) {
+	procMounts := `/dev/sda1 / ext4 rw,relatime 0 0
+/dev/nvme1n1 /data ext4 rw,relatime 0 0
+/dev/nvme1n1p2 /data/my\040logs xfs rw,relatime 0 0
+/dev/nvme10n1 /other ext4 rw,relatime 0 0
+`
+
+	got := volumeMounts(parseMounts(procMounts), []string{"/dev/nvme1n1"})
+	want := []mountEntry{
+		{Source: "/dev/nvme1n1p2", Mountpoint: "/data/my logs", FSType: "xfs"},
+		{Source: "/dev/nvme1n1", Mountpoint: "/data", FSType: "ext4"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("volumeMounts() = %v, want %v", got, want)
+	}
+}
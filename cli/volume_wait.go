@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	volumeWaitInterval = 2 * time.Second
+	volumeWaitTimeout  = 2 * time.Minute
+)
+
+// waitFor polls check until it reports done, printing its progress, or fails after timeout
+func (h *volumeHost) waitFor(description string, timeout time.Duration, check func() (bool, string, error)) error {
+	if h.dryRun {
+		fmt.Fprintf(os.Stdout, "[DRY-RUN] wait up to %s for %s\n", timeout, description)
+		return nil
+	}
+
+	printStatus(fmt.Sprintf("Waiting for %s...", description))
+	for waited := time.Duration(0); ; waited += volumeWaitInterval {
+		done, progress, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			printStatus(fmt.Sprintf("✓ %s", progress))
+			return nil
+		}
+		if waited >= timeout {
+			return fmt.Errorf("timed out after %s waiting for %s (%s)", timeout, description, progress)
+		}
+
+		printStatus(fmt.Sprintf("  %s (%s elapsed)", progress, waited))
+		h.sleep(volumeWaitInterval)
+	}
+}
@@ -31,5 +31,9 @@ func Execute() (*cobra.Command, error) {
 	rootCmd.PersistentFlags().BoolVar(&lsh.DryRun, "dry-run", false, "do not send the request to server")
 	rootCmd.PersistentFlags().BoolVar(&lsh.Debug, "debug", false, "output debug logs")
 
-	return cmd, cmd.Execute()
+	notifier := startUpdateNotifier()
+	executed, err := cmd.ExecuteC()
+	printUpdateNotice(notifier, executed)
+
+	return cmd, err
 }
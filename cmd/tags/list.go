@@ -3,7 +3,9 @@ package tags
 import (
 	"context"
 
+	latitudeshgosdk "github.com/latitudesh/latitudesh-go-sdk"
 	"github.com/latitudesh/lsh/cmd/lsh"
+	"github.com/latitudesh/lsh/internal/renderer"
 	"github.com/latitudesh/lsh/internal/utils"
 	cobra "github.com/spf13/cobra"
 )
@@ -17,6 +19,8 @@ func NewListCmd() *cobra.Command {
 		Use:   "list",
 	}
 
+	utils.RegisterWatchFlag(cmd)
+
 	return cmd
 }
 
@@ -26,17 +30,45 @@ func (o *ListTagOperation) run(cmd *cobra.Command, args []string) error {
 	client := lsh.NewClient()
 	ctx := context.Background()
 
+	interval, err := utils.WatchInterval(cmd, args)
+	if err != nil {
+		return err
+	}
+
 	if lsh.DryRun {
 		lsh.LogDebugf("dry-run flag specified. Skip sending request.")
 		return nil
 	}
 
-	response, err := client.Tags.List(ctx)
+	if interval > 0 {
+		return renderer.Watch("Tags", interval, func() ([]renderer.ResponseData, error) {
+			lshTags, err := o.list(ctx, client)
+			if err != nil {
+				return nil, err
+			}
+			return lshTags.GetData(), nil
+		})
+	}
+
+	lshTags, err := o.list(ctx, client)
 	if err != nil {
 		utils.PrintError(err)
 		return err
 	}
 
+	if !lsh.Debug {
+		utils.Render(lshTags.GetData())
+	}
+
+	return nil
+}
+
+func (o *ListTagOperation) list(ctx context.Context, client *latitudeshgosdk.Latitudesh) (*Tags, error) {
+	response, err := client.Tags.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	lsgTagData := []*Tag{}
 	if response.CustomTags != nil && response.CustomTags.Data != nil {
 		for _, tag := range response.CustomTags.Data {
@@ -47,13 +79,7 @@ func (o *ListTagOperation) run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	lshTags := Tags{
+	return &Tags{
 		Data: lsgTagData,
-	}
-
-	if !lsh.Debug {
-		utils.Render(lshTags.GetData())
-	}
-
-	return nil
+	}, nil
 }
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/latitudesh/lsh/internal/updater"
+	"github.com/latitudesh/lsh/internal/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// updateNoticeGrace is how long a finished command waits on a running release check
+const updateNoticeGrace = 500 * time.Millisecond
+
+// startUpdateNotifier starts the release check unless the notice could end up in a script or a log:
+// outside a terminal, on CI, or when disabled with LSH_NO_UPDATE_NOTIFIER
+func startUpdateNotifier() *updater.Notifier {
+	if updater.NotifierDisabledByEnv(os.Getenv) {
+		return nil
+	}
+	if !term.IsTerminal(int(os.Stdout.Fd())) || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return nil
+	}
+
+	return updater.StartNotifier(version.Version)
+}
+
+// printUpdateNotice prints the release notice on stderr after the command ran
+func printUpdateNotice(notifier *updater.Notifier, executed *cobra.Command) {
+	if notifier == nil || executed == nil || !showsUpdateNotice(executed) {
+		return
+	}
+
+	if notice := notifier.Notice(updateNoticeGrace); notice != "" {
+		fmt.Fprintf(os.Stderr, "\n%s\n", notice)
+	}
+}
+
+// showsUpdateNotice skips JSON output, the update command itself and shell completion
+func showsUpdateNotice(executed *cobra.Command) bool {
+	if viper.GetBool("json") || viper.GetString("output") == "json" {
+		return false
+	}
+
+	// The top level command, such as servers for 'lsh servers list'
+	top := executed
+	for top.HasParent() && top.Parent().HasParent() {
+		top = top.Parent()
+	}
+
+	switch top.Name() {
+	case "update", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return false
+	}
+
+	return true
+}
@@ -19,6 +19,7 @@ func NewServerResource() *ServerResource {
 		SupportedOperatingSystems: []string{
 			"ipxe",
 			"windows_server_2019_std_v1",
+			"ubuntu_24_04_x64_lts",
 			"ubuntu_22_04_x64_lts",
 			"debian_11",
 			"rockylinux_8",
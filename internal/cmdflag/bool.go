@@ -36,6 +36,11 @@ func (f *Bool) Register(s *pflag.FlagSet) {
 	f.Value = s.Bool(f.Name, f.defaultValue, f.description())
 }
 
+func (f *Bool) Validate() []error {
+	//Function needed to implement interface
+	return nil
+}
+
 func (f *Bool) label() string {
 	if f.Required {
 		return f.Label
@@ -2,6 +2,7 @@ package cmdflag
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/latitudesh/lsh/internal/prompt"
 	"github.com/spf13/pflag"
@@ -14,12 +15,19 @@ type Int64 struct {
 	defaultValue int64
 	Required     bool
 	Label        string
+	// Env takes the value from LSH_<FLAG> and the config defaults when missing from the command line
+	Env        bool
+	Validators []Validator
 }
 
 func (f *Int64) GetValue() interface{} {
 	return *f.Value
 }
 
+func (f *Int64) envFallback() bool {
+	return f.Env
+}
+
 func (f *Int64) GetName() string {
 	return f.Name
 }
@@ -33,7 +41,15 @@ func (f *Int64) Prompt() prompt.PromptInput {
 }
 
 func (f *Int64) Register(s *pflag.FlagSet) {
-	f.Value = s.Int64(f.Name, f.defaultValue, f.description())
+	f.Value = new(int64)
+	s.Var(newInt64Value(f.defaultValue, f.Value), f.Name, f.description())
+}
+
+func (f *Int64) Validate() []error {
+	if err := validateValue(strconv.FormatInt(*f.Value, 10), f.Validators); err != nil {
+		return []error{err}
+	}
+	return nil
 }
 
 func (f *Int64) label() string {
@@ -1,8 +1,10 @@
 package cmdflag
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
 
 	"github.com/go-openapi/swag"
 	"github.com/latitudesh/lsh/internal/prompt"
@@ -18,6 +20,7 @@ type FlagSchema interface {
 	GetName() string
 	Prompt() prompt.PromptInput
 	IsRequired() bool
+	Validate() []error
 }
 
 type FlagsSchema []FlagSchema
@@ -26,10 +29,23 @@ type Flags struct {
 	schema            *FlagsSchema
 	FlagSet           *pflag.FlagSet
 	PromptDescription string
+	// cmd is the command the flags were prepared for by PreRun
+	cmd *cobra.Command
 }
 
+// registered are the flags registered on each command, checked together by Prepare
+var registered = map[*pflag.FlagSet][]*Flags{}
+
+// prepared keeps the outcome of prepare for each schema, whose values are resolved only once
+var prepared = map[*FlagsSchema]error{}
+
+// ResolvePrompted rewrites the flags of cmd once the values typed at the prompt are set on them,
+// e.g. names given where an ID is expected. Set by the cli package
+var ResolvePrompted func(cmd *cobra.Command) error
+
 func (f *Flags) Register(s *FlagsSchema) {
 	f.schema = s
+	registered[f.FlagSet] = append(registered[f.FlagSet], f)
 
 	for _, v := range *s {
 		v.Register(f.FlagSet)
@@ -55,6 +71,87 @@ func (f *Flags) GetInputs() []prompt.PromptInput {
 }
 
 func (f *Flags) AssignValues(params interface{}) error {
+	f.AssignFlagValues(params)
+
+	if f.interactiveModeEnabled() {
+		p := prompt.Prompt{
+			Description: f.PromptDescription,
+			Inputs:      f.GetInputs(),
+		}
+		p.Run(params)
+
+		if err := f.completePrompted(params); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	return nil
+}
+
+// completePrompted sets the values typed at the prompt on the flags, validates them like those of
+// the command line, resolves them with ResolvePrompted and assigns the outcome back to params
+func (f *Flags) completePrompted(params interface{}) error {
+	var prompted []FlagSchema
+	for _, v := range *f.schema {
+		flag := f.FlagSet.Lookup(v.GetName())
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		field := utils.GetFieldValue(params, v.GetName())
+		if !field.IsValid() || field.Kind() == reflect.Struct || field.IsZero() {
+			continue
+		}
+		if err := setFlagValue(flag, field); err != nil {
+			return fmt.Errorf("--%s: %w", v.GetName(), err)
+		}
+		prompted = append(prompted, v)
+	}
+
+	if len(prompted) == 0 {
+		return nil
+	}
+
+	if err := f.validate(); err != nil {
+		return err
+	}
+	if ResolvePrompted != nil && f.cmd != nil {
+		if err := ResolvePrompted(f.cmd); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range prompted {
+		utils.AssignValue(params, v.GetName(), v.GetValue())
+	}
+	return nil
+}
+
+// setFlagValue sets value, assigned by the prompt, on flag as if it was given on the command line
+func setFlagValue(flag *pflag.Flag, value reflect.Value) error {
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	if slice, ok := flag.Value.(pflag.SliceValue); ok && value.Kind() == reflect.Slice {
+		items := make([]string, value.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(value.Index(i).Interface())
+		}
+		if err := slice.Replace(items); err != nil {
+			return err
+		}
+	} else if err := flag.Value.Set(fmt.Sprint(value.Interface())); err != nil {
+		return err
+	}
+
+	flag.Changed = true
+	return nil
+}
+
+// AssignFlagValues assigns the values given on the command line without prompting for the others
+func (f *Flags) AssignFlagValues(params interface{}) {
 	for _, v := range *f.schema {
 		value := v.GetValue()
 
@@ -62,19 +159,63 @@ func (f *Flags) AssignValues(params interface{}) error {
 			utils.AssignValue(params, v.GetName(), value)
 		}
 	}
+}
 
-	if f.interactiveModeEnabled() {
-		p := prompt.Prompt{
-			Description: f.PromptDescription,
-			Inputs:      f.GetInputs(),
+// Prepare completes and validates the values of every flags registered on cmd, before its PreRun
+// sends any request. All the invalid values are reported together
+func Prepare(cmd *cobra.Command) {
+	var errs []error
+	for _, f := range registered[cmd.Flags()] {
+		errs = append(errs, f.prepare())
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// prepare completes the flags from the other sources of values, see resolveValues, then runs the
+// validators of those given
+func (f *Flags) prepare() error {
+	if err, ok := prepared[f.schema]; ok {
+		return err
+	}
+
+	err := f.resolveValues()
+	if err == nil {
+		err = f.validate()
+	}
+	prepared[f.schema] = err
+	return err
+}
+
+func (f *Flags) validate() error {
+	var errs []error
+
+	for _, v := range *f.schema {
+		if !f.FlagSet.Changed(v.GetName()) {
+			continue
+		}
+
+		for _, err := range v.Validate() {
+			errs = append(errs, fmt.Errorf("--%s: %w", v.GetName(), err))
 		}
-		p.Run(params)
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
+// PreRun completes and validates the flags, see prepare, and requires the missing ones when
+// prompting is disabled
 func (f *Flags) PreRun(cmd *cobra.Command, args []string) {
+	f.cmd = cmd
+
+	if err := f.prepare(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	if f.interactiveModeEnabled() {
 		return
 	}
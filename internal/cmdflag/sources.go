@@ -0,0 +1,220 @@
+package cmdflag
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// A flag value is taken from the first of these sources that has one:
+//
+//  1. the command line, --public_key "ssh-ed25519 AAAA..."
+//  2. the LSH_<FLAG> environment variable, LSH_PUBLIC_KEY for --public_key
+//  3. the defaults section of the config file, {"defaults": {"project": "acme"}}
+//  4. the interactive prompt, unless --no-input is given
+//
+// Sources 2 and 3 only apply to the flags opting in with Env, values shared by many commands such
+// as the project, so that an unrelated LSH_ID or LSH_FORCE never reaches a command. The defaults
+// are a section of their own rather than the top-level settings of the config file, where the
+// global flags such as --hostname are bound and a default would collide with them.
+//
+// Whatever the source, a value of @path is replaced by the content of the file and @- by the
+// standard input. A leading @@ stands for a literal @. For string slices, every non-empty line of
+// the file is an item.
+
+// EnvPrefix prefixes the environment variables giving flag values
+const EnvPrefix = "LSH_"
+
+// defaultsConfigKey is the section of the config file giving flag values
+const defaultsConfigKey = "defaults"
+
+// envFallback is implemented by the flags that may opt in to the environment and config defaults
+type envFallback interface {
+	envFallback() bool
+}
+
+var (
+	getenv = os.Getenv
+	// stdin is read once, by the first @- value
+	stdin     io.Reader = os.Stdin
+	stdinRead bool
+)
+
+// EnvName returns the environment variable giving the value of a flag, LSH_PUBLIC_KEY for public_key
+func EnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(flagName))
+}
+
+// resolveValues sets the flags missing from the command line from the environment and the config
+// defaults, then reads the @path and @- values. All the failures are reported together
+func (f *Flags) resolveValues() error {
+	var errs []error
+
+	for _, v := range *f.schema {
+		flag := f.FlagSet.Lookup(v.GetName())
+		if flag == nil {
+			continue
+		}
+
+		if fallback, ok := v.(envFallback); ok && fallback.envFallback() && !flag.Changed {
+			if value, source, ok := fallbackValue(flag.Name); ok {
+				if err := f.FlagSet.Set(flag.Name, value); err != nil {
+					errs = append(errs, fmt.Errorf("invalid value %q for --%s from %s: %w", value, flag.Name, source, err))
+					continue
+				}
+			}
+		}
+
+		if flag.Changed {
+			if err := readSourceValue(flag); err != nil {
+				errs = append(errs, fmt.Errorf("--%s: %w", flag.Name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// fallbackValue returns the value of a flag missing from the command line and where it comes from
+func fallbackValue(flagName string) (value, source string, ok bool) {
+	env := EnvName(flagName)
+	if value := getenv(env); value != "" {
+		return value, env, true
+	}
+
+	key := defaultsConfigKey + "." + flagName
+	if viper.IsSet(key) {
+		if value := viper.GetString(key); value != "" {
+			return value, "the config defaults", true
+		}
+	}
+
+	return "", "", false
+}
+
+// readSourceValue replaces the @path and @- values of flag by what they refer to
+func readSourceValue(flag *pflag.Flag) error {
+	switch value := flag.Value.(type) {
+	case *int64Value:
+		if value.source == "" {
+			return nil
+		}
+		content, err := readSource(value.source)
+		if err != nil {
+			return err
+		}
+		value.source = ""
+		return value.Set(strings.TrimSpace(content))
+
+	case pflag.SliceValue:
+		var items []string
+		for _, item := range value.GetSlice() {
+			if !isSourceRef(item) {
+				items = append(items, strings.TrimPrefix(item, "@"))
+				continue
+			}
+
+			content, err := readSource(item)
+			if err != nil {
+				return err
+			}
+			for _, line := range strings.Split(content, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					items = append(items, line)
+				}
+			}
+		}
+		return value.Replace(items)
+	}
+
+	text := flag.Value.String()
+	if !strings.HasPrefix(text, "@") {
+		return nil
+	}
+	if !isSourceRef(text) {
+		return flag.Value.Set(text[1:])
+	}
+
+	content, err := readSource(text)
+	if err != nil {
+		return err
+	}
+	return flag.Value.Set(strings.TrimRight(content, "\r\n"))
+}
+
+// isSourceRef reports whether value is @path or @-, rather than a literal escaped as @@
+func isSourceRef(value string) bool {
+	return strings.HasPrefix(value, "@") && !strings.HasPrefix(value, "@@")
+}
+
+// readSource returns the content of the file of ref, @path, or the standard input for @-
+func readSource(ref string) (string, error) {
+	name := strings.TrimPrefix(ref, "@")
+
+	if name == "-" {
+		if stdinRead {
+			return "", errors.New("the standard input (@-) can only be read by one flag")
+		}
+		stdinRead = true
+
+		content, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("could not read the standard input: %w", err)
+		}
+		return string(content), nil
+	}
+
+	if name == "" {
+		return "", errors.New("missing file name after @, use @@ for a value starting with @")
+	}
+
+	path, err := homedir.Expand(name)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read %s: %w", name, err)
+	}
+	return string(content), nil
+}
+
+// int64Value is an int64 flag also accepting @path and @-, which are read with the other sources
+type int64Value struct {
+	value  *int64
+	source string
+}
+
+func newInt64Value(value int64, p *int64) *int64Value {
+	*p = value
+	return &int64Value{value: p}
+}
+
+func (v *int64Value) Set(s string) error {
+	if isSourceRef(s) {
+		v.source = s
+		return nil
+	}
+
+	n, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		return err
+	}
+	*v.value = n
+	return nil
+}
+
+func (v *int64Value) Type() string {
+	return "int64"
+}
+
+func (v *int64Value) String() string {
+	return strconv.FormatInt(*v.value, 10)
+}
@@ -0,0 +1,179 @@
+package cmdflag
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// testFlags registers a string, an int64 and a string slice flag and parses args
+func testFlags(t *testing.T, env map[string]string, defaults map[string]interface{}, args ...string) (*Flags, error) {
+	t.Helper()
+
+	getenv = func(key string) string { return env[key] }
+	stdinRead = false
+	for key, value := range defaults {
+		viper.Set(defaultsConfigKey+"."+key, value)
+	}
+	t.Cleanup(func() {
+		getenv = os.Getenv
+		stdin = os.Stdin
+		stdinRead = false
+		viper.Reset()
+	})
+
+	flags := &Flags{FlagSet: pflag.NewFlagSet("test", pflag.ContinueOnError)}
+	flags.Register(&FlagsSchema{
+		&String{Name: "public_key", Label: "Public key", Env: true},
+		&String{Name: "project", Label: "Project", Env: true},
+		&Int64{Name: "vid", Label: "VLAN ID", Env: true},
+		&StringSlice{Name: "tags", Label: "Tags", Env: true},
+		&String{Name: "id", Label: "ID"},
+		&Bool{Name: "force", Label: "Force"},
+	})
+	if err := flags.FlagSet.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	return flags, flags.resolveValues()
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveValues_Precedence(t *testing.T) {
+	env := map[string]string{"LSH_PROJECT": "from-env", "LSH_VID": "42"}
+	defaults := map[string]interface{}{"project": "from-defaults", "public_key": "ssh-ed25519 AAAA default", "vid": 7}
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		defaults    map[string]interface{}
+		args        []string
+		wantProject string
+		wantVID     int64
+	}{
+		{"flag wins", env, defaults, []string{"--project", "from-flag", "--vid", "1"}, "from-flag", 1},
+		{"env before defaults", env, defaults, nil, "from-env", 42},
+		{"defaults", nil, defaults, nil, "from-defaults", 7},
+		{"nothing left for the prompt", nil, nil, nil, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := testFlags(t, tt.env, tt.defaults, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			project, _ := flags.FlagSet.GetString("project")
+			vid, _ := flags.FlagSet.GetInt64("vid")
+			if project != tt.wantProject || vid != tt.wantVID {
+				t.Errorf("project = %q, vid = %d, want %q, %d", project, vid, tt.wantProject, tt.wantVID)
+			}
+			// A value from the environment or the defaults counts as given, so the prompt skips it
+			if changed := flags.FlagSet.Changed("project"); changed != (tt.wantProject != "") {
+				t.Errorf("Changed(project) = %v", changed)
+			}
+		})
+	}
+}
+
+func TestResolveValues_EnvIsOptIn(t *testing.T) {
+	env := map[string]string{"LSH_ID": "sv_1", "LSH_FORCE": "true"}
+	defaults := map[string]interface{}{"id": "sv_2", "force": true}
+
+	flags, err := testFlags(t, env, defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if flags.FlagSet.Changed("id") || flags.FlagSet.Changed("force") {
+		t.Errorf("flags without Env were set from the environment or the defaults")
+	}
+}
+
+func TestResolveValues_ReadsFiles(t *testing.T) {
+	key := writeFile(t, "id_ed25519.pub", "ssh-ed25519 AAAAC3Nza user@host\n")
+	vid := writeFile(t, "vid", "2042\n")
+	tags := writeFile(t, "tags", "tag_web\n\ntag_prod\n")
+
+	flags, err := testFlags(t, nil, nil, "--public_key", "@"+key, "--vid", "@"+vid, "--tags", "tag_db", "--tags", "@"+tags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := flags.FlagSet.GetString("public_key"); got != "ssh-ed25519 AAAAC3Nza user@host" {
+		t.Errorf("public_key = %q", got)
+	}
+	if got, _ := flags.FlagSet.GetInt64("vid"); got != 2042 {
+		t.Errorf("vid = %d", got)
+	}
+	if got, _ := flags.FlagSet.GetStringSlice("tags"); !slices.Equal(got, []string{"tag_db", "tag_web", "tag_prod"}) {
+		t.Errorf("tags = %q", got)
+	}
+}
+
+func TestResolveValues_ReadsStdinOnce(t *testing.T) {
+	stdin = strings.NewReader("ssh-rsa AAAAB3Nza\n")
+	flags, err := testFlags(t, map[string]string{"LSH_PUBLIC_KEY": "@-"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := flags.FlagSet.GetString("public_key"); got != "ssh-rsa AAAAB3Nza" {
+		t.Errorf("public_key = %q", got)
+	}
+
+	_, err = testFlags(t, nil, nil, "--public_key", "@-", "--project", "@-")
+	if err == nil || !strings.Contains(err.Error(), "only be read by one flag") {
+		t.Errorf("expected a second @- to fail, got %v", err)
+	}
+}
+
+func TestResolveValues_ReportsAllErrors(t *testing.T) {
+	_, err := testFlags(t, map[string]string{"LSH_VID": "ten"}, nil, "--public_key", "@/nonexistent/key.pub")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	for _, want := range []string{"--public_key: could not read /nonexistent/key.pub", `invalid value "ten" for --vid from LSH_VID`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
+
+func TestResolveValues_EscapedAt(t *testing.T) {
+	flags, err := testFlags(t, nil, nil, "--project", "@@home", "--tags", "@@web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := flags.FlagSet.GetString("project"); got != "@home" {
+		t.Errorf("project = %q", got)
+	}
+	if got, _ := flags.FlagSet.GetStringSlice("tags"); !slices.Equal(got, []string{"@web"}) {
+		t.Errorf("tags = %q", got)
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if got := EnvName("public_key"); got != "LSH_PUBLIC_KEY" {
+		t.Errorf("EnvName(public_key) = %q", got)
+	}
+	if got := EnvName("no-input"); got != "LSH_NO_INPUT" {
+		t.Errorf("EnvName(no-input) = %q", got)
+	}
+}
@@ -2,6 +2,7 @@ package cmdflag
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/latitudesh/lsh/internal/prompt"
 	"github.com/spf13/pflag"
@@ -16,12 +17,25 @@ type String struct {
 	Value          *string
 	Options        []string
 	Required       bool
+	// AllowUnlisted accepts values missing from Options with a warning, for the lists the API
+	// extends over time, such as sites, plans and operating systems
+	AllowUnlisted bool
+	// Env takes the value from LSH_<FLAG> and the config defaults when missing from the command line
+	Env        bool
+	Validators []Validator
+	// enum are the Options given in the schema, which the value must be one of. Those added later
+	// by UpdateOptions, such as the projects of the user, are only offered by the prompt
+	enum []string
 }
 
 func (f *String) GetValue() interface{} {
 	return *f.Value
 }
 
+func (f *String) envFallback() bool {
+	return f.Env
+}
+
 func (f *String) GetName() string {
 	return f.Name
 }
@@ -40,6 +54,28 @@ func (f *String) Prompt() prompt.PromptInput {
 
 func (f *String) Register(s *pflag.FlagSet) {
 	f.Value = s.String(f.Name, f.defaultValue, f.description())
+
+	for _, option := range f.Options {
+		if option != skipOption {
+			f.enum = append(f.enum, option)
+		}
+	}
+}
+
+func (f *String) Validate() []error {
+	if len(f.enum) > 0 {
+		if err := OneOf(f.enum...)(*f.Value); err != nil {
+			if !f.AllowUnlisted {
+				return []error{err}
+			}
+			fmt.Fprintf(os.Stderr, "Warning: --%s: %v, sending it as the API may support it\n", f.Name, err)
+		}
+	}
+
+	if err := validateValue(*f.Value, f.Validators); err != nil {
+		return []error{err}
+	}
+	return nil
 }
 
 func (f *String) label() string {
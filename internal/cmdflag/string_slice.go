@@ -14,12 +14,20 @@ type StringSlice struct {
 	defaultValue []string
 	Value        *[]string
 	Required     bool
+	// Env takes the value from LSH_<FLAG> and the config defaults when missing from the command line
+	Env bool
+	// Validators check every item
+	Validators []Validator
 }
 
 func (f *StringSlice) GetValue() interface{} {
 	return *f.Value
 }
 
+func (f *StringSlice) envFallback() bool {
+	return f.Env
+}
+
 func (f *StringSlice) GetName() string {
 	return f.Name
 }
@@ -36,6 +44,16 @@ func (f *StringSlice) Register(s *pflag.FlagSet) {
 	f.Value = s.StringSlice(f.Name, f.defaultValue, f.description())
 }
 
+func (f *StringSlice) Validate() []error {
+	var errs []error
+	for _, item := range *f.Value {
+		if err := validateValue(item, f.Validators); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
 func (f *StringSlice) label() string {
 	if f.Required {
 		return f.Label
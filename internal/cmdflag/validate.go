@@ -0,0 +1,227 @@
+package cmdflag
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Validator checks a flag value before any request is sent. The error says what is expected
+type Validator func(value string) error
+
+// skipOption only stands for no value in the prompt of a flag with options
+const skipOption = "SKIP"
+
+// OneOf accepts one of options, and suggests the closest one otherwise
+func OneOf(options ...string) Validator {
+	return func(value string) error {
+		for _, option := range options {
+			if value == option {
+				return nil
+			}
+		}
+
+		if suggestion, ok := closestOption(value, options); ok {
+			return fmt.Errorf("%q is not one of %s, did you mean %q?", value, strings.Join(options, ", "), suggestion)
+		}
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(options, ", "))
+	}
+}
+
+// Matches accepts the values matching pattern, described as expected in the error, e.g. "an http(s) URL"
+func Matches(pattern, expected string) Validator {
+	re := regexp.MustCompile(pattern)
+
+	return func(value string) error {
+		if !re.MatchString(value) {
+			return fmt.Errorf("%q is not %s", value, expected)
+		}
+		return nil
+	}
+}
+
+// Range accepts the integers from min to max
+func Range(min, max int64) Validator {
+	return func(value string) error {
+		n, err := strconv.ParseInt(value, 0, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+
+		switch {
+		case n < min && max == math.MaxInt64:
+			return fmt.Errorf("%d is less than %d", n, min)
+		case n < min || n > max:
+			return fmt.Errorf("%d is not between %d and %d", n, min, max)
+		}
+		return nil
+	}
+}
+
+// AtLeast accepts the integers from min
+func AtLeast(min int64) Validator {
+	return Range(min, math.MaxInt64)
+}
+
+// Hostname accepts RFC 1123 hostnames: dot-separated labels of up to 63 letters, digits and
+// hyphens, not starting or ending with a hyphen, 253 characters in total
+func Hostname() Validator {
+	return func(value string) error {
+		if len(value) > 253 {
+			return fmt.Errorf("%q is not a valid hostname: longer than 253 characters", value)
+		}
+
+		for _, label := range strings.Split(value, ".") {
+			switch {
+			case label == "":
+				return fmt.Errorf("%q is not a valid hostname: empty label", value)
+			case len(label) > 63:
+				return fmt.Errorf("%q is not a valid hostname: label %q is longer than 63 characters", value, label)
+			case strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-"):
+				return fmt.Errorf("%q is not a valid hostname: label %q starts or ends with a hyphen", value, label)
+			}
+
+			for _, r := range label {
+				if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+					return fmt.Errorf("%q is not a valid hostname: %q is not a letter, digit or hyphen", value, r)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// CIDR accepts IPv4 and IPv6 network blocks such as 10.0.0.0/24, without host bits. No flag takes
+// a network block yet, as the API assigns the subnets of virtual networks and IP addresses itself
+func CIDR() Validator {
+	return func(value string) error {
+		ip, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a CIDR block such as 10.0.0.0/24", value)
+		}
+
+		if !ip.Equal(network.IP) {
+			return fmt.Errorf("%q has host bits set, did you mean %q?", value, network.String())
+		}
+		return nil
+	}
+}
+
+// sshKeyTypes are the public key types accepted by SSHPublicKey
+var sshKeyTypes = []string{
+	"ssh-ed25519",
+	"ssh-rsa",
+	"ecdsa-sha2-nistp256",
+	"ecdsa-sha2-nistp384",
+	"ecdsa-sha2-nistp521",
+	"sk-ssh-ed25519@openssh.com",
+	"sk-ecdsa-sha2-nistp256@openssh.com",
+}
+
+// SSHPublicKey accepts public keys in the authorized_keys format, "<type> <base64 key> [comment]",
+// whose encoded key is of the announced type
+func SSHPublicKey() Validator {
+	return func(value string) error {
+		if strings.Contains(value, "PRIVATE KEY") {
+			return fmt.Errorf("this is a private key, give the public key instead, such as the content of ~/.ssh/id_ed25519.pub")
+		}
+
+		fields := strings.Fields(value)
+		if len(fields) < 2 {
+			return fmt.Errorf("not an SSH public key, expected \"<type> <key> [comment]\" such as the content of ~/.ssh/id_ed25519.pub")
+		}
+
+		keyType := fields[0]
+		if err := OneOf(sshKeyTypes...)(keyType); err != nil {
+			return fmt.Errorf("unsupported SSH key type: %w", err)
+		}
+
+		blob, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return fmt.Errorf("the %s key is not valid base64", keyType)
+		}
+
+		// The key starts with its type, as a length-prefixed string
+		if len(blob) < 4 {
+			return fmt.Errorf("the %s key is truncated", keyType)
+		}
+		size := binary.BigEndian.Uint32(blob)
+		if uint64(len(blob)) < 4+uint64(size) {
+			return fmt.Errorf("the %s key is truncated", keyType)
+		}
+		if encoded := string(blob[4 : 4+size]); encoded != keyType {
+			return fmt.Errorf("the key is announced as %s but encodes a %s key", keyType, encoded)
+		}
+		return nil
+	}
+}
+
+// lookalikes are the characters typed for one another
+var lookalikes = strings.NewReplacer("0", "o", "1", "l")
+
+// closestOption returns the option value was most likely meant to be: the same but for the case
+// and lookalike characters, an option it starts, or the nearest within a few typos
+func closestOption(value string, options []string) (string, bool) {
+	lower := strings.ToLower(value)
+
+	for _, option := range options {
+		if lookalikes.Replace(strings.ToLower(option)) == lookalikes.Replace(lower) {
+			return option, true
+		}
+	}
+
+	if len(value) >= 3 {
+		for _, option := range options {
+			if strings.HasPrefix(strings.ToLower(option), lower) {
+				return option, true
+			}
+		}
+	}
+
+	best, bestDistance := "", math.MaxInt
+	for _, option := range options {
+		if d := editDistance(lower, strings.ToLower(option)); d < bestDistance {
+			best, bestDistance = option, d
+		}
+	}
+	return best, best != "" && bestDistance <= max(2, len(value)/3)
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current := make([]int, len(rb)+1)
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(rb)]
+}
+
+// validateValue runs validators on value, reporting the first failure
+func validateValue(value string, validators []Validator) error {
+	for _, validate := range validators {
+		if err := validate(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
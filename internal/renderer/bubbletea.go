@@ -1,8 +1,10 @@
 package renderer
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/latitudesh/lsh/internal/tui"
@@ -17,54 +19,45 @@ func (btr BubbleTeaRenderer) Render(data []ResponseData) {
 		return
 	}
 
-	// Check if this is server data to use specialized table
-	if isServerData(data) {
-		renderServersWithDetails(data)
-		return
-	}
-
 	// Convert ResponseData to Bubble Tea format
 	columns, rows := convertToTableFormat(data)
 
+	// Every row keeps its resource, so enter shows all of its fields
+	resources := make([]interface{}, len(data))
+	for i, item := range data {
+		resources[i] = item
+	}
+
 	// Render interactive table using Bubble Tea
-	err := tui.RunInteractiveTable("Results", columns, rows)
+	err := tui.RunResourceTable(resourceTitle(data), columns, rows, resources)
 	if err != nil {
 		fmt.Printf("Error rendering table: %v\n", err)
 	}
 }
 
-// isServerData checks if the data is server data
-func isServerData(data []ResponseData) bool {
-	if len(data) == 0 {
-		return false
+// resourceTitle names the table after the JSON type of the resources, such as "Virtual Networks"
+func resourceTitle(data []ResponseData) string {
+	encoded, err := json.Marshal(data[0])
+	if err != nil {
+		return "Results"
 	}
 
-	firstRow := data[0].TableRow()
-	// Check for server-specific fields
-	_, hasHostname := firstRow["hostname"]
-	_, hasIPMI := firstRow["ipmi_status"]
-	return hasHostname && hasIPMI
-}
-
-// renderServersWithDetails renders servers with details support
-func renderServersWithDetails(data []ResponseData) {
-	columns, rows := convertToTableFormat(data)
-
-	// Build original servers data for details view
-	var originalServers []map[string]string
-	for _, item := range data {
-		row := item.TableRow()
-		serverData := make(map[string]string)
-		for _, cell := range row {
-			serverData[cell.Label] = fmt.Sprintf("%v", cell.Value)
-		}
-		originalServers = append(originalServers, serverData)
+	var resource struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(encoded, &resource); err != nil || resource.Type == "" {
+		return "Results"
 	}
 
-	err := tui.RunServersTable("Servers", columns, rows, originalServers)
-	if err != nil {
-		fmt.Printf("Error rendering table: %v\n", err)
+	words := strings.Fields(strings.NewReplacer("_", " ", "-", " ").Replace(resource.Type))
+	for i, word := range words {
+		if word == "ssh" || word == "api" {
+			words[i] = strings.ToUpper(word)
+		} else {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
 	}
+	return strings.Join(words, " ")
 }
 
 // preferredColumnOrder defines the preferred order of columns
@@ -0,0 +1,194 @@
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/latitudesh/lsh/internal/tui"
+)
+
+// Watch polls fetch every interval until interrupted. In a terminal the results are an interactive
+// table refreshed in place, highlighting the rows whose status changed since the previous poll.
+// Otherwise, or with JSON output, only the new and changed records are written as JSON lines, and
+// {"id":...,"deleted":true} for those gone since the previous poll
+func Watch(title string, interval time.Duration, fetch func() ([]ResponseData, error)) error {
+	if _, interactive := GetRenderer().(BubbleTeaRenderer); interactive {
+		return watchTable(title, interval, fetch)
+	}
+
+	watchJSONLines(os.Stdout, os.Stderr, interval, fetch)
+	return nil
+}
+
+// watchDiff remembers the records of the previous poll
+type watchDiff struct {
+	// records are keyed by ID, or by their JSON when they have none
+	records  map[string]string
+	ids      map[string]bool
+	statuses map[string]string
+}
+
+func newWatchDiff() *watchDiff {
+	return &watchDiff{records: map[string]string{}, ids: map[string]bool{}, statuses: map[string]string{}}
+}
+
+// changed returns the JSON of the records that are new or differ from the previous poll, then a
+// deletion marker for each record with an ID missing from this poll
+func (d *watchDiff) changed(data []ResponseData) ([][]byte, error) {
+	var changed [][]byte
+	records := make(map[string]string, len(data))
+	ids := map[string]bool{}
+
+	for _, item := range data {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+
+		key := recordID(item)
+		if key == "" {
+			key = string(encoded)
+		} else {
+			ids[key] = true
+		}
+		records[key] = string(encoded)
+
+		if previous, ok := d.records[key]; !ok || previous != string(encoded) {
+			changed = append(changed, encoded)
+		}
+	}
+
+	var deleted []string
+	for id := range d.ids {
+		if !ids[id] {
+			deleted = append(deleted, id)
+		}
+	}
+	sort.Strings(deleted)
+
+	for _, id := range deleted {
+		encoded, err := json.Marshal(struct {
+			ID      string `json:"id"`
+			Deleted bool   `json:"deleted"`
+		}{id, true})
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, encoded)
+	}
+
+	d.records, d.ids = records, ids
+	return changed, nil
+}
+
+// statusChanges returns the previous status of the records whose status changed, by ID
+func (d *watchDiff) statusChanges(data []ResponseData) map[string]string {
+	changes := map[string]string{}
+	statuses := make(map[string]string, len(data))
+
+	for _, item := range data {
+		row := item.TableRow()
+		status, ok := row["status"]
+		id := recordID(item)
+		if !ok || id == "" {
+			continue
+		}
+
+		statuses[id] = status.Value
+		if previous, ok := d.statuses[id]; ok && previous != status.Value {
+			changes[id] = previous
+		}
+	}
+
+	d.statuses = statuses
+	return changes
+}
+
+func recordID(item ResponseData) string {
+	return item.TableRow()["id"].Value
+}
+
+// watchJSONLines writes the changed records of every poll to out. Failed polls are reported to
+// errOut and retried at the next interval, so a transient API error does not end the watch
+func watchJSONLines(out, errOut io.Writer, interval time.Duration, fetch func() ([]ResponseData, error)) {
+	diff := newWatchDiff()
+
+	for {
+		if err := writeChangedRecords(out, diff, fetch); err != nil {
+			fmt.Fprintf(errOut, "✗ Error: %v\n", err)
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+func writeChangedRecords(out io.Writer, diff *watchDiff, fetch func() ([]ResponseData, error)) error {
+	data, err := fetch()
+	if err != nil {
+		return err
+	}
+
+	changed, err := diff.changed(data)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range changed {
+		if _, err := fmt.Fprintf(out, "%s\n", record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func watchTable(title string, interval time.Duration, fetch func() ([]ResponseData, error)) error {
+	diff := newWatchDiff()
+
+	return tui.RunWatchTable(title, interval, func() (tui.WatchSnapshot, error) {
+		data, err := fetch()
+		if err != nil {
+			return tui.WatchSnapshot{}, err
+		}
+
+		return watchSnapshot(data, diff.statusChanges(data)), nil
+	})
+}
+
+// watchSnapshot builds the table of a poll, marking the status cells that changed with the
+// previous status
+func watchSnapshot(data []ResponseData, changes map[string]string) tui.WatchSnapshot {
+	columns, rows := convertToTableFormat(data)
+	if len(data) == 0 || len(changes) == 0 {
+		return tui.WatchSnapshot{Columns: columns, Rows: rows}
+	}
+
+	statusColumn := -1
+	statusLabel := data[0].TableRow()["status"].Label
+	for i, column := range columns {
+		if column.Title == statusLabel {
+			statusColumn = i
+		}
+	}
+	if statusColumn < 0 {
+		return tui.WatchSnapshot{Columns: columns, Rows: rows}
+	}
+
+	changed := 0
+	for i, item := range data {
+		previous, ok := changes[recordID(item)]
+		if !ok {
+			continue
+		}
+
+		changed++
+		value := fmt.Sprintf("● %s → %s", previous, rows[i][statusColumn])
+		rows[i][statusColumn] = value
+		columns[statusColumn].Width = max(columns[statusColumn].Width, len([]rune(value))+2)
+	}
+
+	return tui.WatchSnapshot{Columns: columns, Rows: rows, Changed: changed}
+}
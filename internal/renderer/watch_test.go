@@ -0,0 +1,105 @@
+package renderer
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	outputTable "github.com/latitudesh/lsh/internal/output/table"
+)
+
+type testRecord struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func (r testRecord) TableRow() outputTable.Row {
+	return outputTable.Row{
+		"id":     outputTable.Cell{Label: "ID", Value: r.ID},
+		"status": outputTable.Cell{Label: "Status", Value: r.Status},
+	}
+}
+
+func records(statuses ...string) []ResponseData {
+	var data []ResponseData
+	for i := 0; i+1 < len(statuses); i += 2 {
+		data = append(data, testRecord{ID: statuses[i], Status: statuses[i+1]})
+	}
+	return data
+}
+
+func TestWriteChangedRecords(t *testing.T) {
+	diff := newWatchDiff()
+	polls := [][]ResponseData{
+		records("sv_1", "deploying", "sv_2", "on"),
+		records("sv_1", "deploying", "sv_2", "on"),
+		records("sv_1", "on", "sv_2", "on", "sv_3", "deploying"),
+		records("sv_3", "on"),
+		records("sv_3", "on"),
+	}
+	want := []string{
+		`{"id":"sv_1","status":"deploying"}` + "\n" + `{"id":"sv_2","status":"on"}` + "\n",
+		"",
+		`{"id":"sv_1","status":"on"}` + "\n" + `{"id":"sv_3","status":"deploying"}` + "\n",
+		`{"id":"sv_3","status":"on"}` + "\n" + `{"id":"sv_1","deleted":true}` + "\n" + `{"id":"sv_2","deleted":true}` + "\n",
+		"",
+	}
+
+	for i, poll := range polls {
+		var out bytes.Buffer
+		err := writeChangedRecords(&out, diff, func() ([]ResponseData, error) { return poll, nil })
+		if err != nil {
+			t.Fatalf("poll %d: %v", i, err)
+		}
+		if out.String() != want[i] {
+			t.Errorf("poll %d wrote %q, want %q", i, out.String(), want[i])
+		}
+	}
+}
+
+func TestWriteChangedRecords_FailedPollKeepsState(t *testing.T) {
+	diff := newWatchDiff()
+	var out bytes.Buffer
+
+	_ = writeChangedRecords(&out, diff, func() ([]ResponseData, error) { return records("sv_1", "on"), nil })
+	err := writeChangedRecords(&out, diff, func() ([]ResponseData, error) { return nil, errors.New("timeout") })
+	if err == nil {
+		t.Fatal("expected the fetch error")
+	}
+
+	out.Reset()
+	_ = writeChangedRecords(&out, diff, func() ([]ResponseData, error) { return records("sv_1", "on"), nil })
+	if out.Len() != 0 {
+		t.Errorf("unchanged record written again after a failed poll: %q", out.String())
+	}
+}
+
+func TestWatchSnapshot_MarksStatusChanges(t *testing.T) {
+	diff := newWatchDiff()
+
+	first := records("sv_1", "deploying", "sv_2", "on")
+	if snapshot := watchSnapshot(first, diff.statusChanges(first)); snapshot.Changed != 0 {
+		t.Errorf("first poll changed = %d, want 0", snapshot.Changed)
+	}
+
+	second := records("sv_1", "on", "sv_2", "on")
+	snapshot := watchSnapshot(second, diff.statusChanges(second))
+	if snapshot.Changed != 1 {
+		t.Fatalf("changed = %d, want 1", snapshot.Changed)
+	}
+
+	// Columns are id then status, the preferred order puts the ID first
+	if got := snapshot.Rows[0][1]; got != "● deploying → on" {
+		t.Errorf("changed status cell = %q", got)
+	}
+	if got := snapshot.Rows[1][1]; got != "on" {
+		t.Errorf("unchanged status cell = %q", got)
+	}
+	if width := snapshot.Columns[1].Width; width < len([]rune("● deploying → on")) {
+		t.Errorf("status column width %d truncates the change", width)
+	}
+	if strings.Contains(snapshot.Rows[0][0], "●") {
+		t.Errorf("ID cell marked: %q", snapshot.Rows[0][0])
+	}
+}
@@ -0,0 +1,452 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DashboardColumn is a column of a dashboard pane
+type DashboardColumn struct {
+	Title string
+	Width int
+}
+
+// DashboardRow is a record shown in a dashboard pane. Data keeps the record for the actions
+type DashboardRow struct {
+	ID    string
+	Cells []string
+	Data  interface{}
+}
+
+// DashboardAction is triggered on the selected row with a key
+type DashboardAction struct {
+	Key  string
+	Name string
+	// Confirm returns the question guarding a destructive action. Leave it nil for safe actions
+	Confirm func(row DashboardRow) string
+	// Prompt asks for a value passed to Run, such as a tag name. Leave it empty for no input
+	Prompt string
+	// Run performs the action and returns the status shown to the user
+	Run func(row DashboardRow, input string) (string, error)
+}
+
+// DashboardPane is a resource listed by the dashboard, such as servers or projects
+type DashboardPane struct {
+	Title   string
+	Columns []DashboardColumn
+	Load    func() ([]DashboardRow, error)
+	Actions []DashboardAction
+}
+
+type dashboardPaneState struct {
+	// table holds the cells of rows, in the same order, and keeps the filter and sort of the pane
+	table    searchableTable
+	rows     []DashboardRow
+	loading  bool
+	err      error
+	loadedAt time.Time
+}
+
+type dashboardLoadedMsg struct {
+	pane int
+	rows []DashboardRow
+	err  error
+}
+
+type dashboardRefreshMsg struct{}
+
+type dashboardActionDoneMsg struct {
+	pane   int
+	status string
+	err    error
+}
+
+// DashboardModel is a multi-pane view of the account, refreshed periodically
+type DashboardModel struct {
+	panes   []DashboardPane
+	states  []dashboardPaneState
+	active  int
+	refresh time.Duration
+	height  int
+
+	// An action waiting for its confirmation or input
+	pending      *DashboardAction
+	pendingRow   DashboardRow
+	pendingInput string
+	confirm      *ConfirmModel
+	input        *TextInputModel
+
+	status      string
+	statusError bool
+	quitting    bool
+}
+
+// NewDashboard creates a dashboard with a pane per resource. Panes are reloaded every refresh
+func NewDashboard(panes []DashboardPane, refresh time.Duration) DashboardModel {
+	states := make([]dashboardPaneState, len(panes))
+	for i, pane := range panes {
+		columns := make([]table.Column, len(pane.Columns))
+		for j, column := range pane.Columns {
+			columns[j] = table.Column{Title: column.Title, Width: column.Width}
+		}
+
+		// The numbers switch panes, so the columns are sorted with s and S
+		t := newSearchableTable(columns, nil, 15)
+		t.cycleSort = true
+
+		states[i] = dashboardPaneState{table: t}
+	}
+
+	return DashboardModel{
+		panes:   panes,
+		states:  states,
+		refresh: refresh,
+	}
+}
+
+func (m DashboardModel) Init() tea.Cmd {
+	if len(m.panes) == 0 {
+		return tea.Quit
+	}
+	m.states[0].loading = true
+	return tea.Batch(m.load(0), m.tick())
+}
+
+func (m DashboardModel) load(pane int) tea.Cmd {
+	loader := m.panes[pane].Load
+	return func() tea.Msg {
+		rows, err := loader()
+		return dashboardLoadedMsg{pane: pane, rows: rows, err: err}
+	}
+}
+
+func (m DashboardModel) tick() tea.Cmd {
+	if m.refresh <= 0 {
+		return nil
+	}
+	return tea.Tick(m.refresh, func(time.Time) tea.Msg { return dashboardRefreshMsg{} })
+}
+
+func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		for i := range m.states {
+			m.states[i].table.table.SetHeight(m.tableHeight())
+		}
+		return m, nil
+
+	case dashboardLoadedMsg:
+		state := &m.states[msg.pane]
+		state.loading = false
+		state.err = msg.err
+		if msg.err == nil {
+			state.rows = msg.rows
+			state.loadedAt = time.Now()
+			state.table.setData(state.table.columns, dashboardTableRows(msg.rows))
+		}
+		return m, nil
+
+	case copyMsg:
+		return m, m.states[m.active].table.update(msg)
+
+	case dashboardRefreshMsg:
+		// Only the pane on screen is reloaded, the others are reloaded when shown again
+		return m, tea.Batch(m.reload(m.active), m.tick())
+
+	case dashboardActionDoneMsg:
+		if msg.err != nil {
+			m.setStatus(msg.err.Error(), true)
+		} else {
+			m.setStatus(msg.status, false)
+		}
+		return m, m.reload(msg.pane)
+
+	case tea.KeyMsg:
+		switch {
+		case m.confirm != nil:
+			return m.updateConfirm(msg)
+		case m.input != nil:
+			return m.updateInput(msg)
+		case m.states[m.active].table.filtering:
+			return m.updateFilter(msg)
+		}
+		return m.updateKey(msg)
+	}
+
+	// Keep the cursor of an open text input blinking
+	if m.input != nil {
+		updated, cmd := m.input.Update(msg)
+		input := updated.(TextInputModel)
+		m.input = &input
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m DashboardModel) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	switch key {
+	case "q", "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	case "tab", "right":
+		return m.switchPane((m.active + 1) % len(m.panes))
+	case "shift+tab", "left":
+		return m.switchPane((m.active + len(m.panes) - 1) % len(m.panes))
+	case "r":
+		return m, m.reload(m.active)
+	}
+
+	if n := paneNumber(key); n > 0 && n <= len(m.panes) {
+		return m.switchPane(n - 1)
+	}
+
+	for i := range m.panes[m.active].Actions {
+		action := m.panes[m.active].Actions[i]
+		if action.Key == key {
+			return m.startAction(&action)
+		}
+	}
+
+	table := &m.states[m.active].table
+	if handled, cmd := table.handleKey(msg); handled {
+		return m, cmd
+	}
+	return m, table.update(msg)
+}
+
+func (m DashboardModel) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if handled, cmd := m.states[m.active].table.handleKey(msg); handled {
+		return m, cmd
+	}
+
+	// Only ctrl+c is left by the filter input
+	m.quitting = true
+	return m, tea.Quit
+}
+
+func (m DashboardModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The confirm quits its own program once answered, so only its answer is kept here
+	updated, _ := m.confirm.Update(msg)
+	confirm := updated.(ConfirmModel)
+	if !confirm.answered {
+		m.confirm = &confirm
+		return m, nil
+	}
+
+	m.confirm = nil
+	if !confirm.Result() {
+		m.setStatus(fmt.Sprintf("Cancelled %s.", m.pending.Name), false)
+		m.pending = nil
+		return m, nil
+	}
+
+	return m.runPending()
+}
+
+func (m DashboardModel) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyEsc || msg.Type == tea.KeyCtrlC {
+		m.input = nil
+		m.setStatus(fmt.Sprintf("Cancelled %s.", m.pending.Name), false)
+		m.pending = nil
+		return m, nil
+	}
+
+	updated, cmd := m.input.Update(msg)
+	input := updated.(TextInputModel)
+	if !input.Submitted() {
+		m.input = &input
+		return m, cmd
+	}
+
+	m.input = nil
+	m.pendingInput = strings.TrimSpace(input.Value())
+	if m.pendingInput == "" {
+		m.setStatus(fmt.Sprintf("Cancelled %s: no value given.", m.pending.Name), true)
+		m.pending = nil
+		return m, nil
+	}
+
+	return m.askConfirmation()
+}
+
+func (m DashboardModel) switchPane(pane int) (tea.Model, tea.Cmd) {
+	m.active = pane
+
+	state := m.states[pane]
+	if state.loading || (!state.loadedAt.IsZero() && time.Since(state.loadedAt) < m.refresh) {
+		return m, nil
+	}
+	return m, m.reload(pane)
+}
+
+func (m *DashboardModel) reload(pane int) tea.Cmd {
+	if m.states[pane].loading {
+		return nil
+	}
+	m.states[pane].loading = true
+	return m.load(pane)
+}
+
+func (m DashboardModel) startAction(action *DashboardAction) (tea.Model, tea.Cmd) {
+	row, ok := m.selectedRow()
+	if !ok {
+		m.setStatus(fmt.Sprintf("Select a record to %s.", action.Name), true)
+		return m, nil
+	}
+
+	m.pending = action
+	m.pendingRow = row
+	m.pendingInput = ""
+
+	if action.Prompt != "" {
+		input := NewTextInput(action.Prompt, "")
+		m.input = &input
+		return m, input.Init()
+	}
+
+	return m.askConfirmation()
+}
+
+func (m DashboardModel) askConfirmation() (tea.Model, tea.Cmd) {
+	if m.pending.Confirm == nil {
+		return m.runPending()
+	}
+
+	confirm := NewConfirm(m.pending.Confirm(m.pendingRow))
+	m.confirm = &confirm
+	return m, nil
+}
+
+func (m DashboardModel) runPending() (tea.Model, tea.Cmd) {
+	action, row, input, pane := *m.pending, m.pendingRow, m.pendingInput, m.active
+	m.pending = nil
+	m.setStatus(fmt.Sprintf("Running %s on %s...", action.Name, row.ID), false)
+
+	return m, func() tea.Msg {
+		status, err := action.Run(row, input)
+		return dashboardActionDoneMsg{pane: pane, status: status, err: err}
+	}
+}
+
+func (m *DashboardModel) setStatus(status string, isError bool) {
+	m.status = status
+	m.statusError = isError
+}
+
+func (m DashboardModel) selectedRow() (DashboardRow, bool) {
+	state := m.states[m.active]
+	index := state.table.selectedIndex()
+	if index < 0 || index >= len(state.rows) {
+		return DashboardRow{}, false
+	}
+	return state.rows[index], true
+}
+
+func dashboardTableRows(rows []DashboardRow) []table.Row {
+	tableRows := make([]table.Row, len(rows))
+	for i, row := range rows {
+		tableRows[i] = table.Row(row.Cells)
+	}
+	return tableRows
+}
+
+func paneNumber(key string) int {
+	if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+		return int(key[0] - '0')
+	}
+	return 0
+}
+
+func (m DashboardModel) tableHeight() int {
+	// Title, tabs, count, filter, status and help lines around the table
+	height := m.height - 12
+	if height < 5 {
+		return 5
+	}
+	return height
+}
+
+func (m DashboardModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	tabs := make([]string, len(m.panes))
+	for i, pane := range m.panes {
+		label := fmt.Sprintf(" %d %s ", i+1, pane.Title)
+		if i == m.active {
+			tabs[i] = selectedRowStyle.Render(label)
+		} else {
+			tabs[i] = BlurredStyle.Render(label)
+		}
+	}
+
+	state := m.states[m.active]
+	pane := m.panes[m.active]
+
+	info := state.table.count(strings.ToLower(pane.Title))
+	switch {
+	case state.loading:
+		info += " • loading..."
+	case !state.loadedAt.IsZero():
+		info += " • updated " + state.loadedAt.Format("15:04:05")
+	}
+
+	body := state.table.view()
+	if state.err != nil {
+		body = ErrorStyle.Render(fmt.Sprintf("Failed to load %s: %v", strings.ToLower(pane.Title), state.err)) + "\n" + body
+	}
+
+	switch {
+	case m.confirm != nil:
+		body = BoxStyle.Render(m.confirm.View())
+	case m.input != nil:
+		body = BoxStyle.Render(m.input.View())
+	}
+
+	status := ""
+	if m.status != "" {
+		if m.statusError {
+			status = ErrorStyle.Render(m.status)
+		} else {
+			status = SuccessStyle.Render(m.status)
+		}
+	}
+
+	help := []string{fmt.Sprintf("tab/1-%d: switch", min(len(m.panes), 9)), cycleSortSearchHelp, "r: refresh"}
+	for _, action := range pane.Actions {
+		help = append(help, fmt.Sprintf("%s: %s", action.Key, action.Name))
+	}
+	help = append(help, "q: quit")
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		TitleStyle.Render("Latitude.sh"),
+		strings.Join(tabs, " "),
+		"",
+		footerStyle.Render(info),
+		body,
+		status,
+		HelpStyle.Render(strings.Join(help, " • ")),
+	)
+}
+
+// RunDashboard runs the dashboard in the alternate screen until the user quits
+func RunDashboard(panes []DashboardPane, refresh time.Duration) error {
+	p := tea.NewProgram(
+		NewDashboard(panes, refresh),
+		tea.WithAltScreen(),
+	)
+
+	_, err := p.Run()
+	return err
+}
@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// testDashboard returns a loaded dashboard with a servers pane and a reboot action counting its runs
+func testDashboard(t *testing.T, runErr error) (DashboardModel, *int) {
+	t.Helper()
+
+	runs := 0
+	pane := DashboardPane{
+		Title:   "Servers",
+		Columns: []DashboardColumn{{Title: "Hostname", Width: 10}},
+		Load:    func() ([]DashboardRow, error) { return nil, nil },
+		Actions: []DashboardAction{
+			{
+				Key:     "b",
+				Name:    "reboot",
+				Confirm: func(row DashboardRow) string { return "Reboot " + row.ID + "?" },
+				Run: func(row DashboardRow, _ string) (string, error) {
+					runs++
+					return "Rebooting " + row.ID, runErr
+				},
+			},
+		},
+	}
+
+	m := NewDashboard([]DashboardPane{pane}, 0)
+	updated, _ := m.Update(dashboardLoadedMsg{pane: 0, rows: []DashboardRow{
+		{ID: "sv_1", Cells: []string{"web-01"}},
+		{ID: "sv_2", Cells: []string{"db-01"}},
+	}})
+
+	return updated.(DashboardModel), &runs
+}
+
+func press(t *testing.T, m DashboardModel, keys ...string) (DashboardModel, tea.Cmd) {
+	t.Helper()
+
+	var cmd tea.Cmd
+	for _, key := range keys {
+		var updated tea.Model
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if key == "enter" {
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		updated, cmd = m.Update(msg)
+		m = updated.(DashboardModel)
+	}
+	return m, cmd
+}
+
+func TestDashboard_ConfirmGuardsActions(t *testing.T) {
+	m, runs := testDashboard(t, nil)
+
+	m, _ = press(t, m, "b")
+	if m.confirm == nil {
+		t.Fatal("a destructive action must ask for confirmation")
+	}
+
+	m, cmd := press(t, m, "n")
+	if m.confirm != nil || cmd != nil {
+		t.Fatal("declining must close the confirmation without running the action")
+	}
+
+	m, cmd = press(t, m, "b", "y")
+	if cmd == nil {
+		t.Fatal("confirming must run the action")
+	}
+
+	updated, _ := m.Update(cmd())
+	m = updated.(DashboardModel)
+	if *runs != 1 {
+		t.Errorf("runs = %d, want 1", *runs)
+	}
+	if m.status != "Rebooting sv_1" || m.statusError {
+		t.Errorf("status = %q (error %v), want the action result", m.status, m.statusError)
+	}
+}
+
+func TestDashboard_ActionError(t *testing.T) {
+	m, _ := testDashboard(t, errors.New("server is locked"))
+
+	m, cmd := press(t, m, "b", "y")
+	updated, _ := m.Update(cmd())
+	m = updated.(DashboardModel)
+
+	if m.status != "server is locked" || !m.statusError {
+		t.Errorf("status = %q (error %v), want the action error", m.status, m.statusError)
+	}
+}
+
+func TestDashboard_Filter(t *testing.T) {
+	m, _ := testDashboard(t, nil)
+
+	m, _ = press(t, m, "/", "D", "b", "enter")
+	if m.states[0].table.filtering {
+		t.Fatal("enter must end the filter input")
+	}
+	if got := m.states[0].table.count("servers"); got != "1 of 2 servers" {
+		t.Fatalf("count = %q, want only db-01", got)
+	}
+
+	// The action applies to the selected row of the filtered list
+	m, _ = press(t, m, "b")
+	if m.pendingRow.ID != "sv_2" {
+		t.Errorf("action row = %s, want sv_2", m.pendingRow.ID)
+	}
+}
+
+func TestDashboard_NoRowSelected(t *testing.T) {
+	m, runs := testDashboard(t, nil)
+
+	m, _ = press(t, m, "/", "x", "y", "z", "enter", "b")
+	if m.confirm != nil || *runs != 0 {
+		t.Fatal("an action without a selected row must not run")
+	}
+	if !m.statusError {
+		t.Errorf("status = %q, want an error", m.status)
+	}
+}
+
+func TestDashboard_SortKeepsPaneKeys(t *testing.T) {
+	m, _ := testDashboard(t, nil)
+	m.panes = append(m.panes, DashboardPane{Title: "Projects", Load: func() ([]DashboardRow, error) { return nil, nil }})
+	m.states = append(m.states, dashboardPaneState{table: newSearchableTable(nil, nil, 15)})
+
+	// s sorts by the hostname, so db-01 comes first
+	m, _ = press(t, m, "s")
+	if row, ok := m.selectedRow(); !ok || row.ID != "sv_2" {
+		t.Errorf("selected row = %v, want sv_2 first once sorted", row.ID)
+	}
+
+	// S reverses the order
+	m, _ = press(t, m, "S")
+	if row, ok := m.selectedRow(); !ok || row.ID != "sv_1" {
+		t.Errorf("selected row = %v, want sv_1 first once reversed", row.ID)
+	}
+
+	// The numbers still switch panes
+	m, _ = press(t, m, "2")
+	if m.active != 1 {
+		t.Errorf("active pane = %d, want 1", m.active)
+	}
+}
@@ -0,0 +1,379 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ResourceDetailsModel shows every field of a resource from its JSON. Nested attributes and
+// relationships are indented under their name, and timestamps are shown with their age
+type ResourceDetailsModel struct {
+	title    string
+	lines    []detailLine
+	viewport viewport.Model
+	ready    bool
+	quitting bool
+}
+
+// detailLine is a field of the details, or the heading of a nested object when section is set
+type detailLine struct {
+	depth   int
+	label   string
+	value   string
+	section bool
+}
+
+// leadingFields are shown first, in this order, at every level
+var leadingFields = []string{"id", "type", "name", "hostname", "slug", "status"}
+
+// detailAcronyms are kept upper case in labels
+var detailAcronyms = map[string]bool{
+	"api": true, "cpu": true, "gb": true, "id": true, "ip": true, "ipmi": true, "ips": true,
+	"ipv4": true, "ipv6": true, "nic": true, "os": true, "ram": true, "ssh": true, "url": true,
+	"usd": true, "vid": true, "vlan": true,
+}
+
+// NewResourceDetails creates the details of a resource, any value encoding to a JSON object
+func NewResourceDetails(title string, resource interface{}) ResourceDetailsModel {
+	value, err := resourceValue(resource)
+	if err != nil {
+		return ResourceDetailsModel{
+			title: title,
+			lines: []detailLine{{label: "Error", value: err.Error()}},
+		}
+	}
+
+	return ResourceDetailsModel{
+		title: title,
+		lines: detailLines(value, 0, time.Now()),
+	}
+}
+
+func (m ResourceDetailsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m ResourceDetailsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Title, box border and help
+		height := max(msg.Height-8, 5)
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width-6, height)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width - 6
+			m.viewport.Height = height
+		}
+		m.viewport.SetContent(renderDetailLines(m.lines))
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c", "backspace", "enter":
+			m.quitting = true
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m ResourceDetailsModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(PrimaryColor).
+		MarginBottom(1).
+		Padding(0, 1)
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(PrimaryColor).
+		Padding(0, 2)
+
+	content := renderDetailLines(m.lines)
+	help := "esc/backspace: back • q: quit"
+	if m.ready {
+		content = m.viewport.View()
+		if !m.viewport.AtTop() || !m.viewport.AtBottom() {
+			help = fmt.Sprintf("↑/↓: scroll (%d%%) • ", int(m.viewport.ScrollPercent()*100)) + help
+		}
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render(m.title),
+		boxStyle.Render(content),
+		HelpStyle.Render(help),
+	)
+}
+
+// resourceValue decodes the JSON of resource into maps, lists and scalars. Numbers are kept as
+// json.Number, so IDs and sizes are shown as the API sent them
+func resourceValue(resource interface{}) (interface{}, error) {
+	encoded, ok := resource.(json.RawMessage)
+	if !ok {
+		var err error
+		if encoded, err = json.Marshal(resource); err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// detailLines lists the fields of value. Empty fields are left out
+func detailLines(value interface{}, depth int, now time.Time) []detailLine {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		var lines []detailLine
+		for _, key := range orderedKeys(value) {
+			lines = append(lines, fieldLines(detailLabel(key), value[key], depth, now)...)
+		}
+		return lines
+
+	case []interface{}:
+		var lines []detailLine
+		for i, item := range value {
+			lines = append(lines, fieldLines(fmt.Sprintf("#%d", i+1), item, depth, now)...)
+		}
+		return lines
+	}
+
+	if text := scalarText(value, now); text != "" {
+		return []detailLine{{depth: depth, value: text}}
+	}
+	return nil
+}
+
+func fieldLines(label string, value interface{}, depth int, now time.Time) []detailLine {
+	if isScalarList(value) {
+		var items []string
+		for _, item := range value.([]interface{}) {
+			if text := scalarText(item, now); text != "" {
+				items = append(items, text)
+			}
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return []detailLine{{depth: depth, label: label, value: strings.Join(items, ", ")}}
+	}
+
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		children := detailLines(value, depth+1, now)
+		if len(children) == 0 {
+			return nil
+		}
+		return append([]detailLine{{depth: depth, label: label, section: true}}, children...)
+	}
+
+	text := scalarText(value, now)
+	if text == "" {
+		return nil
+	}
+	return []detailLine{{depth: depth, label: label, value: text}}
+}
+
+// orderedKeys puts the leading fields first, then the other plain fields and then the nested
+// objects, attributes before the others
+func orderedKeys(object map[string]interface{}) []string {
+	rank := func(key string) int {
+		for i, leading := range leadingFields {
+			if key == leading {
+				return i
+			}
+		}
+		switch {
+		case isScalar(object[key]) || isScalarList(object[key]):
+			return len(leadingFields)
+		case key == "attributes":
+			return len(leadingFields) + 1
+		}
+		return len(leadingFields) + 2
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if ri, rj := rank(keys[i]), rank(keys[j]); ri != rj {
+			return ri < rj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	}
+	return true
+}
+
+func isScalarList(value interface{}) bool {
+	list, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, item := range list {
+		if !isScalar(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// scalarText formats a JSON scalar, adding the age to timestamps
+func scalarText(value interface{}, now time.Time) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return fmt.Sprintf("%s (%s)", t.Local().Format("2006-01-02 15:04:05 MST"), age(now.Sub(t)))
+		}
+		return value
+	case bool:
+		if value {
+			return "yes"
+		}
+		return "no"
+	}
+	return fmt.Sprint(value)
+}
+
+// age describes a duration since a timestamp, such as "3 days ago"
+func age(d time.Duration) string {
+	suffix := "ago"
+	if d < 0 {
+		d, suffix = -d, "from now"
+	}
+
+	var amount int
+	var unit string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		amount, unit = int(d/time.Minute), "minute"
+	case d < 24*time.Hour:
+		amount, unit = int(d/time.Hour), "hour"
+	case d < 30*24*time.Hour:
+		amount, unit = int(d/(24*time.Hour)), "day"
+	case d < 365*24*time.Hour:
+		amount, unit = int(d/(30*24*time.Hour)), "month"
+	default:
+		amount, unit = int(d/(365*24*time.Hour)), "year"
+	}
+
+	if amount != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s %s", amount, unit, suffix)
+}
+
+// detailLabel turns a JSON key such as "primary_ipv4" into "Primary IPV4"
+func detailLabel(key string) string {
+	words := strings.Fields(strings.NewReplacer("_", " ", "-", " ").Replace(key))
+	for i, word := range words {
+		switch {
+		case detailAcronyms[strings.ToLower(word)]:
+			words[i] = strings.ToUpper(word)
+		case i == 0:
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func renderDetailLines(lines []detailLine) string {
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(SecondaryColor)
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(PrimaryColor)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	// Labels are aligned across the fields of the same level
+	width := map[int]int{}
+	for _, line := range lines {
+		if !line.section {
+			width[line.depth] = max(width[line.depth], lipgloss.Width(line.label))
+		}
+	}
+
+	rendered := make([]string, 0, len(lines))
+	for _, line := range lines {
+		indent := strings.Repeat("  ", line.depth)
+		switch {
+		case line.section:
+			rendered = append(rendered, indent+sectionStyle.Render(line.label))
+		case line.label == "":
+			rendered = append(rendered, indent+valueStyle.Render(line.value))
+		default:
+			label := labelStyle.Width(width[line.depth] + 1).Render(line.label + ":")
+			rendered = append(rendered, indent+label+" "+valueStyle.Render(line.value))
+		}
+	}
+	return strings.Join(rendered, "\n")
+}
+
+// RunResourceDetails shows the details of a resource
+func RunResourceDetails(title string, resource interface{}) error {
+	p := tea.NewProgram(
+		NewResourceDetails(title, resource),
+		tea.WithAltScreen(),
+	)
+
+	_, err := p.Run()
+	return err
+}
+
+// resourceTitle names a resource for its details, such as "Servers: web-01"
+func resourceTitle(kind string, resource interface{}) string {
+	value, err := resourceValue(resource)
+	object, ok := value.(map[string]interface{})
+	if err != nil || !ok {
+		return kind
+	}
+
+	attributes, _ := object["attributes"].(map[string]interface{})
+	for _, source := range []map[string]interface{}{attributes, object} {
+		for _, key := range []string{"hostname", "name", "slug", "plan_slug", "id"} {
+			if text, ok := source[key].(string); ok && text != "" {
+				return fmt.Sprintf("%s: %s", kind, text)
+			}
+		}
+	}
+	return kind
+}
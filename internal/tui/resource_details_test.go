@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+const testServer = `{
+	"id": "sv_1",
+	"type": "servers",
+	"attributes": {
+		"status": "on",
+		"hostname": "web-01",
+		"primary_ipv4": "203.0.113.10",
+		"created_at": "2024-03-01T12:00:00Z",
+		"locked": false,
+		"rescue": null,
+		"specs": {"cpu": "Intel E-2186G", "ram": "32 GB"},
+		"tags": [{"id": "tag_web", "name": "web"}],
+		"ips": ["203.0.113.10", "2001:db8::10"],
+		"notes": ""
+	}
+}`
+
+func TestDetailLines(t *testing.T) {
+	value, err := resourceValue(json.RawMessage(testServer))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC).Local().Format("2006-01-02 15:04:05 MST")
+
+	want := []detailLine{
+		{depth: 0, label: "ID", value: "sv_1"},
+		{depth: 0, label: "Type", value: "servers"},
+		{depth: 0, label: "Attributes", section: true},
+		{depth: 1, label: "Hostname", value: "web-01"},
+		{depth: 1, label: "Status", value: "on"},
+		{depth: 1, label: "Created at", value: created + " (3 days ago)"},
+		{depth: 1, label: "IPS", value: "203.0.113.10, 2001:db8::10"},
+		{depth: 1, label: "Locked", value: "no"},
+		{depth: 1, label: "Primary IPV4", value: "203.0.113.10"},
+		{depth: 1, label: "Specs", section: true},
+		{depth: 2, label: "CPU", value: "Intel E-2186G"},
+		{depth: 2, label: "RAM", value: "32 GB"},
+		{depth: 1, label: "Tags", section: true},
+		{depth: 2, label: "#1", section: true},
+		{depth: 3, label: "ID", value: "tag_web"},
+		{depth: 3, label: "Name", value: "web"},
+	}
+
+	got := detailLines(value, 0, now)
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestAge(t *testing.T) {
+	tests := map[time.Duration]string{
+		30 * time.Second:     "just now",
+		time.Minute:          "1 minute ago",
+		5 * time.Hour:        "5 hours ago",
+		-2 * 24 * time.Hour:  "2 days from now",
+		400 * 24 * time.Hour: "1 year ago",
+	}
+
+	for d, want := range tests {
+		if got := age(d); got != want {
+			t.Errorf("age(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestResourceTitle(t *testing.T) {
+	if got := resourceTitle("Servers", json.RawMessage(testServer)); got != "Servers: web-01" {
+		t.Errorf("resourceTitle() = %q", got)
+	}
+
+	plan := struct {
+		Slug string `json:"plan_slug"`
+	}{Slug: "c2-small-x86"}
+	if got := resourceTitle("Plans", plan); got != "Plans: c2-small-x86" {
+		t.Errorf("resourceTitle() = %q", got)
+	}
+}
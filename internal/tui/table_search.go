@@ -0,0 +1,353 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// searchableTable adds fuzzy filtering, column sorting and copying the selected ID to a table. The
+// interactive tables embed it, so every resource gets the same keys
+type searchableTable struct {
+	table   table.Model
+	columns []table.Column
+	rows    []table.Row
+	// visible holds the indexes in rows of the displayed rows, in display order
+	visible []int
+
+	filter    string
+	filtering bool
+
+	// sortColumn is the column sorted by, -1 for the original order
+	sortColumn int
+	sortDesc   bool
+	// cycleSort sorts with s, going through the columns, and S, reversing the order, rather than
+	// with the column numbers, for the views already using the numbers such as the dashboard
+	cycleSort bool
+
+	status string
+}
+
+// copyMsg reports the ID copied to the clipboard
+type copyMsg struct {
+	id string
+}
+
+func newSearchableTable(columns []table.Column, rows []table.Row, height int) searchableTable {
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithHeight(height),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(PrimaryColor).
+		BorderBottom(true).
+		Bold(true).
+		Foreground(PrimaryColor)
+	s.Selected = selectedRowStyle
+	s.Cell = s.Cell.Padding(0, 1)
+	t.SetStyles(s)
+
+	st := searchableTable{
+		table:      t,
+		columns:    columns,
+		rows:       rows,
+		sortColumn: -1,
+	}
+	st.refresh()
+
+	return st
+}
+
+// handleKey handles the search, sort and copy keys. It returns false for the keys left to the
+// embedding model, such as enter and q
+func (t *searchableTable) handleKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if t.filtering {
+		switch msg.Type {
+		case tea.KeyEnter:
+			t.filtering = false
+		case tea.KeyEsc:
+			t.filtering = false
+			t.filter = ""
+		case tea.KeyCtrlC:
+			return false, nil
+		case tea.KeyBackspace:
+			if t.filter != "" {
+				runes := []rune(t.filter)
+				t.filter = string(runes[:len(runes)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			t.filter += string(msg.Runes)
+		case tea.KeyUp, tea.KeyDown:
+			var cmd tea.Cmd
+			t.table, cmd = t.table.Update(msg)
+			return true, cmd
+		}
+		t.refresh()
+		return true, nil
+	}
+
+	key := msg.String()
+	switch {
+	case key == "/":
+		t.filtering = true
+		t.status = ""
+		return true, nil
+	case key == "esc" && t.filter != "":
+		t.filter = ""
+		t.refresh()
+		return true, nil
+	case key == "y":
+		id, ok := t.selectedID()
+		if !ok {
+			return true, nil
+		}
+		return true, copyToClipboard(id)
+	case t.cycleSort && key == "s":
+		t.toggleSort((t.sortColumn+2)%(len(t.columns)+1) - 1)
+		return true, nil
+	case t.cycleSort && key == "S":
+		if t.sortColumn >= 0 {
+			t.toggleSort(t.sortColumn)
+		}
+		return true, nil
+	case !t.cycleSort && len(key) == 1 && key[0] >= '0' && key[0] <= '9':
+		t.toggleSort(int(key[0]-'0') - 1)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// update passes the remaining messages, such as navigation keys, to the table
+func (t *searchableTable) update(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(copyMsg); ok {
+		t.status = fmt.Sprintf("Copied %s to the clipboard", msg.id)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	t.table, cmd = t.table.Update(msg)
+	return cmd
+}
+
+// toggleSort sorts by a column, reverses the order when it is already sorted by it, and goes back
+// to the original order with 0
+func (t *searchableTable) toggleSort(column int) {
+	switch {
+	case column < 0 || column >= len(t.columns):
+		t.sortColumn, t.sortDesc = -1, false
+	case column == t.sortColumn:
+		t.sortDesc = !t.sortDesc
+	default:
+		t.sortColumn, t.sortDesc = column, false
+	}
+
+	t.refresh()
+}
+
+// setData replaces the columns and rows, keeping the filter, the sort and the cursor
+func (t *searchableTable) setData(columns []table.Column, rows []table.Row) {
+	t.columns = columns
+	t.rows = rows
+	if t.sortColumn >= len(columns) {
+		t.sortColumn, t.sortDesc = -1, false
+	}
+
+	t.refresh()
+}
+
+// refresh recomputes the displayed rows from the filter and the sort
+func (t *searchableTable) refresh() {
+	t.visible = make([]int, 0, len(t.rows))
+	for i, row := range t.rows {
+		if fuzzyMatchRow(t.filter, row) {
+			t.visible = append(t.visible, i)
+		}
+	}
+
+	if t.sortColumn >= 0 {
+		column, desc := t.sortColumn, t.sortDesc
+		sort.SliceStable(t.visible, func(i, j int) bool {
+			a, b := cellAt(t.rows[t.visible[i]], column), cellAt(t.rows[t.visible[j]], column)
+			if desc {
+				return compareCells(b, a) < 0
+			}
+			return compareCells(a, b) < 0
+		})
+	}
+
+	columns := make([]table.Column, len(t.columns))
+	copy(columns, t.columns)
+	if t.sortColumn >= 0 {
+		indicator := " ▲"
+		if t.sortDesc {
+			indicator = " ▼"
+		}
+		columns[t.sortColumn].Title += indicator
+	}
+
+	rows := make([]table.Row, len(t.visible))
+	for i, index := range t.visible {
+		rows[i] = t.rows[index]
+	}
+
+	// Columns and rows must agree at all times, so the rows are cleared before the columns change.
+	// Clearing them also moves the cursor, which is restored afterwards
+	cursor := t.table.Cursor()
+	t.table.SetRows(nil)
+	t.table.SetColumns(columns)
+	t.table.SetRows(rows)
+	t.table.SetCursor(min(max(cursor, 0), max(len(rows)-1, 0)))
+}
+
+// selectedIndex returns the index in the original rows of the selected row, or -1
+func (t searchableTable) selectedIndex() int {
+	cursor := t.table.Cursor()
+	if cursor < 0 || cursor >= len(t.visible) {
+		return -1
+	}
+	return t.visible[cursor]
+}
+
+// selectedID returns the ID column of the selected row, or its first column without an ID column
+func (t searchableTable) selectedID() (string, bool) {
+	index := t.selectedIndex()
+	if index < 0 {
+		return "", false
+	}
+
+	column := 0
+	for i, c := range t.columns {
+		if strings.EqualFold(strings.TrimSpace(c.Title), "id") {
+			column = i
+			break
+		}
+	}
+
+	id := strings.TrimSpace(cellAt(t.rows[index], column))
+	return id, id != ""
+}
+
+// count describes the displayed rows, such as "12 of 240 servers" while filtering
+func (t searchableTable) count(noun string) string {
+	if len(t.visible) == len(t.rows) {
+		return fmt.Sprintf("%d %s", len(t.rows), noun)
+	}
+	return fmt.Sprintf("%d of %d %s", len(t.visible), len(t.rows), noun)
+}
+
+// filterLine renders the filter being typed, or the last copy, above the help
+func (t searchableTable) filterLine() string {
+	switch {
+	case t.filtering:
+		return FocusedStyle.Render("/") + t.filter + CursorStyle.Render("█")
+	case t.filter != "":
+		return FocusedStyle.Render("/") + t.filter + BlurredStyle.Render("  (esc: clear)")
+	case t.status != "":
+		return SuccessStyle.Render(t.status)
+	}
+	return ""
+}
+
+// view renders the table with the filter line below it
+func (t searchableTable) view() string {
+	tableView := baseTableStyle.Render(t.table.View())
+	if line := t.filterLine(); line != "" {
+		return tableView + "\n" + line
+	}
+	return tableView
+}
+
+// searchHelp lists the search, sort and copy keys
+const searchHelp = "/: filter • 1-9/0: sort • y: copy ID"
+
+// cycleSortSearchHelp lists the search, sort and copy keys of a table with cycleSort
+const cycleSortSearchHelp = "/: filter • s/S: sort • y: copy ID"
+
+// fuzzyMatchRow reports whether every word of the filter fuzzy matches a cell of the row
+func fuzzyMatchRow(filter string, row table.Row) bool {
+	for _, term := range strings.Fields(strings.ToLower(filter)) {
+		matched := false
+		for _, cell := range row {
+			if fuzzyMatch(term, strings.ToLower(cell)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// fuzzyMatch reports whether the runes of term appear in text in order, so "wb1" matches "web-01"
+func fuzzyMatch(term, text string) bool {
+	runes := []rune(term)
+	if len(runes) == 0 {
+		return true
+	}
+
+	i := 0
+	for _, r := range text {
+		if r == runes[i] {
+			i++
+			if i == len(runes) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareCells compares numbers by value, such as sizes and counts, and other values as text
+func compareCells(a, b string) int {
+	na, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	nb, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errA == nil && errB == nil {
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func cellAt(row table.Row, column int) string {
+	if column < len(row) {
+		return row[column]
+	}
+	return ""
+}
+
+// copyToClipboard copies text with the OSC 52 escape sequence, which works over SSH as the
+// terminal, not the host, owns the clipboard
+func copyToClipboard(text string) tea.Cmd {
+	return func() tea.Msg {
+		sequence := osc52.New(text)
+		switch {
+		case os.Getenv("TMUX") != "":
+			sequence = sequence.Tmux()
+		case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+			sequence = sequence.Screen()
+		}
+
+		fmt.Fprint(os.Stderr, sequence)
+		return copyMsg{id: text}
+	}
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testServersTable() TableModel {
+	columns := []table.Column{{Title: "ID", Width: 16}, {Title: "Hostname", Width: 10}, {Title: "Site", Width: 6}, {Title: "Cores", Width: 6}}
+	rows := []table.Row{
+		{"sv_1", "web-01", "SAO2", "16"},
+		{"sv_2", "db-01", "NYC", "4"},
+		{"sv_3", "web-02", "NYC", "32"},
+	}
+	return NewResourceTable("Servers", columns, rows, make([]interface{}, len(rows)))
+}
+
+func typeKeys(m TableModel, keys ...tea.KeyMsg) TableModel {
+	for _, key := range keys {
+		updated, _ := m.Update(key)
+		m = updated.(TableModel)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		term, text string
+		want       bool
+	}{
+		{"", "web-01", true},
+		{"web", "web-01", true},
+		{"wb1", "web-01", true},
+		{"w1b", "web-01", false},
+		{"web-03", "web-01", false},
+	}
+
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.term, tt.text); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.term, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSearchableTable_Filter(t *testing.T) {
+	m := typeKeys(testServersTable(), runes("/"), runes("wb"), runes(" "), runes("nyc"), tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.table.filtering {
+		t.Fatal("enter should confirm the filter")
+	}
+	if got := m.table.count("servers"); got != "1 of 3 servers" {
+		t.Errorf("count = %q, want %q", got, "1 of 3 servers")
+	}
+
+	// Enter selects the filtered row, by its index in the original rows
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.showDetails || m.selected != 2 {
+		t.Errorf("selected %d (details %v), want 2", m.selected, m.showDetails)
+	}
+}
+
+func TestSearchableTable_EscClearsFilterBeforeQuitting(t *testing.T) {
+	m := typeKeys(testServersTable(), runes("/"), runes("db"), tea.KeyMsg{Type: tea.KeyEnter})
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.quitting {
+		t.Fatal("esc should clear the filter first")
+	}
+	if got := m.table.count("servers"); got != "3 servers" {
+		t.Errorf("count = %q, want %q", got, "3 servers")
+	}
+
+	// q is typed into the filter rather than quitting
+	m = typeKeys(m, runes("/"), runes("q"))
+	if m.quitting || m.table.filter != "q" {
+		t.Errorf("quitting = %v, filter = %q", m.quitting, m.table.filter)
+	}
+}
+
+func TestSearchableTable_Sort(t *testing.T) {
+	m := testServersTable()
+
+	// Cores are compared as numbers, so 4 comes before 16
+	m = typeKeys(m, runes("4"))
+	if got := m.table.visible; !slices.Equal(got, []int{1, 0, 2}) {
+		t.Errorf("ascending = %v, want [1 0 2]", got)
+	}
+	if got := m.table.table.Columns()[3].Title; got != "Cores ▲" {
+		t.Errorf("header = %q", got)
+	}
+
+	m = typeKeys(m, runes("4"))
+	if got := m.table.visible; !slices.Equal(got, []int{2, 0, 1}) {
+		t.Errorf("descending = %v, want [2 0 1]", got)
+	}
+
+	m = typeKeys(m, runes("0"))
+	if got := m.table.visible; !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("original = %v, want [0 1 2]", got)
+	}
+	if got := m.table.table.Columns()[3].Title; got != "Cores" {
+		t.Errorf("header = %q", got)
+	}
+}
+
+func TestSearchableTable_SelectedID(t *testing.T) {
+	m := typeKeys(testServersTable(), runes("2"), tea.KeyMsg{Type: tea.KeyDown})
+
+	// Sorted by hostname, the second row is web-01
+	id, ok := m.table.selectedID()
+	if !ok || id != "sv_1" {
+		t.Errorf("selectedID() = %q, %v, want sv_1", id, ok)
+	}
+
+	updated, _ := m.Update(copyMsg{id: id})
+	if status := updated.(TableModel).table.status; status != "Copied sv_1 to the clipboard" {
+		t.Errorf("status = %q", status)
+	}
+}
@@ -31,10 +31,13 @@ var (
 )
 
 type TableModel struct {
-	table        table.Model
-	totalRecords int
-	title        string
-	quitting     bool
+	table    searchableTable
+	title    string
+	quitting bool
+	// resources holds the resource of every row, shown by enter
+	resources   []interface{}
+	selected    int
+	showDetails bool
 }
 
 // NewInteractiveTable is a helper function to create an interactive table
@@ -48,58 +51,49 @@ func NewInteractiveTable(title string, columns []table.Column, rows []table.Row)
 		height = 5
 	}
 
-	t := table.New(
-		table.WithColumns(columns),
-		table.WithRows(rows),
-		table.WithFocused(true),
-		table.WithHeight(height),
-	)
-
-	// Style the table
-	s := table.DefaultStyles()
-	s.Header = s.Header.
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(PrimaryColor).
-		BorderBottom(true).
-		Bold(true).
-		Foreground(PrimaryColor)
-
-	s.Selected = selectedRowStyle
-
-	s.Cell = s.Cell.
-		Padding(0, 1)
-
-	t.SetStyles(s)
-
 	return TableModel{
-		table:        t,
-		totalRecords: len(rows),
-		title:        title,
+		table: newSearchableTable(columns, rows, height),
+		title: title,
 	}
 }
 
+// NewResourceTable creates an interactive table showing the details of the resource of the
+// selected row on enter. resources are in the order of rows
+func NewResourceTable(title string, columns []table.Column, rows []table.Row, resources []interface{}) TableModel {
+	m := NewInteractiveTable(title, columns, rows)
+	m.resources = resources
+	return m
+}
+
 func (m TableModel) Init() tea.Cmd {
 	return nil
 }
 
 func (m TableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if handled, cmd := m.table.handleKey(msg); handled {
+			return m, cmd
+		}
+
 		switch msg.String() {
 		case "q", "esc", "ctrl+c":
 			m.quitting = true
 			return m, tea.Quit
 		case "enter":
-			// You can add action to select a row
-			// For now, quit
+			if m.resources != nil {
+				m.selected = m.table.selectedIndex()
+				if m.selected < 0 || m.selected >= len(m.resources) {
+					return m, nil
+				}
+				m.showDetails = true
+			}
 			m.quitting = true
 			return m, tea.Quit
 		}
 	}
 
-	m.table, cmd = m.table.Update(msg)
+	cmd := m.table.update(msg)
 	return m, cmd
 }
 
@@ -115,14 +109,18 @@ func (m TableModel) View() string {
 	}
 
 	// Tabela
-	tableView := baseTableStyle.Render(m.table.View())
+	tableView := m.table.view()
 
 	// Footer with info and help
 	footer := footerStyle.Render(
-		fmt.Sprintf("Showing %d records", m.totalRecords),
+		fmt.Sprintf("Showing %s", m.table.count("records")),
 	)
 
-	help := HelpStyle.Render("↑/↓: navigate • q/esc: quit • enter: select")
+	enter := "enter: select"
+	if m.resources != nil {
+		enter = "enter: details"
+	}
+	help := HelpStyle.Render("↑/↓: navigate • " + searchHelp + " • " + enter + " • q/esc: quit")
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -134,9 +132,10 @@ func (m TableModel) View() string {
 	)
 }
 
-// SelectedRow returns the index of the selected row
+// SelectedRow returns the index of the selected row in the rows given to the table, or -1 when
+// the filter matches no row
 func (m TableModel) SelectedRow() int {
-	return m.table.Cursor()
+	return m.table.selectedIndex()
 }
 
 // RunInteractiveTable runs an interactive table
@@ -154,3 +153,37 @@ func RunInteractiveTable(title string, columns []table.Column, rows []table.Row)
 	_, err := p.Run()
 	return err
 }
+
+// RunResourceTable runs an interactive table, showing the details of the selected resource on enter
+// and coming back to the table afterwards
+func RunResourceTable(title string, columns []table.Column, rows []table.Row, resources []interface{}) error {
+	if len(rows) == 0 {
+		fmt.Println(ErrorStyle.Render("\nNo results found."))
+		return nil
+	}
+
+	// The same model is run again after the details, so the filter and sort are kept
+	model := NewResourceTable(title, columns, rows, resources)
+	for {
+		p := tea.NewProgram(model, tea.WithAltScreen())
+
+		m, err := p.Run()
+		if err != nil {
+			return err
+		}
+
+		result, ok := m.(TableModel)
+		if !ok || !result.showDetails {
+			return nil
+		}
+
+		model = result
+		model.quitting = false
+		model.showDetails = false
+
+		resource := resources[model.selected]
+		if err := RunResourceDetails(resourceTitle(title, resource), resource); err != nil {
+			return err
+		}
+	}
+}
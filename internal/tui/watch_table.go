@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// WatchSnapshot is the result of a poll of a watched table
+type WatchSnapshot struct {
+	Columns []table.Column
+	Rows    []table.Row
+	// Changed counts the rows whose status changed since the previous poll
+	Changed int
+}
+
+// WatchTableModel is an interactive table polled at an interval and refreshed in place
+type WatchTableModel struct {
+	table    searchableTable
+	title    string
+	interval time.Duration
+	load     func() (WatchSnapshot, error)
+
+	loaded  bool
+	loading bool
+	// poll numbers the loads, so the ticks scheduled before a manual refresh are dropped
+	poll      int
+	changed   int
+	updatedAt time.Time
+	err       error
+	quitting  bool
+}
+
+type watchLoadedMsg struct {
+	snapshot WatchSnapshot
+	err      error
+}
+
+type watchTickMsg struct {
+	poll int
+}
+
+// NewWatchTable creates a table calling load now and then every interval
+func NewWatchTable(title string, interval time.Duration, load func() (WatchSnapshot, error)) WatchTableModel {
+	return WatchTableModel{
+		table:    newSearchableTable(nil, nil, 20),
+		title:    title,
+		interval: interval,
+		load:     load,
+		loading:  true,
+	}
+}
+
+func (m WatchTableModel) Init() tea.Cmd {
+	return m.loadCmd()
+}
+
+func (m WatchTableModel) loadCmd() tea.Cmd {
+	load := m.load
+	return func() tea.Msg {
+		snapshot, err := load()
+		return watchLoadedMsg{snapshot: snapshot, err: err}
+	}
+}
+
+func (m WatchTableModel) tickCmd() tea.Cmd {
+	poll := m.poll
+	return tea.Tick(m.interval, func(time.Time) tea.Msg { return watchTickMsg{poll: poll} })
+}
+
+func (m WatchTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case watchLoadedMsg:
+		m.loading = false
+		m.poll++
+		m.err = msg.err
+		if msg.err == nil {
+			m.loaded = true
+			m.changed = msg.snapshot.Changed
+			m.updatedAt = time.Now()
+			m.table.setData(msg.snapshot.Columns, msg.snapshot.Rows)
+		}
+		return m, m.tickCmd()
+
+	case watchTickMsg:
+		if m.loading || msg.poll != m.poll {
+			return m, nil
+		}
+		m.loading = true
+		return m, m.loadCmd()
+
+	case tea.KeyMsg:
+		if handled, cmd := m.table.handleKey(msg); handled {
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case "r":
+			if m.loading {
+				return m, nil
+			}
+			m.loading = true
+			return m, m.loadCmd()
+		}
+	}
+
+	cmd := m.table.update(msg)
+	return m, cmd
+}
+
+func (m WatchTableModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	header := ""
+	if m.title != "" {
+		header = TitleStyle.Render(m.title) + "\n\n"
+	}
+
+	if !m.loaded {
+		body := SubtitleStyle.Render("Loading...")
+		if m.err != nil {
+			body = ErrorStyle.Render("✗ Error: ") + m.err.Error()
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, header, body)
+	}
+
+	status := fmt.Sprintf("Showing %s • updated %s • every %s", m.table.count("records"), m.updatedAt.Format("15:04:05"), m.interval)
+	if m.changed > 0 {
+		status += fmt.Sprintf(" • ● %d changed since the last poll", m.changed)
+	}
+	footer := footerStyle.Render(status)
+	if m.err != nil {
+		footer += "\n" + ErrorStyle.Render("✗ Refresh failed: ") + m.err.Error()
+	}
+
+	help := HelpStyle.Render("↑/↓: navigate • " + searchHelp + " • r: refresh • q/esc: quit")
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		m.table.view(),
+		"\n",
+		footer,
+		help,
+	)
+}
+
+// RunWatchTable runs a table refreshed every interval until the user quits
+func RunWatchTable(title string, interval time.Duration, load func() (WatchSnapshot, error)) error {
+	p := tea.NewProgram(
+		NewWatchTable(title, interval, load),
+		tea.WithAltScreen(),
+	)
+
+	_, err := p.Run()
+	return err
+}
@@ -0,0 +1,206 @@
+package updater
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// keepBackups is how many previous binaries are kept for rollback
+const keepBackups = 3
+
+// errNoBackup is returned when there is no previous binary to roll back to
+var errNoBackup = errors.New("no previous version to roll back to, backups are kept by lsh update")
+
+// backupRoot returns the directory holding one subdirectory per backed up version. Overridden in tests
+var backupRoot = defaultBackupRoot
+
+// Backup is a previous lsh binary kept by an update
+type Backup struct {
+	Version   string
+	Path      string
+	CreatedAt time.Time
+}
+
+// userHome returns the home directory of the user running lsh. Under sudo, the invoking user's home
+// is used so the updater state is the same with or without sudo
+func userHome() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" {
+		if usr, err := user.Lookup(sudoUser); err == nil {
+			home = usr.HomeDir
+		}
+	}
+
+	return home, nil
+}
+
+// configDir returns the lsh config directory
+func configDir() (string, error) {
+	home, err := userHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".config", "lsh"), nil
+}
+
+// defaultBackupRoot keeps the backups next to the CLI config
+func defaultBackupRoot() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "backups"), nil
+}
+
+// backupExecutable copies execPath into the backup directory of version, then drops the oldest
+// backups beyond keepBackups
+func backupExecutable(execPath, version string) error {
+	root, err := backupRoot()
+	if err != nil {
+		return err
+	}
+
+	if !filepath.IsLocal(version) {
+		return fmt.Errorf("invalid version %q", version)
+	}
+
+	dir := filepath.Join(root, version)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to back up the current version: %w", err)
+	}
+
+	backupPath := filepath.Join(dir, buildExecutableName(OS))
+	if err := copyExecutable(execPath, backupPath); err != nil {
+		return fmt.Errorf("failed to back up the current version: %w", err)
+	}
+
+	// Under sudo, the backups are in the invoking user's home and must stay theirs
+	if err := giveToSudoUser(filepath.Dir(root), root, dir, backupPath); err != nil {
+		return fmt.Errorf("failed to back up the current version: %w", err)
+	}
+
+	// The directory time orders the backups, also when the same version is backed up again
+	now := time.Now()
+	os.Chtimes(dir, now, now)
+
+	return pruneBackups(keepBackups)
+}
+
+// ListBackups returns the backed up binaries, newest first
+func ListBackups() ([]Backup, error) {
+	root, err := backupRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	executable := buildExecutableName(OS)
+	backups := []Backup{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		path := filepath.Join(root, entry.Name(), executable)
+		if file, err := os.Lstat(path); err != nil || !file.Mode().IsRegular() {
+			continue
+		}
+
+		backups = append(backups, Backup{Version: entry.Name(), Path: path, CreatedAt: info.ModTime()})
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+
+	return backups, nil
+}
+
+func pruneBackups(keep int) error {
+	backups, err := ListBackups()
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range backups[min(keep, len(backups)):] {
+		os.RemoveAll(filepath.Dir(backup.Path))
+	}
+
+	return nil
+}
+
+// Rollback restores a backed up binary over the running one: the newest backup, or the backup of
+// version when it is set. It returns the restored version
+func Rollback(version string) (string, error) {
+	installation, err := DetectInstallation()
+	if err != nil {
+		return "", err
+	}
+	if err := checkInstallation(installation); err != nil {
+		return "", err
+	}
+
+	return rollback(version, installation.ExecPath)
+}
+
+func rollback(version, execPath string) (string, error) {
+	backups, err := ListBackups()
+	if err != nil {
+		return "", err
+	}
+	if len(backups) == 0 {
+		return "", errNoBackup
+	}
+
+	backup := backups[0]
+	if version != "" {
+		found := false
+		for _, b := range backups {
+			if b.Version == version {
+				backup, found = b, true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("no backup of %s, available: %s", version, strings.Join(backupVersions(backups), ", "))
+		}
+	}
+
+	if err := replaceExecutable(backup.Path, execPath); err != nil {
+		return "", err
+	}
+
+	// The restored version is installed now, so its backup is no longer needed
+	os.RemoveAll(filepath.Dir(backup.Path))
+
+	return backup.Version, nil
+}
+
+func backupVersions(backups []Backup) []string {
+	versions := make([]string, 0, len(backups))
+	for _, backup := range backups {
+		versions = append(versions, backup.Version)
+	}
+	return versions
+}
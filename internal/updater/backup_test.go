@@ -0,0 +1,118 @@
+package updater
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeBackup stores content as the backup of version, created age ago
+func writeBackup(t *testing.T, root, version, content string, age time.Duration) {
+	t.Helper()
+
+	dir := filepath.Join(root, version)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, buildExecutableName(OS)), []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Now().Add(-age)
+	if err := os.Chtimes(dir, created, created); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackupExecutable_KeepsNewest(t *testing.T) {
+	root := useTestBackups(t)
+	writeBackup(t, root, "v1.0.0", "v1", 4*time.Hour)
+	writeBackup(t, root, "v1.1.0", "v1.1", 3*time.Hour)
+	writeBackup(t, root, "v1.2.0", "v1.2", 2*time.Hour)
+
+	execPath := installedExecutable(t)
+	if err := backupExecutable(execPath, "v1.3.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := backupVersions(backups)
+	want := []string{"v1.3.0", "v1.2.0", "v1.1.0"}
+	if len(got) != len(want) {
+		t.Fatalf("backups = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("backups = %v, want %v", got, want)
+		}
+	}
+
+	content, err := os.ReadFile(backups[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "old binary" {
+		t.Errorf("backup = %q, want the current binary", content)
+	}
+}
+
+func TestBackupExecutable_RejectsInvalidVersion(t *testing.T) {
+	useTestBackups(t)
+
+	if err := backupExecutable(installedExecutable(t), "../v1.0.0"); err == nil {
+		t.Fatal("expected an error for a version escaping the backup directory")
+	}
+}
+
+func TestRollback(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		wantVersion string
+		wantContent string
+		wantErr     bool
+	}{
+		{name: "newest backup", wantVersion: "v1.2.0", wantContent: "v1.2"},
+		{name: "pinned backup", version: "v1.1.0", wantVersion: "v1.1.0", wantContent: "v1.1"},
+		{name: "unknown backup", version: "v0.9.0", wantErr: true, wantContent: "old binary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := useTestBackups(t)
+			writeBackup(t, root, "v1.1.0", "v1.1", 2*time.Hour)
+			writeBackup(t, root, "v1.2.0", "v1.2", time.Hour)
+
+			execPath := installedExecutable(t)
+			restored, err := rollback(tt.version, execPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rollback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if restored != tt.wantVersion {
+				t.Errorf("restored = %q, want %q", restored, tt.wantVersion)
+			}
+			assertExecutable(t, execPath, tt.wantContent)
+
+			if tt.wantErr {
+				return
+			}
+			if _, err := os.Stat(filepath.Join(root, tt.wantVersion)); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("backup of %s was not removed after the rollback", tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestRollback_NoBackup(t *testing.T) {
+	useTestBackups(t)
+
+	if _, err := rollback("", installedExecutable(t)); !errors.Is(err, errNoBackup) {
+		t.Fatalf("rollback() error = %v, want errNoBackup", err)
+	}
+}
@@ -5,12 +5,29 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 )
 
+// Release channels of the lsh CLI
+const (
+	ChannelStable     = "stable"
+	ChannelPrerelease = "prerelease"
+)
+
+// githubAPIURL is the GitHub API the releases are read from
+var githubAPIURL = "https://api.github.com"
+
+// githubClient bounds the release lookups, so an unreachable API does not hang the CLI
+var githubClient = &http.Client{Timeout: 30 * time.Second}
+
 // GithubRelease represents a release object from the Github API
 type GithubRelease struct {
-	Id      int    `json:"id"`
-	TagName string `json:"tag_name"`
+	Id         int    `json:"id"`
+	TagName    string `json:"tag_name"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
 }
 
 // latestLshRelease returns the latest lsh CLI release
@@ -23,9 +40,48 @@ func LatestLshRelease() (*GithubRelease, error) {
 	return &release, err
 }
 
+// LatestLshReleaseOn returns the newest release of a channel: the latest stable release, or the
+// newest release including pre-releases
+func LatestLshReleaseOn(channel string) (*GithubRelease, error) {
+	switch channel {
+	case "", ChannelStable:
+		return LatestLshRelease()
+	case ChannelPrerelease:
+	default:
+		return nil, fmt.Errorf("unknown channel %q: use %s or %s", channel, ChannelStable, ChannelPrerelease)
+	}
+
+	releases := []GithubRelease{}
+	if err := buildRelease("repos/latitudesh/lsh/releases?per_page=30", &releases); err != nil {
+		return nil, err
+	}
+
+	// GitHub lists the releases newest first
+	for _, release := range releases {
+		if !release.Draft {
+			return &release, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no release found on the %s channel", channel)
+}
+
+// LshRelease returns the release with the given tag, accepting versions without the "v" prefix
+func LshRelease(tag string) (*GithubRelease, error) {
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+
+	release := GithubRelease{}
+	if err := buildRelease("repos/latitudesh/lsh/releases/tags/"+url.PathEscape(tag), &release); err != nil {
+		return nil, fmt.Errorf("release %s not found: %w", tag, err)
+	}
+	return &release, nil
+}
+
 // buildRelease Unmarshals github information into a release object
-func buildRelease(url string, release *GithubRelease) error {
-	resp, err := gitHubRequest("GET", url, &http.Client{})
+func buildRelease(url string, release interface{}) error {
+	resp, err := gitHubRequest("GET", url, githubClient)
 	if err != nil {
 		return err
 	}
@@ -38,7 +94,7 @@ func buildRelease(url string, release *GithubRelease) error {
 
 // gitHubRequest accepts a method, path and a client to execute a github request
 func gitHubRequest(method, path string, client *http.Client) (string, error) {
-	req, _ := http.NewRequest(method, fmt.Sprintf("https://api.github.com/%s", path), nil)
+	req, _ := http.NewRequest(method, fmt.Sprintf("%s/%s", githubAPIURL, path), nil)
 	req.Header = http.Header{
 		"Accept":               {"application/vnd.github+json"},
 		"X-GitHub-Api-Version": {"2022-11-28"},
@@ -48,6 +104,11 @@ func gitHubRequest(method, path string, client *http.Client) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s from GitHub API %s", resp.Status, path)
+	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -0,0 +1,80 @@
+package updater
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// serveGitHubAPI answers GitHub API paths with canned JSON bodies
+func serveGitHubAPI(t *testing.T, responses map[string]string) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	previous := githubAPIURL
+	githubAPIURL = server.URL
+	t.Cleanup(func() { githubAPIURL = previous })
+}
+
+func TestLatestLshReleaseOn(t *testing.T) {
+	serveGitHubAPI(t, map[string]string{
+		"/repos/latitudesh/lsh/releases/latest": `{"id": 2, "tag_name": "v1.4.0"}`,
+		"/repos/latitudesh/lsh/releases": `[
+			{"id": 4, "tag_name": "v1.6.0-rc.1", "draft": true, "prerelease": true},
+			{"id": 3, "tag_name": "v1.5.0-rc.1", "prerelease": true},
+			{"id": 2, "tag_name": "v1.4.0"}
+		]`,
+	})
+
+	tests := []struct {
+		channel string
+		want    string
+		wantErr bool
+	}{
+		{channel: "", want: "v1.4.0"},
+		{channel: ChannelStable, want: "v1.4.0"},
+		{channel: ChannelPrerelease, want: "v1.5.0-rc.1"},
+		{channel: "nightly", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.channel, func(t *testing.T) {
+			release, err := LatestLshReleaseOn(tt.channel)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LatestLshReleaseOn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && release.TagName != tt.want {
+				t.Errorf("release = %s, want %s", release.TagName, tt.want)
+			}
+		})
+	}
+}
+
+func TestLshRelease(t *testing.T) {
+	serveGitHubAPI(t, map[string]string{
+		"/repos/latitudesh/lsh/releases/tags/v1.2.3": `{"id": 1, "tag_name": "v1.2.3"}`,
+	})
+
+	for _, tag := range []string{"v1.2.3", "1.2.3"} {
+		release, err := LshRelease(tag)
+		if err != nil {
+			t.Fatalf("LshRelease(%q) error = %v", tag, err)
+		}
+		if release.TagName != "v1.2.3" {
+			t.Errorf("LshRelease(%q) = %s, want v1.2.3", tag, release.TagName)
+		}
+	}
+
+	if _, err := LshRelease("v0.0.1"); err == nil {
+		t.Error("expected an error for an unknown release")
+	}
+}
@@ -0,0 +1,232 @@
+package updater
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// InstallMethod is how lsh was installed, which decides how it is updated
+type InstallMethod string
+
+const (
+	// InstallHomebrew is a Homebrew formula, updated with brew
+	InstallHomebrew InstallMethod = "homebrew"
+	// InstallPackage is a deb or rpm package, updated with the system package manager
+	InstallPackage InstallMethod = "package"
+	// InstallScript is the install.sh layout, the binary in ~/.lsh
+	InstallScript InstallMethod = "script"
+	// InstallManual is a binary copied anywhere else, for example from the releases page
+	InstallManual InstallMethod = "manual"
+)
+
+// homebrewFormula is the formula of the latitudesh/tools tap
+const homebrewFormula = "latitudesh/tools/lsh"
+
+// Installation describes the installed lsh binary
+type Installation struct {
+	Method   InstallMethod
+	ExecPath string
+	// Package is the deb or rpm package owning the binary
+	Package string
+	// UpgradeCommand updates an installation owned by a package manager. It is empty when
+	// lsh update can replace the binary itself
+	UpgradeCommand string
+	// Writable reports whether the directory of the binary can be written by the current user
+	Writable bool
+}
+
+// SelfUpdate reports whether lsh update can replace the binary, instead of a package manager
+func (i Installation) SelfUpdate() bool {
+	return i.UpgradeCommand == ""
+}
+
+// ManagedInstallError is returned when a package manager owns the binary. Replacing the binary
+// would leave the package manager with files it does not know about
+type ManagedInstallError struct {
+	Installation Installation
+}
+
+func (e *ManagedInstallError) Error() string {
+	return fmt.Sprintf("lsh was installed with %s, update it with: %s", e.Installation.Method, e.Installation.UpgradeCommand)
+}
+
+// installProbe inspects the system the binary is installed on. Tests point it at simulated layouts
+type installProbe struct {
+	// root prefixes the package databases, "/" on a real system
+	root     string
+	home     string
+	lookPath func(file string) (string, error)
+	// rpmOwner returns the rpm package owning path, or an empty string
+	rpmOwner func(path string) string
+	writable func(dir string) bool
+}
+
+func systemInstallProbe() installProbe {
+	home, _ := userHome()
+
+	return installProbe{
+		root:     "/",
+		home:     home,
+		lookPath: exec.LookPath,
+		rpmOwner: func(path string) string {
+			if _, err := exec.LookPath("rpm"); err != nil {
+				return ""
+			}
+			output, err := exec.Command("rpm", "-qf", "--queryformat", "%{NAME}", path).Output()
+			if err != nil {
+				return ""
+			}
+			return strings.TrimSpace(string(output))
+		},
+		writable: dirWritable,
+	}
+}
+
+// DetectInstallation finds out how the running binary was installed
+func DetectInstallation() (Installation, error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return Installation{}, err
+	}
+
+	execPath, err = filepath.EvalSymlinks(execPath)
+	if err != nil {
+		return Installation{}, err
+	}
+
+	return detectInstallation(execPath, systemInstallProbe()), nil
+}
+
+// detectInstallation matches execPath, with symlinks resolved, against the known install layouts
+func detectInstallation(execPath string, probe installProbe) Installation {
+	installation := Installation{
+		Method:   InstallManual,
+		ExecPath: execPath,
+		Writable: probe.writable(filepath.Dir(execPath)),
+	}
+
+	switch {
+	case isHomebrewPath(execPath):
+		installation.Method = InstallHomebrew
+		installation.UpgradeCommand = "brew upgrade " + homebrewFormula
+
+	case probe.home != "" && execPath == filepath.Join(probe.home, ".lsh", buildExecutableName(OS)):
+		installation.Method = InstallScript
+
+	case isSystemPath(execPath):
+		if pkg := dpkgOwner(probe.root, execPath); pkg != "" {
+			installation.Method = InstallPackage
+			installation.Package = pkg
+			installation.UpgradeCommand = "sudo apt-get install --only-upgrade " + pkg
+		} else if pkg := probe.rpmOwner(execPath); pkg != "" {
+			installation.Method = InstallPackage
+			installation.Package = pkg
+			installation.UpgradeCommand = rpmUpgradeCommand(pkg, probe.lookPath)
+		}
+	}
+
+	return installation
+}
+
+// isHomebrewPath matches the formula directory in a Homebrew cellar, such as
+// /opt/homebrew/Cellar/lsh/1.2.3/bin/lsh
+func isHomebrewPath(path string) bool {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if (parts[i] == "Cellar" || parts[i] == "Caskroom") && parts[i+1] == "lsh" {
+			return true
+		}
+	}
+	return false
+}
+
+// isSystemPath reports whether path is where distribution packages install binaries. Everything
+// under /usr/local is left to the administrator, so it is never owned by a package
+func isSystemPath(path string) bool {
+	path = filepath.ToSlash(path)
+	if strings.HasPrefix(path, "/usr/local/") {
+		return false
+	}
+	for _, prefix := range []string{"/usr/", "/bin/", "/sbin/", "/opt/"} {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// dpkgOwner returns the deb package listing path in the dpkg database, or an empty string
+func dpkgOwner(root, path string) string {
+	lists, err := filepath.Glob(filepath.Join(root, "var", "lib", "dpkg", "info", "*.list"))
+	if err != nil {
+		return ""
+	}
+
+	for _, list := range lists {
+		if listContains(list, path) {
+			pkg := strings.TrimSuffix(filepath.Base(list), ".list")
+			// Multi-arch packages are listed as <package>:<arch>
+			pkg, _, _ = strings.Cut(pkg, ":")
+			return pkg
+		}
+	}
+
+	return ""
+}
+
+func listContains(list, path string) bool {
+	file, err := os.Open(list)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if scanner.Text() == path {
+			return true
+		}
+	}
+	return false
+}
+
+func rpmUpgradeCommand(pkg string, lookPath func(string) (string, error)) string {
+	for _, manager := range []string{"dnf", "yum", "zypper"} {
+		if _, err := lookPath(manager); err == nil {
+			if manager == "zypper" {
+				return "sudo zypper update " + pkg
+			}
+			return fmt.Sprintf("sudo %s upgrade %s", manager, pkg)
+		}
+	}
+	return "sudo dnf upgrade " + pkg
+}
+
+// dirWritable tries to create a file in dir, the only check that holds for ACLs and read-only mounts
+func dirWritable(dir string) bool {
+	file, err := os.CreateTemp(dir, ".lsh-write-test-*")
+	if err != nil {
+		return false
+	}
+	file.Close()
+	os.Remove(file.Name())
+	return true
+}
+
+// checkInstallation refuses to replace a binary owned by a package manager or in a directory the
+// current user cannot write to
+func checkInstallation(installation Installation) error {
+	if !installation.SelfUpdate() {
+		return &ManagedInstallError{Installation: installation}
+	}
+
+	if !installation.Writable {
+		return fmt.Errorf("%s is not writable by the current user, run the command again with sudo", filepath.Dir(installation.ExecPath))
+	}
+
+	return nil
+}
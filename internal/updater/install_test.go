@@ -0,0 +1,202 @@
+package updater
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// simulatedProbe inspects a simulated system rooted in a temporary directory
+func simulatedProbe(t *testing.T, rpmPackages map[string]string, tools ...string) installProbe {
+	t.Helper()
+
+	return installProbe{
+		root: t.TempDir(),
+		home: "/home/alice",
+		lookPath: func(file string) (string, error) {
+			for _, tool := range tools {
+				if tool == file {
+					return "/usr/bin/" + file, nil
+				}
+			}
+			return "", errors.New("not found")
+		},
+		rpmOwner: func(path string) string { return rpmPackages[path] },
+		writable: func(dir string) bool {
+			return strings.HasPrefix(dir, "/home/") || strings.HasPrefix(dir, "/opt/homebrew/")
+		},
+	}
+}
+
+// writeDpkgList registers files of a deb package in the simulated dpkg database
+func writeDpkgList(t *testing.T, root, pkg string, files ...string) {
+	t.Helper()
+
+	dir := filepath.Join(root, "var", "lib", "dpkg", "info")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := strings.Join(files, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, pkg+".list"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetectInstallation(t *testing.T) {
+	if OS == "windows" {
+		t.Skip("install layouts are Unix paths")
+	}
+
+	tests := []struct {
+		name         string
+		execPath     string
+		dpkg         map[string][]string
+		rpm          map[string]string
+		tools        []string
+		wantMethod   InstallMethod
+		wantPackage  string
+		wantCommand  string
+		wantWritable bool
+	}{
+		{
+			name:         "homebrew on macOS",
+			execPath:     "/opt/homebrew/Cellar/lsh/1.2.3/bin/lsh",
+			wantMethod:   InstallHomebrew,
+			wantCommand:  "brew upgrade latitudesh/tools/lsh",
+			wantWritable: true,
+		},
+		{
+			name:        "homebrew on Linux",
+			execPath:    "/home/linuxbrew/.linuxbrew/Cellar/lsh/1.2.3/bin/lsh",
+			wantMethod:  InstallHomebrew,
+			wantCommand: "brew upgrade latitudesh/tools/lsh",
+			// The test probe treats everything under /home as writable
+			wantWritable: true,
+		},
+		{
+			name:         "install script",
+			execPath:     "/home/alice/.lsh/lsh",
+			wantMethod:   InstallScript,
+			wantWritable: true,
+		},
+		{
+			name:        "deb package",
+			execPath:    "/usr/bin/lsh",
+			dpkg:        map[string][]string{"coreutils": {"/usr/bin/ls"}, "lsh:amd64": {"/usr", "/usr/bin", "/usr/bin/lsh"}},
+			wantMethod:  InstallPackage,
+			wantPackage: "lsh",
+			wantCommand: "sudo apt-get install --only-upgrade lsh",
+		},
+		{
+			name:        "rpm package with dnf",
+			execPath:    "/usr/bin/lsh",
+			rpm:         map[string]string{"/usr/bin/lsh": "lsh"},
+			tools:       []string{"rpm", "dnf", "yum"},
+			wantMethod:  InstallPackage,
+			wantPackage: "lsh",
+			wantCommand: "sudo dnf upgrade lsh",
+		},
+		{
+			name:        "rpm package with zypper",
+			execPath:    "/usr/bin/lsh",
+			rpm:         map[string]string{"/usr/bin/lsh": "lsh"},
+			tools:       []string{"rpm", "zypper"},
+			wantMethod:  InstallPackage,
+			wantPackage: "lsh",
+			wantCommand: "sudo zypper update lsh",
+		},
+		{
+			name:       "binary copied to a system path",
+			execPath:   "/usr/bin/lsh",
+			dpkg:       map[string][]string{"coreutils": {"/usr/bin/ls"}},
+			wantMethod: InstallManual,
+		},
+		{
+			name:       "binary copied to /usr/local",
+			execPath:   "/usr/local/bin/lsh",
+			dpkg:       map[string][]string{"lsh": {"/usr/local/bin/lsh"}},
+			wantMethod: InstallManual,
+		},
+		{
+			name:         "binary in another home directory",
+			execPath:     "/home/alice/bin/lsh",
+			wantMethod:   InstallManual,
+			wantWritable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := simulatedProbe(t, tt.rpm, tt.tools...)
+			for pkg, files := range tt.dpkg {
+				writeDpkgList(t, probe.root, pkg, files...)
+			}
+
+			got := detectInstallation(tt.execPath, probe)
+
+			if got.Method != tt.wantMethod {
+				t.Errorf("Method = %s, want %s", got.Method, tt.wantMethod)
+			}
+			if got.Package != tt.wantPackage {
+				t.Errorf("Package = %q, want %q", got.Package, tt.wantPackage)
+			}
+			if got.UpgradeCommand != tt.wantCommand {
+				t.Errorf("UpgradeCommand = %q, want %q", got.UpgradeCommand, tt.wantCommand)
+			}
+			if got.SelfUpdate() != (tt.wantCommand == "") {
+				t.Errorf("SelfUpdate() = %v with upgrade command %q", got.SelfUpdate(), got.UpgradeCommand)
+			}
+			if got.Writable != tt.wantWritable {
+				t.Errorf("Writable = %v, want %v", got.Writable, tt.wantWritable)
+			}
+		})
+	}
+}
+
+func TestCheckInstallation(t *testing.T) {
+	tests := []struct {
+		name         string
+		installation Installation
+		wantErr      string
+		wantManaged  bool
+	}{
+		{
+			name:         "writable manual install",
+			installation: Installation{Method: InstallManual, ExecPath: "/home/alice/bin/lsh", Writable: true},
+		},
+		{
+			name:         "package manager install",
+			installation: Installation{Method: InstallHomebrew, ExecPath: "/opt/homebrew/Cellar/lsh/1.2.3/bin/lsh", UpgradeCommand: "brew upgrade latitudesh/tools/lsh", Writable: true},
+			wantErr:      "update it with: brew upgrade latitudesh/tools/lsh",
+			wantManaged:  true,
+		},
+		{
+			name:         "root owned directory",
+			installation: Installation{Method: InstallManual, ExecPath: "/usr/local/bin/lsh"},
+			wantErr:      "run the command again with sudo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkInstallation(tt.installation)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkInstallation() error = %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkInstallation() error = %v, want %q", err, tt.wantErr)
+			}
+
+			var managed *ManagedInstallError
+			if errors.As(err, &managed) != tt.wantManaged {
+				t.Errorf("ManagedInstallError = %v, want %v", managed != nil, tt.wantManaged)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package updater
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// updateCheckInterval is how often the notifier asks GitHub for a new release
+const updateCheckInterval = 24 * time.Hour
+
+// NoUpdateNotifierEnv disables the update notifier when set to any value
+const NoUpdateNotifierEnv = "LSH_NO_UPDATE_NOTIFIER"
+
+// ciEnvs are set by CI services, where the notice would only add noise to the logs
+var ciEnvs = []string{"CI", "BUILD_NUMBER", "RUN_ID", "GITHUB_ACTIONS", "GITLAB_CI", "BUILDKITE", "TF_BUILD", "JENKINS_URL"}
+
+// updateCheckCachePath returns the file caching the last release check. Overridden in tests
+var updateCheckCachePath = defaultUpdateCheckCachePath
+
+// latestRelease looks up the newest release of a channel. Overridden in tests
+var latestRelease = LatestLshReleaseOn
+
+// upgradeCommand detects how lsh was installed, which probes the filesystem and the package
+// managers, so the notifier only runs it with the release check. Overridden in tests
+var upgradeCommand = UpgradeCommand
+
+// updateCheck is the cached result of the last release check
+type updateCheck struct {
+	CheckedAt      time.Time `json:"checked_at"`
+	LatestVersion  string    `json:"latest_version"`
+	UpgradeCommand string    `json:"upgrade_command,omitempty"`
+}
+
+// Notifier tells the user about a new release after a command. GitHub is asked at most once a day,
+// in the background, and the command never waits on it for longer than the grace period
+type Notifier struct {
+	current string
+	done    chan struct{}
+}
+
+func defaultUpdateCheckCachePath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "update-check.json"), nil
+}
+
+// NotifierDisabledByEnv reports whether the environment opts out of the update notifier: with
+// LSH_NO_UPDATE_NOTIFIER or on a CI service
+func NotifierDisabledByEnv(getenv func(string) string) bool {
+	if getenv(NoUpdateNotifierEnv) != "" {
+		return true
+	}
+
+	for _, env := range ciEnvs {
+		if getenv(env) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StartNotifier refreshes the cached release check in the background when it is older than a day.
+// Development builds are never notified, so nil is returned for them
+func StartNotifier(current string) *Notifier {
+	if IsDevBuild(current) {
+		return nil
+	}
+
+	n := &Notifier{current: current, done: make(chan struct{})}
+
+	check, _ := readUpdateCheck()
+	if time.Since(check.CheckedAt) < updateCheckInterval {
+		close(n.done)
+		return n
+	}
+
+	go func() {
+		defer close(n.done)
+		n.refresh(check)
+	}()
+
+	return n
+}
+
+// refresh looks up the latest release and caches it. The check time is recorded even when the
+// lookup fails, so an offline machine is not slowed down by a lookup on every command
+func (n *Notifier) refresh(previous updateCheck) {
+	channel := ChannelStable
+	if isPrerelease(n.current) {
+		channel = ChannelPrerelease
+	}
+
+	check := updateCheck{CheckedAt: time.Now(), LatestVersion: previous.LatestVersion, UpgradeCommand: upgradeCommand()}
+	if release, err := latestRelease(channel); err == nil {
+		check.LatestVersion = release.TagName
+	}
+
+	writeUpdateCheck(check)
+}
+
+// Notice returns the one-line notice about a newer release, or an empty string. It waits up to
+// grace for a release check started by StartNotifier, then falls back to the cached result, which
+// also holds the upgrade command
+func (n *Notifier) Notice(grace time.Duration) string {
+	if n == nil {
+		return ""
+	}
+
+	select {
+	case <-n.done:
+	case <-time.After(grace):
+	}
+
+	check, err := readUpdateCheck()
+	if err != nil || !IsNewerVersion(check.LatestVersion, n.current) {
+		return ""
+	}
+
+	command := check.UpgradeCommand
+	if command == "" {
+		command = "lsh update"
+	}
+
+	return fmt.Sprintf("A new release of lsh is available: %s -> %s. Run '%s' to install it.", n.current, check.LatestVersion, command)
+}
+
+// UpgradeCommand returns the command updating the installed lsh: the package manager command when
+// one owns the binary, otherwise lsh update
+func UpgradeCommand() string {
+	installation, err := DetectInstallation()
+	if err != nil || installation.SelfUpdate() {
+		return "lsh update"
+	}
+	return installation.UpgradeCommand
+}
+
+func readUpdateCheck() (updateCheck, error) {
+	check := updateCheck{}
+
+	path, err := updateCheckCachePath()
+	if err != nil {
+		return check, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return check, err
+	}
+
+	if err := json.Unmarshal(content, &check); err != nil {
+		return updateCheck{}, err
+	}
+	return check, nil
+}
+
+// writeUpdateCheck replaces the cache through a rename, so concurrent commands never read a
+// partially written file. Under sudo, the cache stays the invoking user's
+func writeUpdateCheck(check updateCheck) error {
+	path, err := updateCheckCachePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(check)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".update-check-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	return giveToSudoUser(filepath.Dir(path), path)
+}
@@ -0,0 +1,172 @@
+package updater
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useTestUpdateCheck keeps the release check cache in a temporary directory and answers release
+// lookups with latest, counting them
+func useTestUpdateCheck(t *testing.T, latest string, lookupErr error) *int {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "update-check.json")
+	previousPath, previousLookup, previousUpgrade := updateCheckCachePath, latestRelease, upgradeCommand
+
+	lookups := 0
+	updateCheckCachePath = func() (string, error) { return path, nil }
+	upgradeCommand = func() string { return "lsh update" }
+	latestRelease = func(channel string) (*GithubRelease, error) {
+		lookups++
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		return &GithubRelease{TagName: latest}, nil
+	}
+	t.Cleanup(func() {
+		updateCheckCachePath, latestRelease, upgradeCommand = previousPath, previousLookup, previousUpgrade
+	})
+
+	return &lookups
+}
+
+func TestNotifier(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     string
+		latest      string
+		cached      *updateCheck
+		lookupErr   error
+		wantNotice  bool
+		wantLookups int
+	}{
+		{name: "newer release", current: "v1.2.0", latest: "v1.3.0", wantNotice: true, wantLookups: 1},
+		{name: "up to date", current: "v1.3.0", latest: "v1.3.0", wantLookups: 1},
+		{name: "local build ahead", current: "v1.4.0-dev", latest: "v1.3.0", wantLookups: 1},
+		{name: "development build", current: "v0.0.0", latest: "v1.3.0"},
+		{
+			name:       "fresh cache",
+			current:    "v1.2.0",
+			latest:     "v1.4.0",
+			cached:     &updateCheck{CheckedAt: time.Now().Add(-time.Hour), LatestVersion: "v1.3.0"},
+			wantNotice: true,
+		},
+		{
+			name:        "stale cache",
+			current:     "v1.3.0",
+			latest:      "v1.4.0",
+			cached:      &updateCheck{CheckedAt: time.Now().Add(-25 * time.Hour), LatestVersion: "v1.3.0"},
+			wantNotice:  true,
+			wantLookups: 1,
+		},
+		{
+			name:        "lookup failure keeps the cached release",
+			current:     "v1.2.0",
+			cached:      &updateCheck{CheckedAt: time.Now().Add(-25 * time.Hour), LatestVersion: "v1.3.0"},
+			lookupErr:   errors.New("offline"),
+			wantNotice:  true,
+			wantLookups: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookups := useTestUpdateCheck(t, tt.latest, tt.lookupErr)
+			if tt.cached != nil {
+				if err := writeUpdateCheck(*tt.cached); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			notice := StartNotifier(tt.current).Notice(time.Second)
+			if (notice != "") != tt.wantNotice {
+				t.Errorf("notice = %q, want a notice: %v", notice, tt.wantNotice)
+			}
+			if notice != "" && !strings.Contains(notice, "lsh update") {
+				t.Errorf("notice %q does not say how to update", notice)
+			}
+			if *lookups != tt.wantLookups {
+				t.Errorf("lookups = %d, want %d", *lookups, tt.wantLookups)
+			}
+
+			if tt.wantLookups == 0 {
+				return
+			}
+			check, err := readUpdateCheck()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if time.Since(check.CheckedAt) > time.Minute {
+				t.Errorf("check time was not recorded: %v", check.CheckedAt)
+			}
+		})
+	}
+}
+
+func TestNotifier_CachesUpgradeCommand(t *testing.T) {
+	useTestUpdateCheck(t, "v1.3.0", nil)
+
+	detections := 0
+	upgradeCommand = func() string {
+		detections++
+		return "brew upgrade latitudesh/tools/lsh"
+	}
+
+	for i := 0; i < 3; i++ {
+		notice := StartNotifier("v1.2.0").Notice(time.Second)
+		if !strings.Contains(notice, "brew upgrade latitudesh/tools/lsh") {
+			t.Errorf("notice = %q, want the cached upgrade command", notice)
+		}
+	}
+	if detections != 1 {
+		t.Errorf("installation detected %d times, want once with the release check", detections)
+	}
+}
+
+func TestNotifier_DoesNotBlock(t *testing.T) {
+	useTestUpdateCheck(t, "v1.3.0", nil)
+
+	release := make(chan struct{})
+	latestRelease = func(channel string) (*GithubRelease, error) {
+		<-release
+		return &GithubRelease{TagName: "v1.3.0"}, nil
+	}
+
+	start := time.Now()
+	notifier := StartNotifier("v1.2.0")
+
+	// Let the release check finish before the test state is restored
+	t.Cleanup(func() {
+		close(release)
+		<-notifier.done
+	})
+
+	if notice := notifier.Notice(10 * time.Millisecond); notice != "" {
+		t.Errorf("notice = %q, want none before the first check finished", notice)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Notice waited %v on a slow release check", elapsed)
+	}
+}
+
+func TestNotifierDisabledByEnv(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want bool
+	}{
+		{env: map[string]string{}, want: false},
+		{env: map[string]string{NoUpdateNotifierEnv: "1"}, want: true},
+		{env: map[string]string{"CI": "true"}, want: true},
+		{env: map[string]string{"GITHUB_ACTIONS": "true"}, want: true},
+	}
+
+	for _, tt := range tests {
+		getenv := func(key string) string { return tt.env[key] }
+		if got := NotifierDisabledByEnv(getenv); got != tt.want {
+			t.Errorf("NotifierDisabledByEnv(%v) = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+//go:build !windows
+
+package updater
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// preserveOwner gives staged the owner of original. Only root can change owners, and only root
+// can replace a binary of another user
+func preserveOwner(original, staged string) error {
+	if os.Geteuid() != 0 {
+		return nil
+	}
+
+	info, err := os.Stat(original)
+	if err != nil {
+		return err
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+
+	return os.Chown(staged, int(stat.Uid), int(stat.Gid))
+}
+
+// giveToSudoUser gives paths, created by root in the invoking user's home under sudo, back to that
+// user, or the next run without sudo cannot write them
+func giveToSudoUser(paths ...string) error {
+	if os.Geteuid() != 0 {
+		return nil
+	}
+
+	uid, err := strconv.Atoi(os.Getenv("SUDO_UID"))
+	if err != nil {
+		return nil
+	}
+	gid, err := strconv.Atoi(os.Getenv("SUDO_GID"))
+	if err != nil {
+		return nil
+	}
+
+	for _, path := range paths {
+		if err := os.Lchown(path, uid, gid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
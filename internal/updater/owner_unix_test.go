@@ -0,0 +1,33 @@
+//go:build !windows
+
+package updater
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestBackupExecutable_GivesBackupsToSudoUser(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing owners requires root")
+	}
+	root := useTestBackups(t)
+	t.Setenv("SUDO_UID", "1234")
+	t.Setenv("SUDO_GID", "5678")
+
+	if err := backupExecutable(installedExecutable(t), "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{root, filepath.Join(root, "v1.0.0"), filepath.Join(root, "v1.0.0", buildExecutableName(OS))} {
+		info, err := os.Lstat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stat := info.Sys().(*syscall.Stat_t); stat.Uid != 1234 || stat.Gid != 5678 {
+			t.Errorf("%s is owned by %d:%d, want 1234:5678", path, stat.Uid, stat.Gid)
+		}
+	}
+}
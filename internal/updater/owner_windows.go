@@ -0,0 +1,11 @@
+package updater
+
+// preserveOwner is a no-op on Windows, where the new file inherits the directory permissions
+func preserveOwner(original, staged string) error {
+	return nil
+}
+
+// giveToSudoUser is a no-op on Windows, which has no sudo
+func giveToSudoUser(paths ...string) error {
+	return nil
+}
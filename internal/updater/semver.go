@@ -0,0 +1,135 @@
+package updater
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version, see https://semver.org
+type semver struct {
+	major, minor, patch int
+	prerelease          []string
+}
+
+// parseSemver parses versions such as v1.2.3, 1.2.3-rc.1 and v1.2.3+build.5. Build metadata is
+// ignored, as it does not take part in the precedence
+func parseSemver(version string) (semver, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	v, _, _ = strings.Cut(v, "+")
+	core, prerelease, hasPrerelease := strings.Cut(v, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return semver{}, fmt.Errorf("invalid version %q: expected MAJOR.MINOR.PATCH", version)
+	}
+
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (len(part) > 1 && part[0] == '0') {
+			return semver{}, fmt.Errorf("invalid version %q", version)
+		}
+		numbers[i] = n
+	}
+
+	parsed := semver{major: numbers[0], minor: numbers[1], patch: numbers[2]}
+	if hasPrerelease {
+		parsed.prerelease = strings.Split(prerelease, ".")
+		for _, identifier := range parsed.prerelease {
+			if identifier == "" {
+				return semver{}, fmt.Errorf("invalid version %q: empty pre-release identifier", version)
+			}
+		}
+	}
+
+	return parsed, nil
+}
+
+// CompareVersions returns -1, 0 or 1 when a is older than, the same as or newer than b
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseSemver(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseSemver(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pair := range [][2]int{{va.major, vb.major}, {va.minor, vb.minor}, {va.patch, vb.patch}} {
+		if c := compareInts(pair[0], pair[1]); c != 0 {
+			return c, nil
+		}
+	}
+
+	return comparePrerelease(va.prerelease, vb.prerelease), nil
+}
+
+// IsNewerVersion reports whether candidate is a newer release than current. Versions that cannot
+// be parsed are never newer
+func IsNewerVersion(candidate, current string) bool {
+	c, err := CompareVersions(candidate, current)
+	return err == nil && c > 0
+}
+
+// IsDevBuild reports whether version is not a published release: the default version of local
+// builds, or a version that is not semantic
+func IsDevBuild(version string) bool {
+	v, err := parseSemver(version)
+	if err != nil {
+		return true
+	}
+	return v.major == 0 && v.minor == 0 && v.patch == 0
+}
+
+// isPrerelease reports whether version has a pre-release part, such as v1.2.0-rc.1
+func isPrerelease(version string) bool {
+	v, err := parseSemver(version)
+	return err == nil && len(v.prerelease) > 0
+}
+
+// comparePrerelease follows the semver precedence: a release is newer than its pre-releases,
+// numeric identifiers compare numerically and sort before alphanumeric ones
+func comparePrerelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		na, errA := strconv.Atoi(a[i])
+		nb, errB := strconv.Atoi(b[i])
+
+		switch {
+		case errA == nil && errB == nil:
+			if c := compareInts(na, nb); c != 0 {
+				return c
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return compareInts(len(a), len(b))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,69 @@
+package updater
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"1.2.3", "v1.2.3", 0},
+		{"v1.2.3+build.5", "v1.2.3", 0},
+		{"v1.2.4", "v1.2.3", 1},
+		{"v1.10.0", "v1.9.9", 1},
+		{"v2.0.0", "v1.99.99", 1},
+		{"v1.2.3", "v1.2.3-rc.1", 1},
+		{"v1.2.3-rc.2", "v1.2.3-rc.1", 1},
+		{"v1.2.3-rc.10", "v1.2.3-rc.9", 1},
+		{"v1.2.3-rc.1", "v1.2.3-beta.2", 1},
+		{"v1.2.3-alpha", "v1.2.3-1", 1},
+		{"v1.2.3-alpha.1", "v1.2.3-alpha", 1},
+		{"v1.2.2", "v1.2.3-rc.1", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			got, err := CompareVersions(tt.a, tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+
+			reverse, _ := CompareVersions(tt.b, tt.a)
+			if reverse != -tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.b, tt.a, reverse, -tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareVersions_Invalid(t *testing.T) {
+	for _, version := range []string{"", "dev", "v1.2", "v1.2.3.4", "v01.2.3", "v1.2.x", "v1.2.3-", "v1.2.3-rc..1"} {
+		if _, err := CompareVersions(version, "v1.0.0"); err == nil {
+			t.Errorf("CompareVersions(%q) expected an error", version)
+		}
+	}
+}
+
+func TestIsNewerVersion(t *testing.T) {
+	if !IsNewerVersion("v1.3.0", "v1.2.9") {
+		t.Error("v1.3.0 should be newer than v1.2.9")
+	}
+	if IsNewerVersion("v1.2.0", "v1.3.0-dev") {
+		t.Error("a local build ahead of the release should not be offered an update")
+	}
+	if IsNewerVersion("v1.3.0", "dev") {
+		t.Error("an unparseable current version should not be offered an update")
+	}
+}
+
+func TestIsDevBuild(t *testing.T) {
+	for version, want := range map[string]bool{"v0.0.0": true, "dev": true, "": true, "v1.2.3": false, "v0.1.0": false} {
+		if got := IsDevBuild(version); got != want {
+			t.Errorf("IsDevBuild(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
@@ -1,64 +1,82 @@
 package updater
 
 import (
+	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 
 	"github.com/latitudesh/lsh/internal/utils"
+	currentversion "github.com/latitudesh/lsh/internal/version"
 )
 
 // Update expects a version string and updates the cli to it
 func Update(version string) error {
+	// Replace the binary itself, not a symlink pointing at it, and only when no package manager
+	// owns it
+	installation, err := DetectInstallation()
+	if err != nil {
+		return err
+	}
+	if err := checkInstallation(installation); err != nil {
+		return err
+	}
+
+	return update(version, installation.ExecPath)
+}
+
+// update downloads, verifies and installs a release over execPath. Nothing is replaced unless
+// every check passed
+func update(version, execPath string) error {
 	log.Println("Update started!")
 
 	updateFile := NewUpdateFile()
 
-	tempDir, err := os.MkdirTemp("", "temp")
+	tempDir, err := os.MkdirTemp("", "lsh-update")
 	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(tempDir)
 
-	destinationPath := filepath.Join(tempDir, updateFile.Name)
+	archivePath := filepath.Join(tempDir, updateFile.Name)
+	checksumsPath := filepath.Join(tempDir, checksumsFile)
 
 	log.Println("Downloading new version...")
-	err = updateFile.Download(destinationPath, version)
-	if err != nil {
+	if err := updateFile.Download(archivePath, version); err != nil {
 		return err
 	}
+	if err := downloadFile(releaseAssetURL(version, checksumsFile), checksumsPath); err != nil {
+		return fmt.Errorf("cannot verify the download: %w", err)
+	}
 	log.Println("Download finished successfully!")
 
-	err = decompress(tempDir, updateFile)
-	if err != nil {
+	if err := verifySignatures(version, tempDir); err != nil {
 		return err
 	}
-
-	currentExecPath, err := os.Executable()
-	if err != nil {
+	if err := verifyChecksum(checksumsPath, archivePath, updateFile.Name); err != nil {
 		return err
 	}
-	oldExecPath := currentExecPath + "-old"
-	newExecPath := filepath.Join(tempDir, updateFile.Dir, updateFile.Executable)
+	log.Println("Checksum verified!")
 
-	// Rename current lsh binary to lsh-old
-	err = os.Rename(currentExecPath, oldExecPath)
-	if err != nil {
+	extractDir := filepath.Join(tempDir, "extract")
+	if err := decompress(archivePath, extractDir, updateFile); err != nil {
 		return err
 	}
 
-	// Rename and move downloaded lsh binary
-	err = os.Rename(newExecPath, currentExecPath)
-	if err != nil {
-		return err
+	newExecPath := filepath.Join(extractDir, updateFile.Dir, updateFile.Executable)
+	if info, err := os.Lstat(newExecPath); err != nil || !info.Mode().IsRegular() {
+		return fmt.Errorf("release archive does not contain %s", filepath.Join(updateFile.Dir, updateFile.Executable))
 	}
 
-	// Remove old binary (linux|macOs only)
-	if OS != "windows" {
-		err = os.Remove(oldExecPath)
-		if err != nil {
-			return err
-		}
+	// Keep the running binary so lsh update rollback can restore it. The backup is not worth failing
+	// the update for
+	if err := backupExecutable(execPath, currentversion.Version); err != nil {
+		log.Printf("Warning: %v, 'lsh update rollback' will not restore it", err)
+	}
+
+	if err := replaceExecutable(newExecPath, execPath); err != nil {
+		return err
 	}
 
 	log.Println("Update finished successfully!")
@@ -66,20 +84,71 @@ func Update(version string) error {
 	return nil
 }
 
-func decompress(destination string, file *UpdateFile) error {
-	source := filepath.Join(destination, file.Name)
-	if file.Extension == ".tar.gz" {
-		err := utils.Untar(source, destination)
-		if err != nil {
+// replaceExecutable swaps execPath for newExecPath. The new binary is staged in the same directory
+// first, so the final rename is atomic and an interrupted update leaves the current binary in place
+func replaceExecutable(newExecPath, execPath string) error {
+	stagedPath := execPath + ".new"
+	if err := copyExecutable(newExecPath, stagedPath); err != nil {
+		os.Remove(stagedPath)
+		return err
+	}
+
+	// Under sudo, a binary installed by the user must stay theirs, or the next update without sudo
+	// fails
+	if err := preserveOwner(execPath, stagedPath); err != nil {
+		os.Remove(stagedPath)
+		return fmt.Errorf("failed to stage the new version: %w", err)
+	}
+
+	// A running executable cannot be replaced on Windows, only renamed
+	if OS == "windows" {
+		oldExecPath := execPath + "-old"
+		os.Remove(oldExecPath)
+
+		if err := os.Rename(execPath, oldExecPath); err != nil {
+			os.Remove(stagedPath)
+			return err
+		}
+		if err := os.Rename(stagedPath, execPath); err != nil {
+			os.Rename(oldExecPath, execPath)
+			os.Remove(stagedPath)
 			return err
 		}
-
 		return nil
 	}
 
-	err := utils.Unzip(source, destination)
-	if err != nil {
+	if err := os.Rename(stagedPath, execPath); err != nil {
+		os.Remove(stagedPath)
 		return err
 	}
+
 	return nil
 }
+
+func copyExecutable(source, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to stage the new version: %w", err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to stage the new version: %w", err)
+	}
+
+	return out.Close()
+}
+
+func decompress(source, destination string, file *UpdateFile) error {
+	if file.Extension == ".tar.gz" {
+		return utils.Untar(source, destination)
+	}
+
+	return utils.Unzip(source, destination)
+}
@@ -1,11 +1,13 @@
 package updater
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"runtime"
+	"time"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -36,25 +38,55 @@ func NewUpdateFile() *UpdateFile {
 	}
 }
 
+// releaseBaseURL is where the assets of every release are downloaded from
+var releaseBaseURL = "https://github.com/latitudesh/lsh/releases/download"
+
+// httpClient downloads the release assets
+var httpClient = &http.Client{Timeout: 5 * time.Minute}
+
+// errAssetNotFound is returned when a release does not publish an asset
+var errAssetNotFound = errors.New("release asset not found")
+
+// releaseAssetURL is the download URL of an asset of a release
+func releaseAssetURL(version, name string) string {
+	return fmt.Sprintf("%s/%s/%s", releaseBaseURL, version, name)
+}
+
 func (uf *UpdateFile) Url(version string) string {
-	return fmt.Sprintf("https://github.com/latitudesh/lsh/releases/download/%s/%s", version, uf.Name)
+	return releaseAssetURL(version, uf.Name)
 }
 
 func (uf *UpdateFile) Download(dstPath, version string) error {
-	resp, err := http.Get(uf.Url(version))
+	return downloadFile(uf.Url(version), dstPath)
+}
+
+// downloadFile saves url to dstPath, failing on any response but 200 OK so an error page is
+// never taken for the asset
+func downloadFile(url, dstPath string) error {
+	resp, err := httpClient.Get(url)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", errAssetNotFound, url)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s downloading %s", resp.Status, url)
+	}
+
 	out, err := os.Create(dstPath)
 	if err != nil {
 		return err
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
-	return err
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+
+	return out.Close()
 }
 
 // buildDirName builds the correct file name for your Operating system and architecture
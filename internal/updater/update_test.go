@@ -0,0 +1,342 @@
+package updater
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testVersion = "v9.9.9"
+
+type archiveEntry struct {
+	name     string
+	content  string
+	typeflag byte
+}
+
+func makeTarGz(t *testing.T, entries []archiveEntry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+
+	for _, entry := range entries {
+		typeflag := entry.typeflag
+		switch {
+		case typeflag == 0 && strings.HasSuffix(entry.name, "/"):
+			typeflag = tar.TypeDir
+		case typeflag == 0:
+			typeflag = tar.TypeReg
+		}
+		header := &tar.Header{Name: entry.name, Mode: 0755, Size: int64(len(entry.content)), Typeflag: typeflag}
+		if typeflag == tar.TypeSymlink {
+			header.Linkname = entry.content
+			header.Size = 0
+		}
+		if typeflag == tar.TypeDir {
+			header.Name = strings.TrimSuffix(entry.name, "/")
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(entry.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// serveRelease starts a fixture server publishing the given assets of testVersion
+func serveRelease(t *testing.T, assets map[string][]byte, statuses map[string]int) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/"+testVersion+"/")
+		if status, ok := statuses[name]; ok {
+			w.WriteHeader(status)
+			return
+		}
+		content, ok := assets[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(content)
+	}))
+	t.Cleanup(server.Close)
+
+	previousURL, previousClient := releaseBaseURL, httpClient
+	releaseBaseURL, httpClient = server.URL, server.Client()
+	t.Cleanup(func() { releaseBaseURL, httpClient = previousURL, previousClient })
+
+	useTestBackups(t)
+}
+
+// useTestBackups keeps the backups of the running binary in a temporary directory
+func useTestBackups(t *testing.T) string {
+	t.Helper()
+
+	root := filepath.Join(t.TempDir(), "backups")
+	previous := backupRoot
+	backupRoot = func() (string, error) { return root, nil }
+	t.Cleanup(func() { backupRoot = previous })
+
+	return root
+}
+
+// installedExecutable writes the current binary to a temporary directory
+func installedExecutable(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "lsh")
+	if err := os.WriteFile(path, []byte("old binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func assertExecutable(t *testing.T, path, want string) {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != want {
+		t.Errorf("executable = %q, want %q", content, want)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("leftover files next to the executable: %v", entries)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	if OS == "windows" {
+		t.Skip("release fixtures are tar.gz archives")
+	}
+
+	file := NewUpdateFile()
+	binaryPath := file.Dir + "/" + file.Executable
+	archive := makeTarGz(t, []archiveEntry{{name: file.Dir + "/"}, {name: binaryPath, content: "new binary"}})
+	checksums := []byte(fmt.Sprintf("%s  %s\n%s  lsh_Other_arch.tar.gz\n", sha256Hex(archive), file.Name, sha256Hex(nil)))
+
+	traversal := makeTarGz(t, []archiveEntry{{name: "../escaped", content: "evil"}, {name: binaryPath, content: "new binary"}})
+	symlink := makeTarGz(t, []archiveEntry{{name: file.Dir + "/link", content: "/etc/passwd", typeflag: tar.TypeSymlink}})
+
+	tests := []struct {
+		name     string
+		assets   map[string][]byte
+		statuses map[string]int
+		wantErr  string
+	}{
+		{
+			name:   "installs a verified release",
+			assets: map[string][]byte{file.Name: archive, checksumsFile: checksums},
+		},
+		{
+			name:    "rejects a checksum mismatch",
+			assets:  map[string][]byte{file.Name: append(archive, 0), checksumsFile: checksums},
+			wantErr: "checksum mismatch",
+		},
+		{
+			name:    "rejects a release without checksums",
+			assets:  map[string][]byte{file.Name: archive},
+			wantErr: "cannot verify the download",
+		},
+		{
+			name:    "rejects a checksums file without the archive",
+			assets:  map[string][]byte{file.Name: archive, checksumsFile: []byte(sha256Hex(nil) + "  other.tar.gz\n")},
+			wantErr: "no checksum for",
+		},
+		{
+			name:     "rejects a failed download",
+			assets:   map[string][]byte{checksumsFile: checksums},
+			statuses: map[string]int{file.Name: http.StatusInternalServerError},
+			wantErr:  "unexpected status 500",
+		},
+		{
+			name:    "rejects path traversal",
+			assets:  map[string][]byte{file.Name: traversal, checksumsFile: []byte(sha256Hex(traversal) + "  " + file.Name + "\n")},
+			wantErr: "invalid file path",
+		},
+		{
+			name:    "rejects links",
+			assets:  map[string][]byte{file.Name: symlink, checksumsFile: []byte(sha256Hex(symlink) + "  " + file.Name + "\n")},
+			wantErr: "unsupported entry type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serveRelease(t, tt.assets, tt.statuses)
+			execPath := installedExecutable(t)
+
+			err := update(testVersion, execPath)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("update() error = %v", err)
+				}
+				assertExecutable(t, execPath, "new binary")
+
+				backups, err := ListBackups()
+				if err != nil || len(backups) != 1 {
+					t.Fatalf("backups = %v, %v, want the previous binary", backups, err)
+				}
+				if content, _ := os.ReadFile(backups[0].Path); string(content) != "old binary" {
+					t.Errorf("backup = %q, want %q", content, "old binary")
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("update() error = %v, want %q", err, tt.wantErr)
+			}
+			assertExecutable(t, execPath, "old binary")
+		})
+	}
+}
+
+func TestUpdate_Signatures(t *testing.T) {
+	if OS == "windows" {
+		t.Skip("release fixtures are tar.gz archives")
+	}
+
+	file := NewUpdateFile()
+	archive := makeTarGz(t, []archiveEntry{{name: file.Dir + "/" + file.Executable, content: "new binary"}})
+	unsigned := map[string][]byte{
+		file.Name:     archive,
+		checksumsFile: []byte(sha256Hex(archive) + "  " + file.Name + "\n"),
+	}
+	signed := map[string][]byte{
+		checksumsFile + ".sig":     []byte("MEUCIQ=="),
+		checksumsFile + ".minisig": []byte("untrusted comment: signature\n"),
+	}
+	for name, content := range unsigned {
+		signed[name] = content
+	}
+
+	tests := []struct {
+		name        string
+		assets      map[string][]byte
+		cosignKey   string
+		minisignKey string
+		installed   []string
+		verifyErr   error
+		wantVerify  string
+		wantErr     string
+	}{
+		{name: "verified signature", assets: signed, minisignKey: "RWQtest", installed: []string{"minisign"}, wantVerify: "minisign"},
+		{name: "one tool is enough", assets: signed, cosignKey: "LS0t", minisignKey: "RWQtest", installed: []string{"minisign"}, wantVerify: "minisign"},
+		{name: "invalid signature", assets: signed, minisignKey: "RWQtest", installed: []string{"minisign"}, verifyErr: errors.New("signature verification failed"), wantVerify: "minisign", wantErr: "verification"},
+		{name: "no public key in this build", assets: signed, installed: []string{"cosign", "minisign"}},
+		{name: "no tool installed", assets: signed, cosignKey: "LS0t", minisignKey: "RWQtest", wantErr: "install cosign or minisign"},
+		{name: "signatures deleted from the release", assets: unsigned, cosignKey: "LS0t", minisignKey: "RWQtest", installed: []string{"cosign", "minisign"}, wantErr: "no cosign or minisign signature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serveRelease(t, tt.assets, nil)
+			execPath := installedExecutable(t)
+
+			previousCosign, previousMinisign, previousLookPath, previousRun := CosignPublicKey, MinisignPublicKey, lookPath, runVerifier
+			t.Cleanup(func() {
+				CosignPublicKey, MinisignPublicKey, lookPath, runVerifier = previousCosign, previousMinisign, previousLookPath, previousRun
+			})
+
+			var verified []string
+			CosignPublicKey, MinisignPublicKey = tt.cosignKey, tt.minisignKey
+			lookPath = func(name string) (string, error) {
+				for _, tool := range tt.installed {
+					if tool == name {
+						return "/usr/bin/" + name, nil
+					}
+				}
+				return "", exec.ErrNotFound
+			}
+			runVerifier = func(name string, args ...string) error {
+				verified = append([]string{name}, args...)
+				return tt.verifyErr
+			}
+
+			err := update(testVersion, execPath)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("update() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("update() error = %v, want %q", err, tt.wantErr)
+			}
+
+			if tt.wantVerify == "" && verified != nil {
+				t.Fatalf("verifier called = %q, want none", verified)
+			}
+			if tt.wantVerify != "" && (verified == nil || verified[0] != tt.wantVerify) {
+				t.Fatalf("verifier called = %q, want %s", verified, tt.wantVerify)
+			}
+			if tt.wantVerify == "minisign" && (verified[2] != "-P" || verified[3] != tt.minisignKey) {
+				t.Errorf("verifier = %q", verified)
+			}
+
+			want := "new binary"
+			if tt.wantErr != "" {
+				want = "old binary"
+			}
+			assertExecutable(t, execPath, want)
+		})
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	sum := sha256Hex([]byte("archive"))
+
+	sums, err := parseChecksums(strings.NewReader(sum + "  lsh_Linux_x86_64.tar.gz\n\n" + strings.ToUpper(sum) + " *lsh_Windows_x86_64.zip\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sums["lsh_Linux_x86_64.tar.gz"] != sum || sums["lsh_Windows_x86_64.zip"] != sum {
+		t.Errorf("parseChecksums() = %v", sums)
+	}
+
+	if _, err := parseChecksums(strings.NewReader("not-a-sum lsh.tar.gz\n")); err == nil {
+		t.Error("parseChecksums() accepted a malformed line")
+	}
+}
+
+func TestPemKey(t *testing.T) {
+	pem := "-----BEGIN PUBLIC KEY-----\nMFkw\n-----END PUBLIC KEY-----\n"
+
+	if got := pemKey(pem); got != pem {
+		t.Errorf("pemKey(PEM) = %q", got)
+	}
+	if got := pemKey(base64.StdEncoding.EncodeToString([]byte(pem))); got != pem {
+		t.Errorf("pemKey(base64) = %q", got)
+	}
+}
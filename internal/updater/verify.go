@@ -0,0 +1,195 @@
+package updater
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// checksumsFile is the list of SHA-256 sums goreleaser publishes with every release
+const checksumsFile = "checksums.txt"
+
+// Public keys the release checksums are signed with, set at build time with -ldflags -X (see
+// .goreleaser.yaml). The cosign PEM key can be given base64 encoded, to fit on one line
+var (
+	CosignPublicKey   string
+	MinisignPublicKey string
+)
+
+// signatureScheme is a way the checksums file of a release can be signed
+type signatureScheme struct {
+	tool      string
+	extension string
+	publicKey func() string
+	args      func(checksums, signature, key string) []string
+}
+
+var signatureSchemes = []signatureScheme{
+	{
+		tool:      "cosign",
+		extension: ".sig",
+		publicKey: func() string { return CosignPublicKey },
+		args: func(checksums, signature, key string) []string {
+			return []string{"verify-blob", "--key", key, "--signature", signature, checksums}
+		},
+	},
+	{
+		tool:      "minisign",
+		extension: ".minisig",
+		publicKey: func() string { return MinisignPublicKey },
+		args: func(checksums, signature, key string) []string {
+			return []string{"-V", "-P", key, "-m", checksums, "-x", signature}
+		},
+	},
+}
+
+// lookPath and runVerifier run the signature tools, replaced in tests
+var (
+	lookPath    = exec.LookPath
+	runVerifier = func(name string, args ...string) error {
+		output, err := exec.Command(name, args...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+)
+
+// parseChecksums reads "<sha256>  <file name>" lines into a map keyed by file name
+func parseChecksums(r io.Reader) (map[string]string, error) {
+	sums := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 || len(fields[0]) != sha256.Size*2 {
+			return nil, fmt.Errorf("malformed %s line: %q", checksumsFile, scanner.Text())
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+
+	return sums, scanner.Err()
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyChecksum compares the SHA-256 of the archive with its entry in the checksums file
+func verifyChecksum(checksumsPath, archivePath, name string) error {
+	f, err := os.Open(checksumsPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sums, err := parseChecksums(f)
+	if err != nil {
+		return err
+	}
+
+	want, ok := sums[name]
+	if !ok {
+		return fmt.Errorf("%s has no checksum for %s", checksumsFile, name)
+	}
+
+	got, err := fileSHA256(archivePath)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want)
+	}
+
+	return nil
+}
+
+// verifySignatures checks the signatures published for the checksums file with the public keys of
+// this build. One signature verified with an installed tool is enough, but one that does not verify
+// aborts the update. A build with a key requires a signature, so deleting the signatures from a
+// release does not bypass the check; only builds without keys, such as dev builds, rely on the
+// checksums alone
+func verifySignatures(version, dir string) error {
+	checksumsPath := filepath.Join(dir, checksumsFile)
+
+	var keyed, uncheckable []string
+	for _, scheme := range signatureSchemes {
+		key := scheme.publicKey()
+		if key == "" {
+			continue
+		}
+		keyed = append(keyed, scheme.tool)
+
+		signaturePath := checksumsPath + scheme.extension
+		err := downloadFile(releaseAssetURL(version, checksumsFile+scheme.extension), signaturePath)
+		if errors.Is(err, errAssetNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := lookPath(scheme.tool); err != nil {
+			uncheckable = append(uncheckable, scheme.tool)
+			continue
+		}
+
+		keyArg := key
+		if scheme.tool == "cosign" {
+			// cosign reads the key from a file
+			keyArg = filepath.Join(dir, "cosign.pub")
+			if err := os.WriteFile(keyArg, []byte(pemKey(key)), 0600); err != nil {
+				return err
+			}
+		}
+
+		if err := runVerifier(scheme.tool, scheme.args(checksumsPath, signaturePath, keyArg)...); err != nil {
+			return fmt.Errorf("%s signature verification of %s failed: %w", scheme.tool, checksumsFile, err)
+		}
+		log.Printf("Verified %s signature of %s.", scheme.tool, checksumsFile)
+		return nil
+	}
+
+	switch {
+	case len(keyed) == 0:
+		log.Printf("This build has no signing key, relying on %s only.", checksumsFile)
+		return nil
+	case len(uncheckable) > 0:
+		return fmt.Errorf("release is signed with %s: install %s to verify it", strings.Join(uncheckable, " and "), strings.Join(uncheckable, " or "))
+	default:
+		return fmt.Errorf("release publishes no %s signature of %s, refusing to update without one", strings.Join(keyed, " or "), checksumsFile)
+	}
+}
+
+// pemKey returns key as PEM, decoding it when it was set base64 encoded
+func pemKey(key string) string {
+	if strings.HasPrefix(key, "-----BEGIN") {
+		return key
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(key); err == nil {
+		return string(decoded)
+	}
+	return key
+}
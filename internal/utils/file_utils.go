@@ -8,14 +8,23 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
 )
 
+// safeJoin joins an archive entry name to destination, refusing names that would escape it
+// such as absolute paths or "../" components (Zip Slip)
+func safeJoin(destination, name string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return "", fmt.Errorf("invalid file path in archive: %s", name)
+	}
+	return filepath.Join(destination, filepath.FromSlash(name)), nil
+}
+
 func Untar(source, destination string) error {
 	r, err := os.Open(source)
 	if err != nil {
 		return err
 	}
+	defer r.Close()
 
 	gzr, err := gzip.NewReader(r)
 	if err != nil {
@@ -40,38 +49,45 @@ func Untar(source, destination string) error {
 			continue
 		}
 
-		target := filepath.Join(destination, header.Name)
+		target, err := safeJoin(destination, header.Name)
+		if err != nil {
+			return err
+		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
-			if _, err := os.Stat(target); err != nil {
-				if err := os.MkdirAll(target, 0755); err != nil {
-					return err
-				}
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
 			}
 		case tar.TypeReg:
-			fileDir := filepath.Dir(target)
-
-			if err := os.MkdirAll(fileDir, 0770); err != nil {
-				return fmt.Errorf("untar failed while creating folder: %s", err.Error())
+			if err := untarFile(tr, target); err != nil {
+				return err
 			}
-			outFile, err := os.Create(target)
-			if err != nil {
-				return fmt.Errorf("untar failed while creating file: %s", err.Error())
-			}
-			err = os.Chmod(outFile.Name(), 0755)
-			if err != nil {
-				return fmt.Errorf("untar failed, permission error: %s", err.Error())
-			}
-			if _, err := io.Copy(outFile, tr); err != nil {
-				return fmt.Errorf("untar failed while copying data to file: %s", err.Error())
-
-			}
-			outFile.Close()
+		default:
+			// Links could point outside of destination
+			return fmt.Errorf("unsupported entry type in archive: %s", header.Name)
 		}
 	}
 }
 
+func untarFile(tr *tar.Reader, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0770); err != nil {
+		return fmt.Errorf("untar failed while creating folder: %s", err.Error())
+	}
+
+	outFile, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("untar failed while creating file: %s", err.Error())
+	}
+	defer outFile.Close()
+
+	if _, err := io.Copy(outFile, tr); err != nil {
+		return fmt.Errorf("untar failed while copying data to file: %s", err.Error())
+	}
+
+	return outFile.Close()
+}
+
 func Unzip(source, destination string) error {
 	reader, err := zip.OpenReader(source)
 	if err != nil {
@@ -96,9 +112,9 @@ func Unzip(source, destination string) error {
 
 func unzipFile(f *zip.File, destination string) error {
 	// Check if file paths are not vulnerable to Zip Slip
-	filePath := filepath.Join(destination, f.Name)
-	if !strings.HasPrefix(filePath, filepath.Clean(destination)+string(os.PathSeparator)) {
-		return fmt.Errorf("invalid file path: %s", filePath)
+	filePath, err := safeJoin(destination, f.Name)
+	if err != nil {
+		return err
 	}
 
 	// Create directory tree
@@ -109,6 +125,10 @@ func unzipFile(f *zip.File, destination string) error {
 		return nil
 	}
 
+	if !f.Mode().IsRegular() {
+		return fmt.Errorf("unsupported entry type in archive: %s", f.Name)
+	}
+
 	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
 		return err
 	}
@@ -130,5 +150,5 @@ func unzipFile(f *zip.File, destination string) error {
 	if _, err := io.Copy(destinationFile, zippedFile); err != nil {
 		return err
 	}
-	return nil
+	return destinationFile.Close()
 }
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	watchFlagName = "watch"
+	// DefaultWatchInterval is used by a bare --watch
+	DefaultWatchInterval = 5 * time.Second
+	// minWatchInterval keeps a watch from hammering the API
+	minWatchInterval = time.Second
+)
+
+// RegisterWatchFlag adds --watch [interval] to a list command. A bare --watch polls every
+// DefaultWatchInterval
+func RegisterWatchFlag(cmd *cobra.Command) {
+	cmd.Flags().String(watchFlagName, "", fmt.Sprintf("Refresh the list every interval until interrupted, such as --watch 10s (default %s)", DefaultWatchInterval))
+	cmd.Flags().Lookup(watchFlagName).NoOptDefVal = DefaultWatchInterval.String()
+}
+
+// WatchInterval returns the --watch interval, or 0 when the list is not watched. As the interval
+// is optional, "--watch 10s" leaves 10s in args, so a single interval argument is taken as the
+// interval of a bare --watch
+func WatchInterval(cmd *cobra.Command, args []string) (time.Duration, error) {
+	flag := cmd.Flags().Lookup(watchFlagName)
+	if flag == nil || !flag.Changed {
+		return 0, nil
+	}
+
+	value := flag.Value.String()
+	if value == flag.NoOptDefVal && len(args) == 1 {
+		if _, err := parseWatchInterval(args[0]); err == nil {
+			value = args[0]
+		}
+	}
+
+	interval, err := parseWatchInterval(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --watch interval %q, use a duration such as 10s or 1m", value)
+	}
+	if interval < minWatchInterval {
+		return 0, fmt.Errorf("--watch interval must be at least %s, got %s", minWatchInterval, interval)
+	}
+
+	return interval, nil
+}
+
+// parseWatchInterval parses a duration, a plain number being seconds
+func parseWatchInterval(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return time.ParseDuration(value)
+}
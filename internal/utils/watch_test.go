@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWatchInterval(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    time.Duration
+		wantErr bool
+	}{
+		{args: []string{}, want: 0},
+		{args: []string{"--watch"}, want: DefaultWatchInterval},
+		{args: []string{"--watch=30s"}, want: 30 * time.Second},
+		{args: []string{"--watch", "1m"}, want: time.Minute},
+		{args: []string{"--watch", "10"}, want: 10 * time.Second},
+		{args: []string{"--watch=100ms"}, wantErr: true},
+		{args: []string{"--watch=soon"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var got time.Duration
+		var err error
+		cmd := &cobra.Command{
+			Use: "list",
+			Run: func(cmd *cobra.Command, args []string) {
+				got, err = WatchInterval(cmd, args)
+			},
+		}
+		RegisterWatchFlag(cmd)
+		cmd.SetArgs(tt.args)
+		if execErr := cmd.Execute(); execErr != nil {
+			t.Fatalf("%v: %v", tt.args, execErr)
+		}
+
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%v: error = %v, wantErr %v", tt.args, err, tt.wantErr)
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("%v: interval = %s, want %s", tt.args, got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,22 @@
 package main
 
 import (
+	"errors"
+	"os"
+
+	"github.com/latitudesh/lsh/cli"
 	"github.com/latitudesh/lsh/cmd"
 )
 
 func main() {
-	cmd.Execute()
+	_, err := cmd.Execute()
+
+	var exitErr *cli.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		os.Exit(exitErr.Code)
+	case err != nil:
+		// The error was already printed by cobra
+		os.Exit(1)
+	}
 }